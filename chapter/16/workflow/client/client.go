@@ -1,28 +1,49 @@
 /*
 Package client provides access to the workflow service. You can use this client to:
+
 	Submit a *pb.WorkReq to the service
+	Plan a *pb.WorkReq to see what it would do without submitting it
+	Approve a *pb.WorkReq previously submitted
 	Execute a *pb.WorkReq previously submitted
 	Get the status of a *pb.WorkReq
+	Watch the status of a *pb.WorkReq as it changes
+	Cancel, Pause or Resume a running *pb.WorkReq
+	List the *pb.WorkReq(s) stored on the service
+	Audit a *pb.WorkReq, which shows who did what to it and when
+	Get, List or Set the emergency stop status of workflow types
 
 See the README.md in the root workflow/ directory for more information.
 
-SECURITY NOTICE: As this is an example for a book and is meant to be run in a secure environment, we
-use grpc.WithInsecure().  Aka, not production ready.
+SECURITY NOTICE: By default we use grpc.WithInsecure(), as this is an example for a book and is meant
+to be run in a secure environment. If the server uses mTLS, connect with:
+	conf, err := client.TLSConfig(certFile, keyFile, caFile)
+	if err != nil {
+		// Do something
+	}
+	c, err := client.New(addr, client.WithTLS(conf))
+
+The Common Name of the client's certificate is the identity the server uses to authorize the caller.
 */
 package client
 
 import (
 	"context"
+	"crypto/tls"
+	"io"
 	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff"
 	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
@@ -35,14 +56,54 @@ import (
 type Workflow struct {
 	conn   *grpc.ClientConn
 	client pb.WorkflowClient
+	es     pb.EmergencyStopClient
+	tb     pb.TokenBucketsClient
 
 	cb        *gobreaker.CircuitBreaker
 	retryPool sync.Pool
 }
 
+// Option is an optional argument to New().
+type Option func(o *options)
+
+type options struct {
+	tlsConf *tls.Config
+}
+
+// WithTLS connects to the server using TLS with "conf" instead of an insecure connection.
+// For mTLS, "conf" must contain the client's certificate, see TLSConfig().
+func WithTLS(conf *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConf = conf
+	}
+}
+
+// TLSConfig returns a *tls.Config for use with WithTLS(). The client presents the certificate in
+// "certFile" and verifies the server's certificate was signed by the CA in "caFile".
+func TLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	return auth.ClientConfig(certFile, keyFile, caFile)
+}
+
 // New creates a new Workflow instance.
-func New(addr string) (*Workflow, error) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+func New(addr string, opts ...Option) (*Workflow, error) {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	creds := grpc.WithInsecure()
+	if o.tlsConf != nil {
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(o.tlsConf))
+	}
+
+	// If the caller is tracing, the trace is sent to the server so that its spans are part of it.
+	prop := otelgrpc.WithPropagators(propagation.TraceContext{})
+	conn, err := grpc.Dial(
+		addr,
+		creds,
+		grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(prop)),
+		grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(prop)),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +111,8 @@ func New(addr string) (*Workflow, error) {
 	return &Workflow{
 		conn:   conn,
 		client: pb.NewWorkflowClient(conn),
+		es:     pb.NewEmergencyStopClient(conn),
+		tb:     pb.NewTokenBucketsClient(conn),
 		cb: gobreaker.NewCircuitBreaker(
 			gobreaker.Settings{
 				MaxRequests: 1,
@@ -89,6 +152,90 @@ func (w *Workflow) Submit(ctx context.Context, req *pb.WorkReq) (string, error)
 	return resp.(*pb.WorkResp).Id, nil
 }
 
+// SubmitTemplate asks the server to build a pb.WorkReq from the template called "name" and
+// submit it. "params" are the template's parameters by name. Like Submit(), this returns the
+// ID of the pb.WorkReq on the server.
+func (w *Workflow) SubmitTemplate(ctx context.Context, name string, params map[string]string) (string, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.SubmitTemplateReq)
+		return w.client.SubmitTemplate(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.SubmitTemplateReq{Template: name, Params: params}, caller)
+	if err != nil {
+		return "", err
+	}
+	return resp.(*pb.WorkResp).Id, nil
+}
+
+// ListTemplates lists the templates registered on the server and the parameters they take.
+func (w *Workflow) ListTemplates(ctx context.Context) ([]*pb.TemplateInfo, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ListTemplatesReq)
+		return w.client.ListTemplates(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ListTemplatesReq{}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListTemplatesResp).Templates, nil
+}
+
+// ListJobs lists the Jobs registered on the server and the args they take.
+func (w *Workflow) ListJobs(ctx context.Context) ([]*pb.JobSchema, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ListJobsReq)
+		return w.client.ListJobs(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ListJobsReq{}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListJobsResp).Jobs, nil
+}
+
+// ListPolicies lists the policies registered on the server and the settings they take.
+func (w *Workflow) ListPolicies(ctx context.Context) ([]*pb.PolicySchema, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ListPoliciesReq)
+		return w.client.ListPolicies(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ListPoliciesReq{}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListPoliciesResp).Policies, nil
+}
+
+// Plan asks the server what would happen if the pb.WorkReq was submitted and executed now.
+// Nothing is stored or executed on the server. If the pb.WorkReq would not be accepted or could
+// not be executed, PlanResp.Ok is false and PlanResp.Errors lists why.
+func (w *Workflow) Plan(ctx context.Context, req *pb.WorkReq) (*pb.PlanResp, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.WorkReq)
+		return w.client.Plan(ctx, r)
+	}
+	resp, err := w.call(ctx, req, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.PlanResp), nil
+}
+
+// Approve approves a pb.WorkReq that was previously submitted, with an optional comment.
+// Workflows that require approvals can only be executed once approved by enough identities
+// other than the submitter. This returns all approvals the pb.WorkReq has received.
+func (w *Workflow) Approve(ctx context.Context, id, comment string) ([]*pb.Approval, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ApproveReq)
+		return w.client.Approve(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ApproveReq{Id: id, Comment: comment}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ApproveResp).Approvals, nil
+}
+
 // Exec causes the server to execute a pb.WorkReq that was previously accepted by the server
 // via a Submit() call.
 func (w *Workflow) Exec(ctx context.Context, id string) error {
@@ -117,6 +264,192 @@ func (w *Workflow) Status(ctx context.Context, id string) (*pb.StatusResp, error
 	return resp.(*pb.StatusResp), nil
 }
 
+// Update is a status update sent by Watch().
+type Update struct {
+	// Status is the status of the pb.WorkReq. This is nil if Err is set.
+	Status *pb.StatusResp
+	// Err is set if the stream from the server had an error. This is always
+	// the last Update sent.
+	Err error
+}
+
+// Watch returns a channel that receives the status of a pb.WorkReq each time it changes on
+// the server. The channel is closed after the final status of the pb.WorkReq is sent, the context
+// is cancelled or an Update with an error is sent. If the pb.WorkReq has already finished, the final
+// status is sent once. Unlike other methods, Watch() does not retry.
+func (w *Workflow) Watch(ctx context.Context, id string) (chan Update, error) {
+	stream, err := w.client.Watch(ctx, &pb.StatusReq{Id: id})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Update, 1)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF {
+					return
+				}
+				select {
+				case ch <- Update{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			select {
+			case ch <- Update{Status: resp}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Cancel cancels a pb.WorkReq that is currently executing on the server.
+func (w *Workflow) Cancel(ctx context.Context, id string) error {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.CancelReq)
+		return w.client.Cancel(ctx, r)
+	}
+	_, err := w.call(ctx, &pb.CancelReq{Id: id}, caller)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Pause pauses a pb.WorkReq that is currently executing on the server. Jobs that are
+// already running will complete, but no new Blocks or Jobs will start until Resume() is called.
+func (w *Workflow) Pause(ctx context.Context, id string) error {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.PauseReq)
+		return w.client.Pause(ctx, r)
+	}
+	_, err := w.call(ctx, &pb.PauseReq{Id: id}, caller)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Resume resumes a pb.WorkReq that was paused with Pause().
+func (w *Workflow) Resume(ctx context.Context, id string) error {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ResumeReq)
+		return w.client.Resume(ctx, r)
+	}
+	_, err := w.call(ctx, &pb.ResumeReq{Id: id}, caller)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// List lists the pb.WorkReq(s) on the server that match the filters in "req". If the
+// pb.ListResp has a NextPageToken, pass it as the PageToken in another List() call with the
+// same filters to get the next page.
+func (w *Workflow) List(ctx context.Context, req *pb.ListReq) (*pb.ListResp, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ListReq)
+		return w.client.List(ctx, r)
+	}
+	resp, err := w.call(ctx, req, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListResp), nil
+}
+
+// Audit returns the audit records for the pb.WorkReq with "id", oldest first. This includes
+// every RPC that referenced the pb.WorkReq and every change in its status.
+func (w *Workflow) Audit(ctx context.Context, id string) ([]*pb.AuditRecord, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.AuditReq)
+		return w.client.Audit(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.AuditReq{Id: id}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuditResp).Records, nil
+}
+
+// ESSetStatus sets the emergency stop status of the entry described by "info". info.Name is
+// the pb.WorkReq.Name of the workflow type. If info.Site, info.Machine and info.Job are not set,
+// setting pb.ESStatus_ESStop stops running workflows of that type. Otherwise only the matching
+// Jobs are stopped.
+func (w *Workflow) ESSetStatus(ctx context.Context, info *pb.ESInfo) error {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ESSetStatusReq)
+		return w.es.SetStatus(ctx, r)
+	}
+	req := &pb.ESSetStatusReq{
+		Name:    info.Name,
+		Status:  info.Status,
+		Site:    info.Site,
+		Machine: info.Machine,
+		Job:     info.Job,
+	}
+	_, err := w.call(ctx, req, caller)
+	return err
+}
+
+// ESGetStatus gets the emergency stop status of the workflow type "name".
+func (w *Workflow) ESGetStatus(ctx context.Context, name string) (*pb.ESInfo, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ESGetStatusReq)
+		return w.es.GetStatus(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ESGetStatusReq{Name: name}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ESGetStatusResp).Info, nil
+}
+
+// ESList lists the emergency stop status of all workflow types.
+func (w *Workflow) ESList(ctx context.Context) ([]*pb.ESInfo, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.ESListReq)
+		return w.es.List(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.ESListReq{}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ESListResp).Infos, nil
+}
+
+// TBSet drains or refills the token bucket "name", depending on "action". It returns the bucket
+// after the change.
+func (w *Workflow) TBSet(ctx context.Context, name string, action pb.TBAction) (*pb.TBInfo, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.TBSetReq)
+		return w.tb.Set(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.TBSetReq{Name: name, Action: action}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.TBSetResp).Info, nil
+}
+
+// TBList lists all token buckets.
+func (w *Workflow) TBList(ctx context.Context) ([]*pb.TBInfo, error) {
+	caller := func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		r := req.(*pb.TBListReq)
+		return w.tb.List(ctx, r)
+	}
+	resp, err := w.call(ctx, &pb.TBListReq{}, caller)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.TBListResp).Infos, nil
+}
+
 type grpcCall = func(context.Context, proto.Message) (proto.Message, error)
 
 // call generically calls any non-streaming gRPC endpoint that is contained within "call".
@@ -0,0 +1,249 @@
+/*
+Package audit provides an append-only audit log for the workflow service. Each Record is
+written as a single line of JSON, which makes the log easy to ship to other systems and
+to read with tools like jq.
+
+The log records every RPC made to the service and every change in the status of a
+WorkReq, Block or Job. Use the interceptors after the auth interceptors so that the
+caller's identity is recorded:
+
+	l, err := audit.New(path)
+	if err != nil {
+		// Do something
+	}
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.UnaryInterceptor, l.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(auth.StreamInterceptor, l.StreamInterceptor),
+	)
+*/
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Kind is the kind of Record.
+type Kind string
+
+const (
+	// KindRPC records an RPC call.
+	KindRPC Kind = "rpc"
+	// KindWork records a change in the status of a WorkReq.
+	KindWork Kind = "work"
+	// KindBlock records a change in the status of a Block.
+	KindBlock Kind = "block"
+	// KindJob records a change in the status of a Job.
+	KindJob Kind = "job"
+)
+
+// Record is a single entry in the audit log.
+type Record struct {
+	// Time is when this happened. If not set, Write() sets it to the current time.
+	Time time.Time `json:"time"`
+	// Kind is the kind of Record.
+	Kind Kind `json:"kind"`
+	// ID is the ID of the WorkReq. It is empty for RPCs that do not refer to a single WorkReq.
+	ID string `json:"id,omitempty"`
+	// Method is the full gRPC method name for KindRPC.
+	Method string `json:"method,omitempty"`
+	// Caller is the identity of the caller for KindRPC.
+	Caller string `json:"caller,omitempty"`
+	// Code is the gRPC status code returned for KindRPC.
+	Code string `json:"code,omitempty"`
+	// Error is the error returned by the RPC or recorded with the status.
+	Error string `json:"error,omitempty"`
+	// Block is the index of the Block for KindBlock and KindJob.
+	Block int `json:"block,omitempty"`
+	// Job is the index of the Job in the Block for KindJob.
+	Job int `json:"job,omitempty"`
+	// From is the status before the change.
+	From string `json:"from,omitempty"`
+	// To is the status after the change.
+	To string `json:"to,omitempty"`
+	// WasEsStopped is set for KindWork if the WorkReq was stopped with emergency stop.
+	WasEsStopped bool `json:"wasEsStopped,omitempty"`
+}
+
+// Proto converts the Record to a *pb.AuditRecord.
+func (r Record) Proto() *pb.AuditRecord {
+	return &pb.AuditRecord{
+		Time:         timestamppb.New(r.Time),
+		Kind:         string(r.Kind),
+		Id:           r.ID,
+		Method:       r.Method,
+		Caller:       r.Caller,
+		Code:         r.Code,
+		Error:        r.Error,
+		Block:        int32(r.Block),
+		Job:          int32(r.Job),
+		From:         pb.Status(pb.Status_value[r.From]),
+		To:           pb.Status(pb.Status_value[r.To]),
+		WasEsStopped: r.WasEsStopped,
+	}
+}
+
+// Log is an append-only audit log stored in a file.
+type Log struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+// New opens the audit log at "path", creating it if it does not exist.
+func New(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log(%s): %w", path, err)
+	}
+	return &Log{path: path, f: f}, nil
+}
+
+// Write appends "r" to the log.
+func (l *Log) Write(r Record) error {
+	if r.Time.IsZero() {
+		r.Time = time.Now()
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("could not marshal audit record: %w", err)
+	}
+	b = append(b, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.f.Write(b); err != nil {
+		return fmt.Errorf("could not write audit record: %w", err)
+	}
+	return nil
+}
+
+// Record writes "r" to the log. If that fails, the error is logged, as failing to audit should
+// not fail the work being audited.
+func (l *Log) Record(r Record) {
+	if err := l.Write(r); err != nil {
+		log.Printf("audit log(%s): %s", l.path, err)
+	}
+}
+
+// Query returns all Records for the WorkReq with "id", oldest first.
+func (l *Log) Query(ctx context.Context, id string) ([]Record, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open audit log(%s): %w", l.path, err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		r := Record{}
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			// A partial line can be left by a crash. That shouldn't prevent reading the rest.
+			log.Printf("audit log(%s) line %d is corrupted, skipping: %s", l.path, line, err)
+			continue
+		}
+		if r.ID == id {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read audit log(%s): %w", l.path, err)
+	}
+	return records, nil
+}
+
+// Close closes the log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// ider is implemented by requests and responses that have the ID of a WorkReq.
+type ider interface {
+	GetId() string
+}
+
+// UnaryInterceptor is a grpc.UnaryServerInterceptor that records each RPC.
+func (l *Log) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+
+	// Submit() and SubmitTemplate() don't have an ID until the response.
+	id := idOf(req)
+	if id == "" && err == nil {
+		id = idOf(resp)
+	}
+	l.Record(rpcRecord(ctx, info.FullMethod, id, err))
+	return resp, err
+}
+
+// StreamInterceptor is a grpc.StreamServerInterceptor that records each RPC.
+func (l *Log) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	as := &auditStream{ServerStream: ss}
+	err := handler(srv, as)
+	l.Record(rpcRecord(ss.Context(), info.FullMethod, as.id, err))
+	return err
+}
+
+// auditStream wraps a grpc.ServerStream to record the ID of the WorkReq from the request.
+type auditStream struct {
+	grpc.ServerStream
+	id string
+}
+
+// RecvMsg implements grpc.ServerStream.RecvMsg().
+func (a *auditStream) RecvMsg(m interface{}) error {
+	err := a.ServerStream.RecvMsg(m)
+	if err == nil && a.id == "" {
+		a.id = idOf(m)
+	}
+	return err
+}
+
+func idOf(m interface{}) string {
+	if i, ok := m.(ider); ok {
+		return i.GetId()
+	}
+	return ""
+}
+
+func rpcRecord(ctx context.Context, method, id string, err error) Record {
+	s, ok := status.FromError(err)
+	if !ok {
+		// This turns errors such as the caller going away into codes.Canceled instead of codes.Unknown.
+		s = status.FromContextError(err)
+	}
+	r := Record{
+		Kind:   KindRPC,
+		ID:     id,
+		Method: method,
+		Caller: auth.Identity(ctx),
+		Code:   s.Code().String(),
+	}
+	if err != nil {
+		r.Error = err.Error()
+		if ok {
+			r.Error = s.Message()
+		}
+	}
+	return r
+}
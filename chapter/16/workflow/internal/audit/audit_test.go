@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestLog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := New(path)
+	if err != nil {
+		t.Fatalf("TestLog: New() had error: %s", err)
+	}
+
+	now := time.Now().UTC()
+	records := []Record{
+		{Time: now, Kind: KindRPC, ID: "a", Method: "/workflow.Workflow/Exec", Caller: "alice", Code: "OK"},
+		{Time: now, Kind: KindRPC, ID: "b", Method: "/workflow.Workflow/Exec", Caller: "bob", Code: "OK"},
+		{Time: now, Kind: KindJob, ID: "a", Block: 1, Job: 2, From: "StatusRunning", To: "StatusFailed", Error: "bad"},
+	}
+	for _, r := range records {
+		if err := l.Write(r); err != nil {
+			t.Fatalf("TestLog: Write() had error: %s", err)
+		}
+	}
+	l.Close()
+
+	// A crash can leave a partial line, which should be skipped. Records written after
+	// reopening the log should be appended.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"` + "\n")
+	f.Close()
+
+	l, err = New(path)
+	if err != nil {
+		t.Fatalf("TestLog: New() on existing log had error: %s", err)
+	}
+	defer l.Close()
+	last := Record{Time: now, Kind: KindWork, ID: "a", From: "StatusRunning", To: "StatusFailed", WasEsStopped: true}
+	if err := l.Write(last); err != nil {
+		t.Fatalf("TestLog: Write() had error: %s", err)
+	}
+
+	got, err := l.Query(ctx, "a")
+	if err != nil {
+		t.Fatalf("TestLog: Query() had error: %s", err)
+	}
+	want := []Record{records[0], records[2], last}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestLog: Query(): -want/+got:\n%s", diff)
+	}
+
+	p := got[1].Proto()
+	if p.From != pb.Status_StatusRunning || p.To != pb.Status_StatusFailed || p.Block != 1 || p.Job != 2 {
+		t.Errorf("TestLog: Proto(): got %v, want From/To/Block/Job to match %v", p, got[1])
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	l, err := New(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatalf("TestUnaryInterceptor: New() had error: %s", err)
+	}
+	defer l.Close()
+
+	ctx := auth.WithIdentity(context.Background(), "alice")
+
+	// Submit() only has the ID in the response.
+	info := &grpc.UnaryServerInfo{FullMethod: "/workflow.Workflow/Submit"}
+	_, err = l.UnaryInterceptor(ctx, &pb.WorkReq{}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &pb.WorkResp{Id: "a"}, nil
+	})
+	if err != nil {
+		t.Fatalf("TestUnaryInterceptor: Submit had error: %s", err)
+	}
+
+	info = &grpc.UnaryServerInfo{FullMethod: "/workflow.Workflow/Exec"}
+	wantErr := status.Errorf(codes.PermissionDenied, "no")
+	_, err = l.UnaryInterceptor(ctx, &pb.ExecReq{Id: "a"}, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("TestUnaryInterceptor: Exec: got err == %v, want %v", err, wantErr)
+	}
+
+	got, err := l.Query(context.Background(), "a")
+	if err != nil {
+		t.Fatalf("TestUnaryInterceptor: Query() had error: %s", err)
+	}
+	for i := range got {
+		got[i].Time = time.Time{}
+	}
+	want := []Record{
+		{Kind: KindRPC, ID: "a", Method: "/workflow.Workflow/Submit", Caller: "alice", Code: "OK"},
+		{Kind: KindRPC, ID: "a", Method: "/workflow.Workflow/Exec", Caller: "alice", Code: "PermissionDenied", Error: "no"},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestUnaryInterceptor: -want/+got:\n%s", diff)
+	}
+}
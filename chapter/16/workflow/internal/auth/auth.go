@@ -0,0 +1,152 @@
+/*
+Package auth provides mTLS configuration for the workflow service and its clients and
+records the identity of callers in the Context of each RPC.
+
+A caller's identity is the Common Name of the certificate it presented. Use the
+interceptors when creating the gRPC server:
+	g := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(conf)),
+		grpc.UnaryInterceptor(auth.UnaryInterceptor),
+		grpc.StreamInterceptor(auth.StreamInterceptor),
+	)
+
+Handlers can then retrieve the identity with Identity(ctx). Handlers record what the caller
+is trying to do with WithOperation() so that policies can authorize it.
+*/
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Operation is an operation a caller is trying to perform on a workflow.
+type Operation string
+
+const (
+	// OpUnknown indicates the operation was not recorded.
+	OpUnknown Operation = ""
+	// OpSubmit is submitting a WorkReq.
+	OpSubmit Operation = "submit"
+	// OpExec is executing a WorkReq.
+	OpExec Operation = "exec"
+	// OpPlan is planning a WorkReq, which previews both submitting and executing it.
+	OpPlan Operation = "plan"
+)
+
+// ErrPermissionDenied is wrapped by errors that indicate the caller is not allowed to
+// perform an Operation.
+var ErrPermissionDenied = errors.New("permission denied")
+
+type identityKey struct{}
+type opKey struct{}
+
+// Identity returns the identity of the caller. If the caller did not present a certificate,
+// such as when the server does not use TLS, this returns "".
+func Identity(ctx context.Context) string {
+	id, _ := ctx.Value(identityKey{}).(string)
+	return id
+}
+
+// WithIdentity returns a Context that records the caller's identity.
+func WithIdentity(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// OperationFrom returns the Operation recorded in the Context.
+func OperationFrom(ctx context.Context) Operation {
+	op, _ := ctx.Value(opKey{}).(Operation)
+	return op
+}
+
+// WithOperation returns a Context that records the Operation the caller is performing.
+func WithOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, opKey{}, op)
+}
+
+// UnaryInterceptor is a grpc.UnaryServerInterceptor that records the caller's identity.
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(fromPeer(ctx), req)
+}
+
+// StreamInterceptor is a grpc.StreamServerInterceptor that records the caller's identity.
+func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &identityStream{ServerStream: ss, ctx: fromPeer(ss.Context())})
+}
+
+// identityStream wraps a grpc.ServerStream to replace its Context.
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context implements grpc.ServerStream.Context().
+func (i *identityStream) Context() context.Context {
+	return i.ctx
+}
+
+// fromPeer returns a Context with the identity from the peer's verified certificate.
+func fromPeer(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	return WithIdentity(ctx, tlsInfo.State.VerifiedChains[0][0].Subject.CommonName)
+}
+
+// ServerConfig returns a *tls.Config for a server that requires clients to present a
+// certificate signed by the CA in "caFile".
+func ServerConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, pool, err := load(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientConfig returns a *tls.Config for a client that presents the certificate in "certFile" and
+// verifies the server's certificate was signed by the CA in "caFile".
+func ClientConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, pool, err := load(certFile, keyFile, caFile)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func load(certFile, keyFile, caFile string) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("could not load certificate(%s) and key(%s): %w", certFile, keyFile, err)
+	}
+	b, err := os.ReadFile(caFile)
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("could not read CA file(%s): %w", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return tls.Certificate{}, nil, fmt.Errorf("CA file(%s) did not contain any PEM certificates", caFile)
+	}
+	return cert, pool, nil
+}
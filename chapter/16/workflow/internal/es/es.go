@@ -1,8 +1,29 @@
 /*
-Package es contains an emergency stop implementation. This data is read from es.json file
-every 10 seconds. If the data changes, subscribers will receive an update.
+Package es contains an emergency stop implementation. This data is read from an es.json file
+as soon as it changes, with a check for changes every 10 seconds in case change notifications
+are not available. If the data changes, subscribers will receive an update. Each reload is
+logged, so operators can tell if an edit to the file was accepted.
+
+Entries can also be changed with Data.SetStatus(), which writes them to es.json and sends
+Stop to subscribers immediately instead of waiting for the next read. This is used by the
+EmergencyStop gRPC service.
+
+Entries in es.json can also be scoped to Jobs that act on a site, a machine or that have a
+certain name. A scoped entry set to stop does not stop the workflow, instead the executor
+fails matching Jobs before they run with Data.Stopped(). This es.json stops erasing disks
+only at site "aba":
+	{
+		"Name": "SatelliteDiskErase",
+		"Status": "go"
+	}
+	{
+		"Name": "SatelliteDiskErase",
+		"Status": "stop",
+		"Site": "aba",
+		"Job": "diskErase"
+	}
 
-Using this is simple:
+Call Init() in main with the path to es.json before using Data. Using this is simple:
 	ch, cancel := es.Data.Subscribe("SatelliteDiskErase")
 	defer cancel()
 
@@ -18,21 +39,33 @@ Using this is simple:
 package es
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/watch"
 )
 
+// pollInterval is how often we check es.json for changes in case we miss a change notification.
+const pollInterval = 10 * time.Second
+
 // Data is how to access the emergency stop information.
 var Data *Reader
 
-func init() {
-	d, err := newReader()
+// Init is called in main to initialize our reads of the es.json file at "path". It is called
+// manually instead of init() so that importing this package does not require the file.
+func Init(path string) {
+	d, err := NewReader(path)
 	if err != nil {
 		panic(err)
 	}
@@ -53,45 +86,141 @@ const (
 )
 
 // Info is the emergency stop information for a particular entry in our es.json file.
+// If Site, Machine or Job are set, the entry is scoped to the Jobs they match.
 type Info struct {
 	// Name is the WorkReq type.
 	Name string
 	// Status is the emergency stop status.
 	Status Status
+	// Site, if set, scopes the entry to Jobs that act on this site.
+	Site string `json:",omitempty"`
+	// Machine, if set, scopes the entry to Jobs that act on a machine that matches this
+	// pattern. The pattern uses path.Match() syntax, such as "aba-*".
+	Machine string `json:",omitempty"`
+	// Job, if set, scopes the entry to Jobs with this name.
+	Job string `json:",omitempty"`
+}
+
+// Scoped returns true if the entry only applies to some Jobs.
+func (i Info) Scoped() bool {
+	return i.Site != "" || i.Machine != "" || i.Job != ""
+}
+
+// Scope returns a description of the Jobs a scoped entry applies to.
+func (i Info) Scope() string {
+	var l []string
+	if i.Site != "" {
+		l = append(l, fmt.Sprintf("site(%s)", i.Site))
+	}
+	if i.Machine != "" {
+		l = append(l, fmt.Sprintf("machine(%s)", i.Machine))
+	}
+	if i.Job != "" {
+		l = append(l, fmt.Sprintf("job(%s)", i.Job))
+	}
+	return strings.Join(l, " ")
+}
+
+// key returns the key of the entry in our entries map. Unscoped entries use their Name, so
+// they can be looked up by the WorkReq name.
+func (i Info) key() string {
+	if !i.Scoped() {
+		return i.Name
+	}
+	return strings.Join([]string{i.Name, i.Site, i.Machine, i.Job}, "\x00")
+}
+
+// matches returns true if a Job named "job" that acts on "sites" and "machines" is in the
+// scope of the entry.
+func (i Info) matches(job string, sites, machines []string) bool {
+	if i.Job != "" && i.Job != job {
+		return false
+	}
+	if i.Site != "" && !contains(sites, i.Site) {
+		return false
+	}
+	if i.Machine != "" {
+		for _, m := range machines {
+			// Patterns are checked by validate(), so this cannot return an error.
+			if ok, _ := path.Match(i.Machine, m); ok {
+				return true
+			}
+		}
+		return false
+	}
+	return true
 }
 
 func (i Info) validate() error {
-	i.Name = strings.TrimSpace(i.Name)
-	if i.Name == "" {
-		return fmt.Errorf("es.json: rule with empty name, ignored")
+	if strings.TrimSpace(i.Name) == "" {
+		return fmt.Errorf("es.json: rule with empty name")
 	}
 	switch i.Status {
 	case "go", "stop":
 	default:
-		return fmt.Errorf("es.json: rule(%s) has invalid Status(%s), ignored", i.Name, i.Status)
+		return fmt.Errorf("es.json: rule(%s) has invalid Status(%s)", i.Name, i.Status)
+	}
+	if _, err := path.Match(i.Machine, ""); err != nil {
+		return fmt.Errorf("es.json: rule(%s) has invalid Machine pattern(%s): %s", i.Name, i.Machine, err)
 	}
 	return nil
 }
 
-// Reader reads the es.json file at intervals and makes the data and changes to the data
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Reader reads the es.json file when it changes and makes the data and changes to the data
 // available.
 type Reader struct {
-	entries atomic.Value // map[string]Info
+	// path is the location of the es.json file.
+	path string
+	// watcher calls reload() when the file changes. Set by NewReader().
+	watcher *watch.Watcher
+
+	// updateMu prevents concurrent changes to entries and the es.json file.
+	updateMu sync.Mutex
+	entries  atomic.Value // map[string]Info
 
 	mu          sync.Mutex
 	subscribers map[string][]chan Status
 }
 
-func newReader() (*Reader, error) {
-	r := &Reader{subscribers: map[string][]chan Status{}}
+// NewReader returns a Reader for the es.json file at "path" that reloads the file when it changes.
+func NewReader(path string) (*Reader, error) {
+	r, err := newReader(path)
+	if err != nil {
+		return nil, err
+	}
+	r.watcher, err = watch.New(path, pollInterval, r.reload)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Close stops watching the es.json file for changes.
+func (r *Reader) Close() error {
+	if r.watcher == nil {
+		return nil
+	}
+	return r.watcher.Close()
+}
+
+// newReader returns a Reader for the es.json file at "path" that does not watch for changes.
+func newReader(path string) (*Reader, error) {
+	r := &Reader{path: path, subscribers: map[string][]chan Status{}}
 
 	m, err := r.load()
 	if err != nil {
 		return nil, err
 	}
 	r.entries.Store(m)
-
-	go r.loop()
 	return r, nil
 }
 
@@ -104,6 +233,10 @@ type Cancel func()
 // be closed once this is sent. Once you either receive a Stop or are no longer interested
 // in listening, simply call Cancel().
 func (r *Reader) Subscribe(name string) (chan Status, Cancel) {
+	// We hold the lock while checking the status so that we can't miss a transition to Stop.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	i, ok := r.entries.Load().(map[string]Info)[name]
 	if !ok || i.Status != Go {
 		ch := make(chan Status, 1)
@@ -112,28 +245,27 @@ func (r *Reader) Subscribe(name string) (chan Status, Cancel) {
 		return ch, func() {}
 	}
 
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	ch := make(chan Status, 1)
 	ch <- Go
 
-	l := r.subscribers[name]
-	l = append(l, ch)
-	r.subscribers[name] = l
+	r.subscribers[name] = append(r.subscribers[name], ch)
 
 	// This removes the channel when it is no longer needed because no
 	// one is listening.
 	cancel := func() {
 		r.mu.Lock()
 		defer r.mu.Unlock()
-		l := make([]chan Status, 0, len(r.subscribers[name])-1)
+		var l []chan Status
 		for _, stored := range r.subscribers[name] {
 			if stored == ch {
 				continue
 			}
 			l = append(l, stored)
 		}
+		if len(l) == 0 {
+			delete(r.subscribers, name)
+			return
+		}
 		r.subscribers[name] = l
 	}
 
@@ -151,69 +283,140 @@ func (r *Reader) Status(name string) Status {
 	return Stop
 }
 
-// loop reads the es.json file in every 10 seconds and updates subscribers of changes
-// from Go status to Stop status.
-func (r *Reader) loop() {
-	for _ = range time.Tick(10 * time.Second) {
-		newInfos, err := r.load()
-		if err != nil {
-			// This means the file was malformed or missing. In these
-			// cases we stop all work.
-			r.mu.Lock()
-			for name := range r.subscribers {
-				r.sendStop(name)
-			}
-			r.mu.Unlock()
+// Info returns the ES entry for the named workflow. If there is no entry, ok is false.
+func (r *Reader) Info(name string) (info Info, ok bool) {
+	info, ok = r.entries.Load().(map[string]Info)[name]
+	return info, ok
+}
+
+// Stopped returns the scoped ES entry that stops a Job named "job" in the named workflow that
+// acts on "sites" and "machines". If no scoped entry stops the Job, ok is false. This does not
+// check the workflow's unscoped entry, use Status() or Subscribe() for that.
+func (r *Reader) Stopped(name, job string, sites, machines []string) (info Info, ok bool) {
+	for _, info := range r.entries.Load().(map[string]Info) {
+		if !info.Scoped() || info.Name != name || info.Status == Go {
 			continue
 		}
-		for name, info := range r.entries.Load().(map[string]Info) {
-			newInfo, ok := newInfos[name]
-			if !ok {
-				r.sendStop(name)
-				continue
-			}
-			if info.Status == Go && newInfo.Status != Go {
-				r.sendStop(name)
-				continue
-			}
+		if info.matches(job, sites, machines) {
+			return info, true
 		}
-		r.entries.Store(newInfos)
 	}
+	return Info{}, false
 }
 
-// sendStop sends a Stop State change to all subscriber to a name.
-func (r *Reader) sendStop(name string) {
+// List returns all ES entries, sorted by name. Unscoped entries come before scoped entries
+// with the same name.
+func (r *Reader) List() []Info {
+	return sortedInfos(r.entries.Load().(map[string]Info))
+}
+
+// SetStatus sets the status of the ES entry with the same name and scope as "info", adding
+// the entry if it doesn't exist, and writes the entries to the es.json file. If an unscoped
+// entry changes to Stop, subscribers are sent Stop before this returns. The file is read
+// before it is changed, so edits to the file that we have not read yet are kept.
+func (r *Reader) SetStatus(info Info) error {
+	info.Name = strings.TrimSpace(info.Name)
+	if err := info.validate(); err != nil {
+		return err
+	}
+
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	m, err := r.load()
+	if err != nil {
+		return fmt.Errorf("cannot change an entry until es.json is fixed: %w", err)
+	}
+	m[info.key()] = info
+	if err := r.write(m); err != nil {
+		return err
+	}
+	r.update(m)
+	return nil
+}
+
+// reload reads the es.json file and updates subscribers of changes from Go status to Stop status.
+func (r *Reader) reload() {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	newInfos, err := r.load()
+	if err != nil {
+		// This means the file was malformed or missing. In these
+		// cases we stop all work.
+		log.Printf("es: rejected reload of %s, all workflows are stopped: %s", r.path, err)
+		metrics.ConfigReloads.WithLabelValues("es", metrics.ResultRejected).Inc()
+		newInfos = map[string]Info{}
+	} else {
+		log.Printf("es: reloaded %s with %d entries", r.path, len(newInfos))
+		metrics.ConfigReloads.WithLabelValues("es", metrics.ResultSuccess).Inc()
+	}
+	r.update(newInfos)
+}
+
+// update stores "newInfos" as our entries and sends Stop to subscribers of any entry that was Go
+// and is not in "newInfos" or is no longer Go. r.updateMu must be held.
+func (r *Reader) update(newInfos map[string]Info) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	chans := r.subscribers[name]
-
-	for _, ch := range chans {
-		for {
-			select {
-			case ch <- Stop:
-				break
-			default:
-				// If somehow the channel is full, remove the old entry
-				// and then loop and add the most recent one.
-				select {
-				case <-ch:
-				default:
-				}
-			}
+	oldInfos := r.entries.Load().(map[string]Info)
+	recordTransitions(oldInfos, newInfos)
+
+	for key, info := range oldInfos {
+		if info.Status != Go || newInfos[key].Status == Go {
+			continue
 		}
+		// Scoped entries are checked before each Job runs, there is nothing to send.
+		if info.Scoped() {
+			log.Printf("es: %s %s changed to stop, matching Jobs will not run", info.Name, info.Scope())
+			continue
+		}
+		log.Printf("es: %s changed to stop, stopping %d running workflows", key, len(r.subscribers[key]))
+		r.sendStop(key)
+	}
+	r.entries.Store(newInfos)
+}
+
+// recordTransitions records metrics.ESTransitions for each entry that changed status. Entries
+// that were removed are recorded with the status "removed".
+func recordTransitions(oldInfos, newInfos map[string]Info) {
+	for key, info := range newInfos {
+		if oldInfos[key].Status != info.Status {
+			metrics.ESTransitions.WithLabelValues(info.Name, string(info.Status)).Inc()
+		}
+	}
+	for key, info := range oldInfos {
+		if _, ok := newInfos[key]; !ok {
+			metrics.ESTransitions.WithLabelValues(info.Name, "removed").Inc()
+		}
+	}
+}
+
+// sendStop sends a Stop State change to all subscribers to a name and removes them.
+// r.mu must be held.
+func (r *Reader) sendStop(name string) {
+	for _, ch := range r.subscribers[name] {
+		// If somehow the channel is full, remove the old entry so there is room for Stop.
+		// We are the only sender and hold the lock, so the send cannot block.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- Stop
 		close(ch)
-		delete(r.subscribers, name)
 	}
+	delete(r.subscribers, name)
 }
 
 // load loads the current es.json values and returns them. Any error is an indication
 // that the file could not be read.
 func (r *Reader) load() (map[string]Info, error) {
-	f, err := os.Open("configs/es.json")
+	f, err := os.Open(r.path)
 	if err != nil {
-		return map[string]Info{}, fmt.Errorf("could not open configs/es.json: %w", err)
+		return map[string]Info{}, fmt.Errorf("could not open %s: %w", r.path, err)
 	}
+	defer f.Close()
 
 	dec := json.NewDecoder(f)
 	dec.DisallowUnknownFields()
@@ -223,14 +426,69 @@ func (r *Reader) load() (map[string]Info, error) {
 	for dec.More() {
 		info := Info{}
 		if err := dec.Decode(&info); err != nil {
-			r.entries.Store(map[string]Info{})
 			return map[string]Info{}, fmt.Errorf("es.json file is badly formatted, all jobs moving into stop state")
 		}
-		if _, ok := m[info.Name]; ok {
-			log.Printf("es.json file has two definitions(%s) with the same name, ignoring the second", info.Name)
+		// An entry we can't understand might have been meant to stop something.
+		if err := info.validate(); err != nil {
+			return map[string]Info{}, fmt.Errorf("%s, all jobs moving into stop state", err)
+		}
+		if _, ok := m[info.key()]; ok {
+			log.Printf("es.json file has two definitions(%s %s) with the same name and scope, ignoring the second", info.Name, info.Scope())
 			continue
 		}
-		m[info.Name] = info
+		m[info.key()] = info
 	}
 	return m, nil
 }
+
+// write writes "m" to the es.json file. The file is replaced atomically, so reads of it never
+// see a partial write.
+func (r *Reader) write(m map[string]Info) error {
+	buff := bytes.Buffer{}
+	for _, info := range sortedInfos(m) {
+		b, err := json.MarshalIndent(info, "", "\t")
+		if err != nil {
+			return fmt.Errorf("could not marshal es entry(%s): %w", info.Name, err)
+		}
+		buff.Write(b)
+		buff.WriteString("\n")
+	}
+
+	mode := os.FileMode(0644)
+	if stat, err := os.Stat(r.path); err == nil {
+		mode = stat.Mode()
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*")
+	if err != nil {
+		return fmt.Errorf("could not create temp file for %s: %w", r.path, err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(buff.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s: %w", f.Name(), err)
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return fmt.Errorf("could not set mode on %s: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not write %s: %w", f.Name(), err)
+	}
+	if err := os.Rename(f.Name(), r.path); err != nil {
+		return fmt.Errorf("could not replace %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// sortedInfos returns the entries in "m" sorted by name. Unscoped entries come before scoped
+// entries with the same name.
+func sortedInfos(m map[string]Info) []Info {
+	infos := make([]Info, 0, len(m))
+	for _, info := range m {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].key() < infos[j].key() })
+	return infos
+}
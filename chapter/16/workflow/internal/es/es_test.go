@@ -0,0 +1,204 @@
+package es
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+const testES = `{
+	"Name": "SatelliteDiskErase",
+	"Status": "go"
+}
+{
+	"Name": "Other",
+	"Status": "go"
+}
+`
+
+func newTestReader(t *testing.T) *Reader {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "es.json")
+	if err := os.WriteFile(path, []byte(testES), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := newReader(path)
+	if err != nil {
+		t.Fatalf("newReader() had error: %s", err)
+	}
+	return r
+}
+
+// received returns what is on "ch" without blocking and if "ch" was closed.
+func received(ch chan Status) (got []Status, closed bool) {
+	for {
+		select {
+		case s, ok := <-ch:
+			if !ok {
+				return got, true
+			}
+			got = append(got, s)
+		default:
+			return got, false
+		}
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	r := newTestReader(t)
+
+	ch, cancel := r.Subscribe("SatelliteDiskErase")
+	defer cancel()
+	otherCh, otherCancel := r.Subscribe("Other")
+	defer otherCancel()
+
+	if got, _ := received(ch); len(got) != 1 || got[0] != Go {
+		t.Fatalf("TestSetStatus: Subscribe(): got %v, want [%v]", got, Go)
+	}
+	received(otherCh)
+
+	if err := r.SetStatus(Info{Name: "SatelliteDiskErase", Status: "bad"}); err == nil {
+		t.Errorf("TestSetStatus: SetStatus() with bad Status: got err == nil, want err != nil")
+	}
+
+	// Stop must be sent before SetStatus() returns.
+	if err := r.SetStatus(Info{Name: "SatelliteDiskErase", Status: Stop}); err != nil {
+		t.Fatalf("TestSetStatus: SetStatus(Stop) had error: %s", err)
+	}
+	got, closed := received(ch)
+	if len(got) != 1 || got[0] != Stop || !closed {
+		t.Errorf("TestSetStatus: after SetStatus(Stop): got %v, closed == %v, want [%v], closed == true", got, closed, Stop)
+	}
+	if got, closed := received(otherCh); len(got) != 0 || closed {
+		t.Errorf("TestSetStatus: other subscriber: got %v, closed == %v, want nothing", got, closed)
+	}
+	if r.Status("SatelliteDiskErase") != Stop {
+		t.Errorf("TestSetStatus: Status(): got %v, want %v", r.Status("SatelliteDiskErase"), Stop)
+	}
+
+	if err := r.SetStatus(Info{Name: "New", Status: Go}); err != nil {
+		t.Fatalf("TestSetStatus: SetStatus() of new entry had error: %s", err)
+	}
+
+	// The file should have our changes, so a new Reader should see the same entries.
+	r2, err := newReader(r.path)
+	if err != nil {
+		t.Fatalf("TestSetStatus: newReader() of written file had error: %s", err)
+	}
+	want := []Info{
+		{Name: "New", Status: Go},
+		{Name: "Other", Status: Go},
+		{Name: "SatelliteDiskErase", Status: Stop},
+	}
+	if diff := pretty.Compare(want, r.List()); diff != "" {
+		t.Errorf("TestSetStatus: List(): -want/+got:\n%s", diff)
+	}
+	if diff := pretty.Compare(want, r2.List()); diff != "" {
+		t.Errorf("TestSetStatus: List() from written file: -want/+got:\n%s", diff)
+	}
+}
+
+func TestReload(t *testing.T) {
+	r := newTestReader(t)
+
+	ch, cancel := r.Subscribe("SatelliteDiskErase")
+	defer cancel()
+	received(ch)
+
+	// Editing the file must still work.
+	if err := os.WriteFile(r.path, []byte(`{"Name": "SatelliteDiskErase", "Status": "stop"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r.reload()
+
+	got, closed := received(ch)
+	if len(got) != 1 || got[0] != Stop || !closed {
+		t.Errorf("TestReload: got %v, closed == %v, want [%v], closed == true", got, closed, Stop)
+	}
+	if _, ok := r.Info("Other"); ok {
+		t.Errorf("TestReload: Info(Other): got ok == true, want false")
+	}
+
+	// A bad file stops everything.
+	ch, cancel = r.Subscribe("SatelliteDiskErase")
+	defer cancel()
+	if err := os.WriteFile(r.path, []byte(`{"Name": `), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r.reload()
+	if r.Status("SatelliteDiskErase") != Stop {
+		t.Errorf("TestReload: bad file: got %v, want %v", r.Status("SatelliteDiskErase"), Stop)
+	}
+	if err := r.SetStatus(Info{Name: "SatelliteDiskErase", Status: Go}); err == nil {
+		t.Errorf("TestReload: SetStatus() with bad file: got err == nil, want err != nil")
+	}
+}
+
+func TestStopped(t *testing.T) {
+	r := newTestReader(t)
+
+	ch, cancel := r.Subscribe("SatelliteDiskErase")
+	defer cancel()
+	received(ch)
+
+	stops := []Info{
+		{Name: "SatelliteDiskErase", Status: Stop, Site: "aba"},
+		{Name: "SatelliteDiskErase", Status: Stop, Machine: "abb-00*", Job: "diskErase"},
+		{Name: "SatelliteDiskErase", Status: Go, Site: "abc"},
+	}
+	for _, info := range stops {
+		if err := r.SetStatus(info); err != nil {
+			t.Fatalf("TestStopped: SetStatus(%+v) had error: %s", info, err)
+		}
+	}
+	if err := r.SetStatus(Info{Name: "SatelliteDiskErase", Status: Stop, Machine: "["}); err == nil {
+		t.Errorf("TestStopped: SetStatus() with bad Machine pattern: got err == nil, want err != nil")
+	}
+
+	// Scoped stops must not stop the whole workflow.
+	if got, closed := received(ch); len(got) != 0 || closed {
+		t.Errorf("TestStopped: subscriber: got %v, closed == %v, want nothing", got, closed)
+	}
+	if r.Status("SatelliteDiskErase") != Go {
+		t.Errorf("TestStopped: Status(): got %v, want %v", r.Status("SatelliteDiskErase"), Go)
+	}
+
+	tests := []struct {
+		desc     string
+		name     string
+		job      string
+		sites    []string
+		machines []string
+		want     bool
+	}{
+		{desc: "Matching site", name: "SatelliteDiskErase", job: "validateDecom", sites: []string{"aba"}, want: true},
+		{desc: "Other site", name: "SatelliteDiskErase", job: "validateDecom", sites: []string{"abb"}},
+		{desc: "Site with go", name: "SatelliteDiskErase", job: "validateDecom", sites: []string{"abc"}},
+		{desc: "Other workflow", name: "Other", job: "validateDecom", sites: []string{"aba"}},
+		{desc: "Matching machine and job", name: "SatelliteDiskErase", job: "diskErase", machines: []string{"abb-001"}, want: true},
+		{desc: "Matching machine, other job", name: "SatelliteDiskErase", job: "sleep", machines: []string{"abb-001"}},
+		{desc: "Other machine", name: "SatelliteDiskErase", job: "diskErase", machines: []string{"abb-010"}},
+		{desc: "No targets", name: "SatelliteDiskErase", job: "diskErase"},
+	}
+	for _, test := range tests {
+		_, got := r.Stopped(test.name, test.job, test.sites, test.machines)
+		if got != test.want {
+			t.Errorf("TestStopped(%s): got %v, want %v", test.desc, got, test.want)
+		}
+	}
+
+	// Scoped entries must be written to the file.
+	r2, err := newReader(r.path)
+	if err != nil {
+		t.Fatalf("TestStopped: newReader() of written file had error: %s", err)
+	}
+	if diff := pretty.Compare(r.List(), r2.List()); diff != "" {
+		t.Errorf("TestStopped: List() from written file: -want/+got:\n%s", diff)
+	}
+	if len(r2.List()) != 5 {
+		t.Errorf("TestStopped: got %d entries, want 5", len(r2.List()))
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Package lease provides exclusive leases on the resources that Jobs act on, such as sites and
+machines, so that Jobs in different workflows cannot act on the same resource at the same time.
+A global variable called Data holds the leases for the server.
+
+Resources are paths separated by "/", like "aap" for a site or "aap/aa00" for a machine in it.
+A lease on a resource includes everything under it, so a lease on "aap" conflicts with a lease
+on "aap/aa00" that is held by another holder.
+
+Leases are held by a holder, which is the ID of a workflow. A holder can lease a resource it
+already holds, so Jobs in the same workflow do not block each other. Leases are only kept in
+memory, a restart releases all of them.
+*/
+package lease
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Data holds the leases for the server.
+var Data = New()
+
+// HeldError is returned when a resource is leased by another holder.
+type HeldError struct {
+	// Resource is the resource that was asked for.
+	Resource string
+	// Lease is the lease that conflicts with it.
+	Lease Lease
+}
+
+// Error implements error.Error().
+func (h *HeldError) Error() string {
+	if h.Resource == h.Lease.Resource {
+		return fmt.Sprintf("resource(%s) is leased by workflow(%s)", h.Resource, h.Lease.Holder)
+	}
+	return fmt.Sprintf("resource(%s) conflicts with resource(%s) leased by workflow(%s)", h.Resource, h.Lease.Resource, h.Lease.Holder)
+}
+
+// Lease is a lease on a resource.
+type Lease struct {
+	// Resource is the resource that is leased.
+	Resource string
+	// Holder is who holds the lease.
+	Holder string
+	// Since is when the lease was first acquired.
+	Since time.Time
+}
+
+type lease struct {
+	Lease
+	// count is how many times the holder has acquired the lease without releasing it.
+	count int
+}
+
+// Manager manages leases.
+type Manager struct {
+	mu     sync.Mutex
+	leases map[string]*lease
+	// released is closed and replaced when a lease is released, which wakes up waiters.
+	released chan struct{}
+}
+
+// New is the constructor for Manager.
+func New() *Manager {
+	return &Manager{leases: map[string]*lease{}, released: make(chan struct{})}
+}
+
+// conflicts returns true if a lease on "a" would include "b" or the other way around.
+func conflicts(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// held returns the first lease held by another holder that conflicts with "resources".
+// m.mu must be held.
+func (m *Manager) held(holder string, resources []string) *HeldError {
+	for _, r := range resources {
+		for _, l := range m.leases {
+			if l.Holder != holder && conflicts(r, l.Resource) {
+				return &HeldError{Resource: r, Lease: l.Lease}
+			}
+		}
+	}
+	return nil
+}
+
+// TryAcquire acquires leases on all "resources" for "holder" at once. If any of them is leased
+// by another holder, nothing is acquired and a *HeldError is returned. The returned function must
+// be called to release the leases.
+func (m *Manager) TryAcquire(holder string, resources []string) (release func(), err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.held(holder, resources); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, r := range resources {
+		l, ok := m.leases[r]
+		if !ok {
+			l = &lease{Lease: Lease{Resource: r, Holder: holder, Since: now}}
+			m.leases[r] = l
+		}
+		l.count++
+	}
+
+	once := sync.Once{}
+	return func() { once.Do(func() { m.release(resources) }) }, nil
+}
+
+// Acquire is like TryAcquire(), but waits until the leases are available or "ctx" is cancelled.
+// If it must wait, "waiting" is called with the lease it is waiting on each time that changes.
+func (m *Manager) Acquire(ctx context.Context, holder string, resources []string, waiting func(*HeldError)) (release func(), err error) {
+	var last Lease
+	for {
+		m.mu.Lock()
+		released := m.released
+		m.mu.Unlock()
+
+		release, err := m.TryAcquire(holder, resources)
+		if err == nil {
+			return release, nil
+		}
+		held := err.(*HeldError)
+		if held.Lease != last {
+			last = held.Lease
+			if waiting != nil {
+				waiting(held)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-released:
+		}
+	}
+}
+
+func (m *Manager) release(resources []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range resources {
+		l, ok := m.leases[r]
+		if !ok {
+			continue
+		}
+		l.count--
+		if l.count <= 0 {
+			delete(m.leases, r)
+		}
+	}
+	close(m.released)
+	m.released = make(chan struct{})
+}
@@ -0,0 +1,109 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTryAcquire(t *testing.T) {
+	tests := []struct {
+		desc     string
+		held     []string
+		holder   string
+		want     []string
+		wantHeld bool
+	}{
+		{desc: "Different machine", held: []string{"aap/aa00"}, holder: "b", want: []string{"aap/aa01"}},
+		{desc: "Different site", held: []string{"aap"}, holder: "b", want: []string{"aaq/aa00"}},
+		{desc: "Same machine", held: []string{"aap/aa00"}, holder: "b", want: []string{"aap/aa00"}, wantHeld: true},
+		{desc: "Machine in leased site", held: []string{"aap"}, holder: "b", want: []string{"aap/aa00"}, wantHeld: true},
+		{desc: "Site with leased machine", held: []string{"aap/aa00"}, holder: "b", want: []string{"aap"}, wantHeld: true},
+		{desc: "Site that is a prefix", held: []string{"aa"}, holder: "b", want: []string{"aap/aa00"}},
+		{desc: "One of many", held: []string{"aap/aa00"}, holder: "b", want: []string{"aap/aa01", "aap/aa00"}, wantHeld: true},
+		{desc: "Same holder", held: []string{"aap"}, holder: "a", want: []string{"aap/aa00"}},
+	}
+
+	for _, test := range tests {
+		m := New()
+		if _, err := m.TryAcquire("a", test.held); err != nil {
+			t.Fatalf("TestTryAcquire(%s): TryAcquire(held) had error: %s", test.desc, err)
+		}
+		_, err := m.TryAcquire(test.holder, test.want)
+		held := &HeldError{}
+		switch {
+		case test.wantHeld && !errors.As(err, &held):
+			t.Errorf("TestTryAcquire(%s): got err == %v, want *HeldError", test.desc, err)
+		case test.wantHeld && held.Lease.Holder != "a":
+			t.Errorf("TestTryAcquire(%s): got HeldError.Lease.Holder %q, want \"a\"", test.desc, held.Lease.Holder)
+		case !test.wantHeld && err != nil:
+			t.Errorf("TestTryAcquire(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestRelease(t *testing.T) {
+	m := New()
+
+	// The same holder can acquire a lease twice, it is held until both are released.
+	release1, err := m.TryAcquire("a", []string{"aap/aa00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release2, err := m.TryAcquire("a", []string{"aap/aa00"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release1()
+	release1() // Releasing twice does nothing.
+	if _, err := m.TryAcquire("b", []string{"aap/aa00"}); err == nil {
+		t.Errorf("TestRelease: TryAcquire() after one release: got err == nil, want err != nil")
+	}
+	release2()
+	if _, err := m.TryAcquire("b", []string{"aap/aa00"}); err != nil {
+		t.Errorf("TestRelease: TryAcquire() after all releases: got err == %s, want err == nil", err)
+	}
+}
+
+func TestAcquire(t *testing.T) {
+	m := New()
+
+	release, err := m.TryAcquire("a", []string{"aap"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waiting := make(chan *HeldError, 1)
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.Acquire(context.Background(), "b", []string{"aap/aa00"}, func(h *HeldError) { waiting <- h })
+		done <- err
+	}()
+
+	select {
+	case h := <-waiting:
+		if h.Lease.Resource != "aap" || h.Lease.Holder != "a" {
+			t.Errorf("TestAcquire: waiting on: got %+v, want resource(aap) held by a", h.Lease)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestAcquire: Acquire() did not wait")
+	}
+
+	release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("TestAcquire: got err == %s, want err == nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestAcquire: Acquire() did not return after the lease was released")
+	}
+
+	// Cancelling the Context stops waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := m.Acquire(ctx, "c", []string{"aap/aa00"}, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TestAcquire: Acquire() of held lease with cancelled Context: got err == %v, want context.DeadlineExceeded", err)
+	}
+}
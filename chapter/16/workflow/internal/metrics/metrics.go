@@ -0,0 +1,184 @@
+/*
+Package metrics provides the Prometheus metrics exported by the workflow service.
+
+Metrics are registered with the default Prometheus registry when this package is imported.
+Serve them on an HTTP endpoint with Handler() and record every RPC by adding our interceptors
+to the gRPC server:
+
+	g := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(metrics.UnaryInterceptor, auth.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(metrics.StreamInterceptor, auth.StreamInterceptor),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+The interceptors should be first so that RPCs rejected by other interceptors are counted.
+*/
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const namespace = "workflow"
+
+// Results used in the "result" label of ConfigReloads, TokenWait and Notifications.
+const (
+	ResultSuccess  = "success"
+	ResultRejected = "rejected"
+	ResultFailed   = "failed"
+)
+
+var (
+	// RPCs counts the RPCs the server has handled by method and gRPC status code. Rejected
+	// RPCs, such as those rejected by rate limits with ResourceExhausted, are counted by code.
+	RPCs = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpcs_total",
+			Help:      "The number of RPCs handled by method and gRPC status code.",
+		},
+		[]string{"method", "code"},
+	)
+
+	// RPCDuration records how long RPCs took by method. Streaming RPCs, such as Watch, record
+	// how long the stream was open.
+	RPCDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "How long RPCs took by method.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+
+	// ActiveWorkflows is the number of workflows executing by workflow type.
+	ActiveWorkflows = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active",
+			Help:      "The number of workflows that are executing by workflow type.",
+		},
+		[]string{"name"},
+	)
+
+	// Workflows counts workflows that finished by workflow type and final status.
+	Workflows = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "finished_total",
+			Help:      "The number of workflows that finished by workflow type and final status.",
+		},
+		[]string{"name", "status"},
+	)
+
+	// BlockDuration records how long Blocks ran by workflow type and final status.
+	BlockDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "block_duration_seconds",
+			Help:      "How long Blocks ran by workflow type and final status.",
+			Buckets:   longBuckets,
+		},
+		[]string{"name", "status"},
+	)
+
+	// JobDuration records how long Jobs ran by Job name and final status. This includes retries.
+	JobDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "How long Jobs ran, including retries, by Job name and final status.",
+			Buckets:   longBuckets,
+		},
+		[]string{"job", "status"},
+	)
+
+	// ESTransitions counts changes to emergency stop entries by workflow type and the status
+	// changed to. Scoped entries are counted with their workflow type.
+	ESTransitions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "es_transitions_total",
+			Help:      "The number of emergency stop status changes by workflow type and new status.",
+		},
+		[]string{"name", "status"},
+	)
+
+	// ConfigReloads counts reloads of our configuration files by file ("es" or "policies") and
+	// result (ResultSuccess or ResultRejected).
+	ConfigReloads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "config_reloads_total",
+			Help:      "The number of configuration file reloads by file and result.",
+		},
+		[]string{"file", "result"},
+	)
+
+	// TokenWait records how long Jobs waited for a token by bucket and result (ResultSuccess or
+	// ResultFailed).
+	TokenWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "token_bucket_wait_seconds",
+			Help:      "How long Jobs waited for a token by bucket and result.",
+			Buckets:   longBuckets,
+		},
+		[]string{"bucket", "result"},
+	)
+
+	// Notifications counts workflow notifications sent to webhooks by result (ResultSuccess or
+	// ResultFailed). A notification that was retried is counted once.
+	Notifications = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_total",
+			Help:      "The number of notifications sent to webhooks by result.",
+		},
+		[]string{"result"},
+	)
+)
+
+// longBuckets are histogram buckets for things that can take from milliseconds to hours.
+var longBuckets = prometheus.ExponentialBuckets(0.01, 4, 12)
+
+// Handler returns an http.Handler that serves our metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// UnaryInterceptor is a grpc.UnaryServerInterceptor that records RPCs and RPCDuration.
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	record(info.FullMethod, start, err)
+	return resp, err
+}
+
+// StreamInterceptor is a grpc.StreamServerInterceptor that records RPCs and RPCDuration.
+func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	record(info.FullMethod, start, err)
+	return err
+}
+
+func record(method string, start time.Time, err error) {
+	s, ok := status.FromError(err)
+	if !ok {
+		s = status.FromContextError(err)
+	}
+	RPCs.WithLabelValues(method, s.Code().String()).Inc()
+	RPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
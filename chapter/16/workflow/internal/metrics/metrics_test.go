@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	const method = "/workflow.Workflow/TestUnaryInterceptor"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	tests := []struct {
+		desc string
+		err  error
+		code string
+	}{
+		{desc: "Success", code: "OK"},
+		{desc: "Rate limited", err: status.Errorf(codes.ResourceExhausted, "too many requests"), code: "ResourceExhausted"},
+		{desc: "Caller went away", err: context.Canceled, code: "Canceled"},
+	}
+
+	for _, test := range tests {
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, test.err
+		}
+		before := testutil.ToFloat64(RPCs.WithLabelValues(method, test.code))
+		if _, err := UnaryInterceptor(context.Background(), nil, info, handler); err != test.err {
+			t.Errorf("TestUnaryInterceptor(%s): got err == %v, want %v", test.desc, err, test.err)
+		}
+		if got := testutil.ToFloat64(RPCs.WithLabelValues(method, test.code)) - before; got != 1 {
+			t.Errorf("TestUnaryInterceptor(%s): RPCs{code=%s} increased by %v, want 1", test.desc, test.code, got)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Package notify tells other systems when workflows finish by POSTing a JSON Summary to webhooks.
+
+A Summary is sent when a workflow completes, fails, is cancelled or is emergency stopped:
+
+	n, err := notify.New([]string{"https://chat.example.com/hooks/workflows"})
+	if err != nil {
+		// Do something
+	}
+	defer n.Close()
+
+	n.Send(notify.NewSummary(id, statusResp))
+
+Send() does not block. Each webhook is tried up to 3 times with a backoff, and failures are
+logged but otherwise ignored, as a webhook being down must not affect workflows.
+*/
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Event is why a Summary was sent.
+type Event string
+
+const (
+	// EventCompleted is sent when a workflow completed.
+	EventCompleted Event = "completed"
+	// EventFailed is sent when a workflow failed.
+	EventFailed Event = "failed"
+	// EventCancelled is sent when a workflow was cancelled.
+	EventCancelled Event = "cancelled"
+	// EventEmergencyStop is sent when a workflow was stopped by an emergency stop.
+	EventEmergencyStop Event = "emergency_stop"
+)
+
+// Summary is the JSON body POSTed to webhooks.
+type Summary struct {
+	// Event is why the Summary was sent.
+	Event Event `json:"event"`
+	// Time is when the Summary was created.
+	Time time.Time `json:"time"`
+	// ID is the ID of the workflow.
+	ID string `json:"id"`
+	// Name is the name of the WorkReq.
+	Name string `json:"name"`
+	// Desc is the description of the WorkReq.
+	Desc string `json:"desc,omitempty"`
+	// Status is the final status of the workflow.
+	Status string `json:"status"`
+	// Error is an error that did not come from a Job, such as the server restarting.
+	Error string `json:"error,omitempty"`
+	// ExecutedBy is the identity that executed the workflow.
+	ExecutedBy string `json:"executed_by,omitempty"`
+	// TraceID is the OpenTelemetry trace ID of the execution.
+	TraceID string `json:"trace_id,omitempty"`
+	// Blocks is the final status of each Block.
+	Blocks []BlockSummary `json:"blocks"`
+	// FailedJobs are the Jobs that failed.
+	FailedJobs []JobSummary `json:"failed_jobs,omitempty"`
+}
+
+// BlockSummary summarizes a Block.
+type BlockSummary struct {
+	ID     string `json:"id,omitempty"`
+	Desc   string `json:"desc,omitempty"`
+	Status string `json:"status"`
+}
+
+// JobSummary summarizes a Job that failed.
+type JobSummary struct {
+	// Block is the index of the Block the Job is in.
+	Block int `json:"block"`
+	// Job is the index of the Job in the Block.
+	Job   int    `json:"job"`
+	Name  string `json:"name"`
+	Error string `json:"error,omitempty"`
+}
+
+// NewSummary returns the Summary of the finished workflow with "id" and status "resp".
+func NewSummary(id string, resp *pb.StatusResp) Summary {
+	s := Summary{
+		Event:      EventFailed,
+		Time:       time.Now(),
+		ID:         id,
+		Name:       resp.Name,
+		Desc:       resp.Desc,
+		Status:     resp.Status.String(),
+		Error:      resp.Error,
+		ExecutedBy: resp.ExecutedBy,
+		TraceID:    resp.TraceId,
+		Blocks:     []BlockSummary{},
+	}
+	switch {
+	case resp.WasEsStopped:
+		s.Event = EventEmergencyStop
+	case resp.Status == pb.Status_StatusCompleted:
+		s.Event = EventCompleted
+	case resp.Status == pb.Status_StatusCancelled:
+		s.Event = EventCancelled
+	}
+
+	for i, b := range resp.Blocks {
+		s.Blocks = append(s.Blocks, BlockSummary{ID: b.Id, Desc: b.Desc, Status: b.Status.String()})
+		for j, js := range b.Jobs {
+			if js.Status == pb.Status_StatusFailed {
+				s.FailedJobs = append(s.FailedJobs, JobSummary{Block: i, Job: j, Name: js.Name, Error: js.Error})
+			}
+		}
+	}
+	return s
+}
+
+// Notifier sends Summaries to webhooks. A nil *Notifier sends nothing.
+type Notifier struct {
+	urls   []string
+	client *http.Client
+	// backoff is how long to wait after the first failed attempt. It doubles after each attempt.
+	backoff time.Duration
+
+	wg sync.WaitGroup
+}
+
+// attempts is how many times we try to send a Summary to a webhook.
+const attempts = 3
+
+// New creates a Notifier that sends to "urls", which must be http or https URLs.
+func New(urls []string) (*Notifier, error) {
+	for _, u := range urls {
+		p, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("webhook(%s) is not a valid URL: %w", u, err)
+		}
+		if (p.Scheme != "http" && p.Scheme != "https") || p.Host == "" {
+			return nil, fmt.Errorf("webhook(%s) must be an http or https URL", u)
+		}
+	}
+	return &Notifier{
+		urls:    urls,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		backoff: time.Second,
+	}, nil
+}
+
+// Send sends "s" to all webhooks in the background.
+func (n *Notifier) Send(s Summary) {
+	if n == nil || len(n.urls) == 0 {
+		return
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("could not marshal notification for Workflow(%s): %s", s.ID, err)
+		return
+	}
+	for _, u := range n.urls {
+		u := u
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			if err := n.post(u, b); err != nil {
+				log.Printf("could not notify webhook(%s) that Workflow(%s) %s: %s", u, s.ID, s.Event, err)
+				metrics.Notifications.WithLabelValues(metrics.ResultFailed).Inc()
+				return
+			}
+			metrics.Notifications.WithLabelValues(metrics.ResultSuccess).Inc()
+		}()
+	}
+}
+
+// post sends "body" to "u", retrying on failure.
+func (n *Notifier) post(u string, body []byte) error {
+	backoff := n.backoff
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		if err = n.postOnce(u, body); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("after %d attempts: %w", attempts, err)
+}
+
+func (n *Notifier) postOnce(u string, body []byte) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close waits for Summaries that are being sent.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestNewSummary(t *testing.T) {
+	resp := &pb.StatusResp{
+		Name:       "SatelliteDiskErase",
+		Status:     pb.Status_StatusFailed,
+		ExecutedBy: "alice",
+		Blocks: []*pb.BlockStatus{
+			{
+				Id:     "erase",
+				Status: pb.Status_StatusFailed,
+				Jobs: []*pb.JobStatus{
+					{Name: "diskErase", Status: pb.Status_StatusCompleted},
+					{Name: "diskErase", Status: pb.Status_StatusFailed, Error: "disk on fire"},
+				},
+			},
+			{Status: pb.Status_StatusNotStarted},
+		},
+	}
+
+	got := NewSummary("id", resp)
+	got.Time = time.Time{}
+	want := Summary{
+		Event:      EventFailed,
+		ID:         "id",
+		Name:       "SatelliteDiskErase",
+		Status:     "StatusFailed",
+		ExecutedBy: "alice",
+		Blocks: []BlockSummary{
+			{ID: "erase", Status: "StatusFailed"},
+			{Status: "StatusNotStarted"},
+		},
+		FailedJobs: []JobSummary{{Block: 0, Job: 1, Name: "diskErase", Error: "disk on fire"}},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestNewSummary: -want/+got:\n%s", diff)
+	}
+
+	tests := []struct {
+		desc         string
+		status       pb.Status
+		wasEsStopped bool
+		want         Event
+	}{
+		{desc: "Completed", status: pb.Status_StatusCompleted, want: EventCompleted},
+		{desc: "Failed", status: pb.Status_StatusFailed, want: EventFailed},
+		{desc: "Cancelled", status: pb.Status_StatusCancelled, want: EventCancelled},
+		{desc: "Emergency stopped", status: pb.Status_StatusFailed, wasEsStopped: true, want: EventEmergencyStop},
+	}
+	for _, test := range tests {
+		s := NewSummary("id", &pb.StatusResp{Status: test.status, WasEsStopped: test.wasEsStopped})
+		if s.Event != test.want {
+			t.Errorf("TestNewSummary(%s): got Event %q, want %q", test.desc, s.Event, test.want)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []Summary
+		calls    int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls++
+		// Fail the first attempt so that we retry.
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("TestSend: got %s with Content-Type %q, want POST of application/json", r.Method, r.Header.Get("Content-Type"))
+		}
+		s := Summary{}
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			t.Errorf("TestSend: could not decode body: %s", err)
+		}
+		received = append(received, s)
+	}))
+	defer srv.Close()
+
+	n, err := New([]string{srv.URL})
+	if err != nil {
+		t.Fatalf("TestSend: New() had error: %s", err)
+	}
+	n.backoff = time.Millisecond
+
+	want := NewSummary("id", &pb.StatusResp{Name: "test", Status: pb.Status_StatusCompleted})
+	n.Send(want)
+	n.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 {
+		t.Errorf("TestSend: got %d calls, want 2", calls)
+	}
+	if len(received) != 1 {
+		t.Fatalf("TestSend: got %d Summaries, want 1", len(received))
+	}
+	if !received[0].Time.Equal(want.Time) {
+		t.Errorf("TestSend: got Time %v, want %v", received[0].Time, want.Time)
+	}
+	received[0].Time, want.Time = time.Time{}, time.Time{}
+	if diff := pretty.Compare(want, received[0]); diff != "" {
+		t.Errorf("TestSend: -want/+got:\n%s", diff)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, u := range []string{"ftp://example.com", "example.com/hook", "http://"} {
+		if _, err := New([]string{u}); err == nil {
+			t.Errorf("TestNew(%s): got err == nil, want err != nil", u)
+		}
+	}
+
+	// A nil Notifier, such as when none is configured, must do nothing.
+	var n *Notifier
+	n.Send(Summary{})
+	n.Close()
+}
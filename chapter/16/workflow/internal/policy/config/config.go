@@ -3,6 +3,10 @@ Package config stores the policy configuration Go representation, a global varia
 Policies that is for reading the Config as it is updated on disk and
 configuration validation to make sure errors don't slip in to the Config.
 
+The file is reloaded as soon as it changes, with a check for changes every 10 seconds in case
+change notifications are not available. Each reload is logged, so operators can tell if an
+edit to the file was accepted or rejected.
+
 A configuration is stored in JSON and looks like:
 {
 	"Name": "SateliteDiskErase",
@@ -27,22 +31,28 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"reflect"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/watch"
 )
 
+// pollInterval is how often we check the policy file for changes in case we miss a change notification.
+const pollInterval = 10 * time.Second
+
 // Policies provides the policy Reader that can be used to read the current policy.
 var Policies *Reader
 
-// Init is called in main to initialize our reads of the policy file. It is called
+// Init is called in main to initialize our reads of the policy file at "path". It is called
 // manually instead of init() to guarantee other init() statements are run first.
-func Init() {
-	r, err := newReader("configs/policies.json")
+func Init(path string) {
+	r, err := NewReader(path)
 	if err != nil {
 		panic(err)
 	}
@@ -147,14 +157,15 @@ func (p *Policy) validate() error {
 }
 
 // Reader is used to read the current policy configuration. The
-// configuration is checked for updates every 10 seconds and if there
+// configuration is reloaded when it changes and if there
 // is a valid configuration, it is updated. If not, an error is recorded.
-// Once a Reader is returned by New(), it guarantees to always return a Config.
+// Once a Reader is returned by NewReader(), it guarantees to always return a Config.
 // If there is an error also returned, then the Config is the last known good
 // Config.
 type Reader struct {
-	loc  string
-	conf atomic.Value // Config
+	loc     string
+	conf    atomic.Value // Config
+	watcher *watch.Watcher
 }
 
 // Read reads the latest Config we have. If an error is returned, the Config will
@@ -165,14 +176,19 @@ func (r *Reader) Read() (Config, error) {
 	return c, c.err
 }
 
-func (r *Reader) update() {
-	for _ = range time.Tick(10 * time.Second) {
-		if err := r.load(); err != nil {
-			c := r.conf.Load().(Config)
-			c.err = err
-			r.conf.Store(c)
-		}
+// reload loads the policy file. If it is not valid, the last known good Config is kept and
+// the error is recorded.
+func (r *Reader) reload() {
+	if err := r.load(); err != nil {
+		log.Printf("policy config: rejected reload of %s, keeping the last good config: %s", r.loc, err)
+		metrics.ConfigReloads.WithLabelValues("policies", metrics.ResultRejected).Inc()
+		c := r.conf.Load().(Config)
+		c.err = err
+		r.conf.Store(c)
+		return
 	}
+	log.Printf("policy config: reloaded %s with %d workflows", r.loc, len(r.conf.Load().(Config).Workflows))
+	metrics.ConfigReloads.WithLabelValues("policies", metrics.ResultSuccess).Inc()
 }
 
 func (r *Reader) load() error {
@@ -208,13 +224,22 @@ func (r *Reader) load() error {
 	return nil
 }
 
-// newReader returns a Reader that can grab the latest Config on disk.
-func newReader(loc string) (*Reader, error) {
+// NewReader returns a Reader that can grab the latest Config on disk at "loc".
+func NewReader(loc string) (*Reader, error) {
 	r := &Reader{loc: loc}
 	if err := r.load(); err != nil {
 		return nil, err
 	}
-	go r.update()
 
+	var err error
+	r.watcher, err = watch.New(loc, pollInterval, r.reload)
+	if err != nil {
+		return nil, err
+	}
 	return r, nil
 }
+
+// Close stops watching the policy file for changes.
+func (r *Reader) Close() error {
+	return r.watcher.Close()
+}
@@ -9,8 +9,10 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
@@ -51,6 +53,11 @@ func Register(name string, p Policy, s Settings) {
 	if reflect.ValueOf(s).Kind() != reflect.Struct {
 		panic(fmt.Sprintf("cannot register a policy(%s) with settings that are not a struct", name))
 	}
+	if d, ok := p.(Describer); ok {
+		if err := d.Describe().validate(s); err != nil {
+			panic(fmt.Sprintf("policy(%s) Schema %s", name, err))
+		}
+	}
 	log.Println("Registered Policy: ", name)
 	policies[name] = registration{policy: p, settings: s}
 }
@@ -64,6 +71,30 @@ func GetSettings(name string) (Settings, error) {
 	return r.settings, nil
 }
 
+// List returns the names of all registered policies, sorted.
+func List() []string {
+	l := make([]string, 0, len(policies))
+	for name := range policies {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Describe returns the Schema for the policy called "name". "ok" is false if the policy does not
+// implement Describer.
+func Describe(name string) (s Schema, ok bool, err error) {
+	r, found := policies[name]
+	if !found {
+		return Schema{}, false, fmt.Errorf("policy(%s) cannot be found", name)
+	}
+	d, ok := r.policy.(Describer)
+	if !ok {
+		return Schema{}, false, nil
+	}
+	return d.Describe(), true, nil
+}
+
 // Policy represents a policy that is defined to check a WorkReq is compliant.
 type Policy interface {
 	// Run runs the policy against a request with settings that are specific
@@ -71,6 +102,62 @@ type Policy interface {
 	Run(ctx context.Context, req *pb.WorkReq, settings Settings) error
 }
 
+// Pauser is an optional interface a Policy can implement to pause a WorkReq that is executing,
+// such as when a maintenance window closes. It is checked before each new Block is started.
+type Pauser interface {
+	// Pause returns why "req" cannot start a new Block now, or "" if it can. If a reason is
+	// returned, "until" is when it should be checked again, or zero if that is not known.
+	Pause(ctx context.Context, req *pb.WorkReq, settings Settings) (reason string, until time.Time)
+}
+
+// Setting describes a field in a policy's Settings.
+type Setting struct {
+	// Name is the name of the field in the Settings struct.
+	Name string
+	// Desc describes the setting.
+	Desc string
+	// Type is the type of the setting.
+	Type pb.ArgType
+	// Required indicates the setting must be set.
+	Required bool
+	// Allowed, if set, are the only values the setting can have.
+	Allowed []string
+}
+
+// Schema describes what a Policy checks and its Settings.
+type Schema struct {
+	// Desc describes what the Policy checks.
+	Desc string
+	// Settings describe the fields in the Policy's Settings.
+	Settings []Setting
+}
+
+// validate checks that each Setting is an exported field in "s", which is done when a Policy
+// is registered. The Settings themselves are still checked with Settings.Validate().
+func (sc Schema) validate(s Settings) error {
+	t := reflect.TypeOf(s)
+	seen := map[string]bool{}
+	for _, setting := range sc.Settings {
+		if seen[setting.Name] {
+			return fmt.Errorf("has Setting(%s) twice", setting.Name)
+		}
+		seen[setting.Name] = true
+
+		f, ok := t.FieldByName(setting.Name)
+		if !ok || !f.IsExported() {
+			return fmt.Errorf("has Setting(%s) that is not an exported field of %T", setting.Name, s)
+		}
+	}
+	return nil
+}
+
+// Describer is an optional interface a Policy can implement to describe what it checks and its
+// Settings, which the ListPolicies RPC returns.
+type Describer interface {
+	// Describe returns the Policy's Schema.
+	Describe() Schema
+}
+
 // PolicyArgs detail a policy and settings to use to invoke it.
 type PolicyArgs struct {
 	// Name of the policy in the registry.
@@ -146,3 +233,29 @@ func Run(ctx context.Context, req *pb.WorkReq, args ...PolicyArgs) error {
 
 	return nil
 }
+
+// Paused checks all policies that are passed that implement Pauser. It returns why "req" cannot
+// start a new Block, or "" if it can, and the earliest time a policy should be checked again.
+// Policies that do not exist are ignored, as they were checked when the WorkReq was validated.
+func Paused(ctx context.Context, req *pb.WorkReq, args ...PolicyArgs) (reason string, until time.Time) {
+	var reasons []string
+	for _, arg := range args {
+		r, ok := policies[arg.Name]
+		if !ok {
+			continue
+		}
+		p, ok := r.policy.(Pauser)
+		if !ok {
+			continue
+		}
+		reason, u := p.Pause(ctx, req, arg.Settings)
+		if reason == "" {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("policy(%s): %s", arg.Name, reason))
+		if !u.IsZero() && (until.IsZero() || u.Before(until)) {
+			until = u
+		}
+	}
+	return strings.Join(reasons, "; "), until
+}
@@ -0,0 +1,36 @@
+package policy
+
+import (
+	"testing"
+)
+
+type testSettings struct {
+	Count  int
+	hidden bool
+}
+
+func (t testSettings) Validate() error { return nil }
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		schema  Schema
+		wantErr bool
+	}{
+		{desc: "Valid", schema: Schema{Settings: []Setting{{Name: "Count"}}}},
+		{desc: "No settings", schema: Schema{}},
+		{desc: "Not a field", schema: Schema{Settings: []Setting{{Name: "Approvers"}}}, wantErr: true},
+		{desc: "Not exported", schema: Schema{Settings: []Setting{{Name: "hidden"}}}, wantErr: true},
+		{desc: "Twice", schema: Schema{Settings: []Setting{{Name: "Count"}, {Name: "Count"}}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := test.schema.validate(testSettings{})
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestSchemaValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestSchemaValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
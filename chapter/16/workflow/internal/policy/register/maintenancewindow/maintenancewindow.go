@@ -0,0 +1,313 @@
+/*
+Package maintenancewindow provides a policy that only allows a workflow to be submitted and
+executed inside weekly maintenance windows and never inside a change freeze. The policy is also
+checked before each Block is started, so a workflow that is running when a window closes or a
+freeze starts is paused until it is allowed again. Blocks that are running are allowed to finish.
+
+Settings look like:
+
+	{
+		"Timezone": "America/Los_Angeles",
+		"Windows": [
+			{"Days": ["Sat", "Sun"], "Start": "00:00", "End": "23:59"},
+			{"Days": ["Tue", "Thu"], "Start": "22:00", "End": "04:00"}
+		],
+		"Freezes": [
+			{"Start": "2022-11-21", "End": "2022-11-28", "Reason": "Thanksgiving"}
+		]
+	}
+
+A window whose End is before its Start closes on the next day, so the second window above opens
+at 22:00 on Tuesday and closes at 04:00 on Wednesday. If there are no Windows, a workflow is
+allowed at any time that is not in a Freeze.
+*/
+package maintenancewindow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	// Include the timezone database in case the server does not have one.
+	_ "time/tzdata"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This registers our policy with the service.
+func init() {
+	p, err := New()
+	if err != nil {
+		panic(err)
+	}
+	policy.Register("maintenanceWindow", p, Settings{})
+}
+
+// now is the current time. It is a variable so tests can change it.
+var now = time.Now
+
+// Settings provides settings for a specific implementation of our Policy.
+type Settings struct {
+	// Timezone is the IANA name of the timezone Windows and Freezes are in, like
+	// "America/New_York". If not set, this is UTC.
+	Timezone string
+	// Windows are the weekly windows a workflow can run in.
+	Windows []Window
+	// Freezes are periods a workflow cannot run in, even inside a Window.
+	Freezes []Freeze
+}
+
+// Window is a weekly maintenance window.
+type Window struct {
+	// Days are the days of the week the window opens, like "Mon" or "Monday".
+	// If not set, the window opens every day.
+	Days []string
+	// Start is when the window opens, like "22:00".
+	Start string
+	// End is when the window closes, like "04:00". If End is before Start, the window closes
+	// on the day after it opens.
+	End string
+}
+
+// Freeze is a period when changes are not allowed.
+type Freeze struct {
+	// Start is when the freeze starts, like "2022-11-21", "2022-11-21 18:00" or an RFC3339 time.
+	Start string
+	// End is when the freeze ends, in the same formats as Start.
+	End string
+	// Reason says why there is a freeze. It is included in errors.
+	Reason string
+}
+
+// Validate implements policy.Settings.Validate().
+func (s Settings) Validate() error {
+	_, err := s.compile()
+	return err
+}
+
+// freezeLayouts are the layouts Freeze.Start and Freeze.End can be in.
+var freezeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+
+var days = map[string]time.Weekday{}
+
+func init() {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		days[strings.ToLower(d.String())] = d
+		days[strings.ToLower(d.String()[:3])] = d
+	}
+}
+
+// schedule is the compiled version of Settings.
+type schedule struct {
+	loc     *time.Location
+	windows []window
+	freezes []freeze
+}
+
+type window struct {
+	days       [7]bool
+	start, end time.Duration // Time since midnight.
+	overnight  bool
+}
+
+type freeze struct {
+	start, end time.Time
+	reason     string
+}
+
+func (s Settings) compile() (schedule, error) {
+	sched := schedule{loc: time.UTC}
+	if s.Timezone != "" {
+		loc, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			return schedule{}, fmt.Errorf("Timezone(%s) is not valid: %s", s.Timezone, err)
+		}
+		sched.loc = loc
+	}
+
+	for i, w := range s.Windows {
+		cw := window{}
+		if len(w.Days) == 0 {
+			for d := range cw.days {
+				cw.days[d] = true
+			}
+		}
+		for _, name := range w.Days {
+			d, ok := days[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				return schedule{}, fmt.Errorf("Windows[%d] has Day(%s) that is not a day of the week", i, name)
+			}
+			cw.days[d] = true
+		}
+
+		var err error
+		if cw.start, err = clock(w.Start); err != nil {
+			return schedule{}, fmt.Errorf("Windows[%d].Start: %s", i, err)
+		}
+		if cw.end, err = clock(w.End); err != nil {
+			return schedule{}, fmt.Errorf("Windows[%d].End: %s", i, err)
+		}
+		if cw.start == cw.end {
+			return schedule{}, fmt.Errorf("Windows[%d] has the same Start and End", i)
+		}
+		cw.overnight = cw.end < cw.start
+		sched.windows = append(sched.windows, cw)
+	}
+
+	for i, f := range s.Freezes {
+		cf := freeze{reason: f.Reason}
+		var err error
+		if cf.start, err = parseTime(f.Start, sched.loc); err != nil {
+			return schedule{}, fmt.Errorf("Freezes[%d].Start: %s", i, err)
+		}
+		if cf.end, err = parseTime(f.End, sched.loc); err != nil {
+			return schedule{}, fmt.Errorf("Freezes[%d].End: %s", i, err)
+		}
+		if !cf.end.After(cf.start) {
+			return schedule{}, fmt.Errorf("Freezes[%d] must End after it Starts", i)
+		}
+		sched.freezes = append(sched.freezes, cf)
+	}
+	return sched, nil
+}
+
+// clock parses a time of day like "22:00" into the time since midnight.
+func clock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q must be a time of day like 22:00", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// parseTime parses "s" in one of freezeLayouts. Layouts without a timezone are in "loc".
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range freezeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q must be like 2022-11-21, 2022-11-21 18:00 or an RFC3339 time", s)
+}
+
+// at returns the time "d" after midnight on the day of "day". This is done with time.Date()
+// so that times are correct on days that daylight savings starts or ends.
+func at(day time.Time, d time.Duration) time.Time {
+	y, m, dd := day.Date()
+	return time.Date(y, m, dd, int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// opensOn returns when the window opens and closes if it opens on the day of "day".
+func (w window) opensOn(day time.Time) (start, end time.Time, ok bool) {
+	if !w.days[day.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	endDay := day
+	if w.overnight {
+		endDay = day.AddDate(0, 0, 1)
+	}
+	return at(day, w.start), at(endDay, w.end), true
+}
+
+// check returns why a workflow cannot run at "t", or "" if it can. If it cannot, "until" is
+// when that will change.
+func (s schedule) check(t time.Time) (reason string, until time.Time) {
+	t = t.In(s.loc)
+
+	for _, f := range s.freezes {
+		if t.Before(f.start) || !t.Before(f.end) {
+			continue
+		}
+		if f.reason == "" {
+			return fmt.Sprintf("in a change freeze until %s", f.end.Format(time.RFC3339)), f.end
+		}
+		return fmt.Sprintf("in a change freeze(%s) until %s", f.reason, f.end.Format(time.RFC3339)), f.end
+	}
+
+	if len(s.windows) == 0 {
+		return "", time.Time{}
+	}
+
+	// A window that opened yesterday can still be open if it is overnight.
+	var next time.Time
+	for offset := -1; offset <= 7; offset++ {
+		day := t.AddDate(0, 0, offset)
+		for _, w := range s.windows {
+			start, end, ok := w.opensOn(day)
+			if !ok {
+				continue
+			}
+			if !t.Before(start) && t.Before(end) {
+				return "", time.Time{}
+			}
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+	return fmt.Sprintf("outside of maintenance windows, the next window opens at %s", next.Format(time.RFC3339)), next
+}
+
+// Policy implements policy.Policy, policy.Pauser and policy.Describer.
+type Policy struct{}
+
+// New is the constructor for Policy.
+func New() (Policy, error) {
+	return Policy{}, nil
+}
+
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Only allows a workflow inside weekly maintenance windows and outside change freezes, and pauses it outside of them",
+		Settings: []policy.Setting{
+			{Name: "Timezone", Desc: `The IANA timezone Windows and Freezes are in, like "America/New_York". Defaults to UTC`},
+			{
+				Name: "Windows",
+				Type: pb.ArgType_ArgObject,
+				Desc: `Weekly windows a workflow can run in, like {"Days": ["Sat"], "Start": "22:00", "End": "04:00"}. If not set, any time outside a Freeze`,
+			},
+			{
+				Name: "Freezes",
+				Type: pb.ArgType_ArgObject,
+				Desc: `Periods a workflow cannot run in, like {"Start": "2022-11-21", "End": "2022-11-28", "Reason": "Thanksgiving"}`,
+			},
+		},
+	}
+}
+
+// Run implements Policy.Run(). A WorkReq can only be submitted, executed or planned inside a
+// maintenance window and outside any freeze.
+func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
+	sched, err := compiled(settings)
+	if err != nil {
+		return err
+	}
+	if reason, _ := sched.check(now()); reason != "" {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// Pause implements policy.Pauser.Pause(). A WorkReq that is running is paused when a maintenance
+// window closes or a freeze starts, until the next time it could be executed.
+func (p Policy) Pause(ctx context.Context, req *pb.WorkReq, settings policy.Settings) (string, time.Time) {
+	sched, err := compiled(settings)
+	if err != nil {
+		// Settings are validated when the config is loaded, so this should never happen.
+		return err.Error(), time.Time{}
+	}
+	return sched.check(now())
+}
+
+func compiled(settings policy.Settings) (schedule, error) {
+	s, ok := settings.(Settings)
+	if !ok {
+		return schedule{}, fmt.Errorf("settings were not valid type, were %T", settings)
+	}
+	return s.compile()
+}
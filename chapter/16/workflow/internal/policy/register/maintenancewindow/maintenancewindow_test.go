@@ -0,0 +1,135 @@
+package maintenancewindow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestCheck(t *testing.T) {
+	s := Settings{
+		Timezone: "America/Los_Angeles",
+		Windows: []Window{
+			{Days: []string{"Sat", "sunday"}, Start: "08:00", End: "18:00"},
+			{Days: []string{"Tue"}, Start: "22:00", End: "04:00"},
+		},
+		Freezes: []Freeze{
+			{Start: "2022-11-26", End: "2022-11-27", Reason: "Thanksgiving"},
+		},
+	}
+	sched, err := s.compile()
+	if err != nil {
+		t.Fatalf("TestCheck: compile() had error: %s", err)
+	}
+
+	la, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(s string) time.Time {
+		d, err := time.ParseInLocation("2006-01-02 15:04", s, la)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		desc      string
+		t         time.Time
+		wantOpen  bool
+		wantUntil time.Time
+		wantIn    string
+	}{
+		{desc: "Inside a day window", t: date("2022-11-19 09:00"), wantOpen: true},
+		{desc: "Window End is not included", t: date("2022-11-19 18:00"), wantUntil: date("2022-11-20 08:00")},
+		{desc: "Before a window", t: date("2022-11-22 21:00"), wantUntil: date("2022-11-22 22:00")},
+		{desc: "Overnight window on its first day", t: date("2022-11-22 23:00"), wantOpen: true},
+		{desc: "Overnight window on the next day", t: date("2022-11-23 03:59"), wantOpen: true},
+		{desc: "After an overnight window", t: date("2022-11-23 04:00"), wantUntil: date("2022-11-26 08:00")},
+		{desc: "Other timezone", t: date("2022-11-19 09:00").UTC(), wantOpen: true},
+		{
+			desc:      "Freeze inside a window",
+			t:         date("2022-11-26 09:00"),
+			wantUntil: date("2022-11-27 00:00"),
+			wantIn:    "Thanksgiving",
+		},
+		{desc: "After a freeze", t: date("2022-11-27 08:00"), wantOpen: true},
+		// Daylight savings ended at 02:00 on 2022-11-06, the window still opens at 08:00 local time.
+		{desc: "Daylight savings", t: date("2022-11-05 19:00"), wantUntil: date("2022-11-06 08:00")},
+	}
+
+	for _, test := range tests {
+		reason, until := sched.check(test.t)
+		if test.wantOpen {
+			if reason != "" {
+				t.Errorf("TestCheck(%s): got reason %q, want \"\"", test.desc, reason)
+			}
+			continue
+		}
+		if reason == "" {
+			t.Errorf("TestCheck(%s): got reason == \"\", want a reason", test.desc)
+			continue
+		}
+		if !until.Equal(test.wantUntil) {
+			t.Errorf("TestCheck(%s): until: got %v, want %v", test.desc, until, test.wantUntil)
+		}
+		if !strings.Contains(reason, test.wantIn) {
+			t.Errorf("TestCheck(%s): got reason %q, want it to contain %q", test.desc, reason, test.wantIn)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc     string
+		settings Settings
+		wantErr  bool
+	}{
+		{desc: "No windows", settings: Settings{}},
+		{desc: "Every day", settings: Settings{Windows: []Window{{Start: "01:00", End: "02:00"}}}},
+		{desc: "Bad timezone", settings: Settings{Timezone: "Mars/Olympus"}, wantErr: true},
+		{desc: "Bad day", settings: Settings{Windows: []Window{{Days: []string{"Funday"}, Start: "01:00", End: "02:00"}}}, wantErr: true},
+		{desc: "Bad Start", settings: Settings{Windows: []Window{{Start: "25:00", End: "02:00"}}}, wantErr: true},
+		{desc: "Start is End", settings: Settings{Windows: []Window{{Start: "02:00", End: "02:00"}}}, wantErr: true},
+		{desc: "Bad freeze", settings: Settings{Freezes: []Freeze{{Start: "tomorrow", End: "2022-11-27"}}}, wantErr: true},
+		{desc: "Freeze ends first", settings: Settings{Freezes: []Freeze{{Start: "2022-11-27", End: "2022-11-26"}}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := test.settings.Validate()
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	defer func() { now = time.Now }()
+
+	s := Settings{Freezes: []Freeze{{Start: "2022-11-26", End: "2022-11-27"}}}
+	p, _ := New()
+	ctx := context.Background()
+
+	now = func() time.Time { return time.Date(2022, 11, 26, 12, 0, 0, 0, time.UTC) }
+	if err := p.Run(ctx, &pb.WorkReq{}, s); err == nil {
+		t.Errorf("TestPolicy: Run() inside a freeze: got err == nil, want err != nil")
+	}
+	if reason, _ := p.Pause(ctx, &pb.WorkReq{}, s); reason == "" {
+		t.Errorf("TestPolicy: Pause() inside a freeze: got reason == \"\", want a reason")
+	}
+
+	now = func() time.Time { return time.Date(2022, 11, 27, 12, 0, 0, 0, time.UTC) }
+	if err := p.Run(ctx, &pb.WorkReq{}, s); err != nil {
+		t.Errorf("TestPolicy: Run() after a freeze: got err == %s, want err == nil", err)
+	}
+	if reason, _ := p.Pause(ctx, &pb.WorkReq{}, s); reason != "" {
+		t.Errorf("TestPolicy: Pause() after a freeze: got reason %q, want \"\"", reason)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Package requireapprovals provides a policy that requires a workflow be approved by some number of
+identities other than the submitter before it can be executed. Approvals are given with the
+Approve RPC. Like restrictIdentities, this requires the server to use mTLS so that callers
+have identities.
+*/
+package requireapprovals
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This registers our policy with the service.
+func init() {
+	p, err := New()
+	if err != nil {
+		panic(err)
+	}
+	policy.Register("requireApprovals", p, Settings{})
+}
+
+// Settings provides settings for a specific implementation of our Policy.
+type Settings struct {
+	// Count is the number of approvals required.
+	Count int
+	// Approvers, if set, are the only identities whose approvals count.
+	Approvers []string
+}
+
+// Validate implements policy.Settings.Validate().
+func (s Settings) Validate() error {
+	if s.Count < 1 {
+		return fmt.Errorf("Count must be > 0")
+	}
+	if len(s.Approvers) > 0 && len(s.Approvers) < s.Count {
+		return fmt.Errorf("Approvers has fewer identities than Count, the workflow could never be approved")
+	}
+	return nil
+}
+
+func (s Settings) approver(id string) bool {
+	if len(s.Approvers) == 0 {
+		return true
+	}
+	for _, a := range s.Approvers {
+		if a == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy implements policy.Policy.
+type Policy struct{}
+
+// New is the constructor for Policy.
+func New() (Policy, error) {
+	return Policy{}, nil
+}
+
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Requires a workflow be approved by identities other than the submitter before it is executed",
+		Settings: []policy.Setting{
+			{Name: "Count", Type: pb.ArgType_ArgInt, Required: true, Desc: "The number of approvals required"},
+			{Name: "Approvers", Type: pb.ArgType_ArgStringList, Desc: "If set, the only identities whose approvals count"},
+		},
+	}
+}
+
+// Run implements Policy.Run(). A WorkReq can always be submitted, as approvals happen after that.
+// It can also always be planned, as a plan is made before the WorkReq is submitted and approved.
+// Executing a WorkReq requires it has enough approvals.
+func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
+	s, ok := settings.(Settings)
+	if !ok {
+		return fmt.Errorf("settings were not valid type, were %T", settings)
+	}
+
+	switch auth.OperationFrom(ctx) {
+	case auth.OpSubmit, auth.OpPlan:
+		return nil
+	}
+
+	approved := map[string]bool{}
+	for _, a := range req.Approvals {
+		if a.Approver == "" || a.Approver == req.Submitter || !s.approver(a.Approver) {
+			continue
+		}
+		approved[a.Approver] = true
+	}
+	if len(approved) < s.Count {
+		return fmt.Errorf("requires %d approvals from identities other than the submitter, has %d", s.Count, len(approved))
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package requireapprovals
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestRun(t *testing.T) {
+	s := Settings{Count: 2, Approvers: []string{"bob", "carol", "dave"}}
+	approvals := func(ids ...string) []*pb.Approval {
+		var a []*pb.Approval
+		for _, id := range ids {
+			a = append(a, &pb.Approval{Approver: id})
+		}
+		return a
+	}
+
+	tests := []struct {
+		desc      string
+		op        auth.Operation
+		approvals []*pb.Approval
+		wantErr   bool
+	}{
+		{desc: "Submit without approvals", op: auth.OpSubmit},
+		{desc: "Plan without approvals", op: auth.OpPlan},
+		{desc: "Exec without approvals", op: auth.OpExec, wantErr: true},
+		{desc: "Exec with too few approvals", op: auth.OpExec, approvals: approvals("bob"), wantErr: true},
+		{desc: "Exec with duplicate approvals", op: auth.OpExec, approvals: approvals("bob", "bob"), wantErr: true},
+		{desc: "Exec approved by submitter", op: auth.OpExec, approvals: approvals("bob", "alice"), wantErr: true},
+		{desc: "Exec approved by non-approver", op: auth.OpExec, approvals: approvals("bob", "eve"), wantErr: true},
+		{desc: "Exec with enough approvals", op: auth.OpExec, approvals: approvals("bob", "carol")},
+	}
+
+	for _, test := range tests {
+		req := &pb.WorkReq{Name: "test", Submitter: "alice", Approvals: test.approvals}
+		ctx := auth.WithOperation(context.Background(), test.op)
+
+		err := Policy{}.Run(ctx, req, s)
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestRun(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestRun(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
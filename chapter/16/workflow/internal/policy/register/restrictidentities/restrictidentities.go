@@ -0,0 +1,105 @@
+/*
+Package restrictidentities provides a policy that restricts which callers may submit or execute
+a workflow. The caller's identity is the Common Name of the certificate it used to connect to
+the server, so this requires the server to use mTLS. If the server does not, all callers have
+an empty identity and are denied.
+*/
+package restrictidentities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This registers our policy with the service.
+func init() {
+	p, err := New()
+	if err != nil {
+		panic(err)
+	}
+	policy.Register("restrictIdentities", p, Settings{})
+}
+
+// Settings provides settings for a specific implementation of our Policy.
+type Settings struct {
+	// Submit are the identities that may submit the workflow.
+	Submit []string
+	// Exec are the identities that may execute the workflow.
+	Exec []string
+}
+
+// Validate implements policy.Settings.Validate().
+func (s Settings) Validate() error {
+	for _, id := range append(append([]string{}, s.Submit...), s.Exec...) {
+		if id == "" {
+			return fmt.Errorf("identities cannot be empty strings")
+		}
+	}
+	return nil
+}
+
+func allowed(ids []string, id string) bool {
+	for _, allow := range ids {
+		if allow == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy implements policy.Policy.
+type Policy struct{}
+
+// New is the constructor for Policy.
+func New() (Policy, error) {
+	return Policy{}, nil
+}
+
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Restricts which identities may submit or execute a workflow",
+		Settings: []policy.Setting{
+			{Name: "Submit", Type: pb.ArgType_ArgStringList, Desc: "The identities that may submit the workflow"},
+			{Name: "Exec", Type: pb.ArgType_ArgStringList, Desc: "The identities that may execute the workflow"},
+		},
+	}
+}
+
+// Run implements Policy.Run(). The caller must be allowed to perform the Operation recorded in
+// the Context. Planning requires the caller be allowed to both submit and execute.
+func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
+	s, ok := settings.(Settings)
+	if !ok {
+		return fmt.Errorf("settings were not valid type, were %T", settings)
+	}
+
+	id := auth.Identity(ctx)
+	if id == "" {
+		return fmt.Errorf("caller has no identity, the server must use mTLS: %w", auth.ErrPermissionDenied)
+	}
+
+	var submit, exec bool
+	switch op := auth.OperationFrom(ctx); op {
+	case auth.OpSubmit:
+		submit = true
+	case auth.OpExec:
+		exec = true
+	case auth.OpPlan:
+		submit, exec = true, true
+	default:
+		return fmt.Errorf("operation(%s) is not known to this policy", op)
+	}
+
+	if submit && !allowed(s.Submit, id) {
+		return fmt.Errorf("identity(%s) may not submit workflow(%s): %w", id, req.Name, auth.ErrPermissionDenied)
+	}
+	if exec && !allowed(s.Exec, id) {
+		return fmt.Errorf("identity(%s) may not execute workflow(%s): %w", id, req.Name, auth.ErrPermissionDenied)
+	}
+	return nil
+}
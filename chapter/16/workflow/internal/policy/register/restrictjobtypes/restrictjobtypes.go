@@ -55,6 +55,16 @@ func New() (Policy, error) {
 	return Policy{}, nil
 }
 
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Only allows a workflow to contain certain Jobs",
+		Settings: []policy.Setting{
+			{Name: "AllowedJobs", Type: pb.ArgType_ArgStringList, Required: true, Desc: "The names of the Jobs that are allowed"},
+		},
+	}
+}
+
 // Run implements Policy.Run().
 func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
 	const errMsg = "block(%d)/job(%d) is a type(%s) that is not allowed"
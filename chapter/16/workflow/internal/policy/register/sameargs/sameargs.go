@@ -97,6 +97,21 @@ func New() (Policy, error) {
 	return Policy{}, nil
 }
 
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Requires every Job of a type to have the same value for certain args",
+		Settings: []policy.Setting{
+			{
+				Name:     "Jobs",
+				Type:     pb.ArgType_ArgObject,
+				Required: true,
+				Desc:     `A map of Job names to the args that must be the same in every Job with that name, like {"diskErase": ["site"]}`,
+			},
+		},
+	}
+}
+
 // Run implements Policy.Run().
 func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
 	s, ok := settings.(Settings)
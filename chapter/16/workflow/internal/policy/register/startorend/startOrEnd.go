@@ -37,6 +37,9 @@ type Settings struct {
 	// AllowedBeforeOrAfter are job types that are allowed before
 	// this job if Start == true, or after this job if End == true.
 	AllowedBeforeOrAfter []string
+	// AlwaysRun indicates the last block must have RunOn set to RunOnAlways, so that
+	// the job is run even if the WorkReq fails or is stopped. Only valid if End == true.
+	AlwaysRun bool
 
 	allowed map[string]bool
 }
@@ -52,6 +55,9 @@ func (s Settings) Validate() error {
 	if !s.Start && !s.End {
 		return fmt.Errorf("either Start of End must be set")
 	}
+	if s.AlwaysRun && !s.End {
+		return fmt.Errorf("AlwaysRun can only be set if End is set")
+	}
 
 	for _, name := range s.AllowedBeforeOrAfter {
 		if _, err := jobs.GetJob(name); err != nil {
@@ -78,6 +84,25 @@ func New() (Policy, error) {
 	return Policy{}, nil
 }
 
+// Describe implements policy.Describer.Describe().
+func (p Policy) Describe() policy.Schema {
+	return policy.Schema{
+		Desc: "Requires a Job be in the first or last Block of a workflow",
+		Settings: []policy.Setting{
+			{Name: "JobName", Required: true, Desc: "The name of the Job that must be present"},
+			{Name: "MustArgs", Type: pb.ArgType_ArgStringMap, Desc: "Args the Job must have with these values"},
+			{Name: "Start", Type: pb.ArgType_ArgBool, Desc: "The Job must be in the first Block"},
+			{Name: "End", Type: pb.ArgType_ArgBool, Desc: "The Job must be in the last Block"},
+			{
+				Name: "AllowedBeforeOrAfter",
+				Type: pb.ArgType_ArgStringList,
+				Desc: "Jobs allowed before the Job if Start is set, or after it if End is set",
+			},
+			{Name: "AlwaysRun", Type: pb.ArgType_ArgBool, Desc: "The last Block must run on RunOnAlways. Only valid with End"},
+		},
+	}
+}
+
 // Run implements Policy.Run().
 func (p Policy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
 	s, ok := settings.(Settings)
@@ -97,14 +122,58 @@ func (p Policy) eachWorkReq(ctx context.Context, req *pb.WorkReq, s Settings) er
 		return err
 	}
 
-	err := p.endOfBlock(ctx, req.Blocks[len(req.Blocks)-1].Jobs, s)
+	last := req.Blocks[len(req.Blocks)-1]
+	err := p.endOfBlock(ctx, last.Jobs, s)
 	if err != nil {
 		err = fmt.Errorf("requires Job(%s) in the last block: %s", s.JobName, err)
 		return err
 	}
+	if s.AlwaysRun {
+		if last.RunOn != pb.RunOn_RunOnAlways {
+			return fmt.Errorf("requires the last block to have RunOn set to RunOnAlways")
+		}
+		if !runsLast(req) {
+			return fmt.Errorf("requires the last block to depend on all other blocks")
+		}
+	}
 	return nil
 }
 
+// runsLast returns true if the last block in the WorkReq can only run after all other blocks
+// have finished. This is always true unless blocks have dependencies.
+func runsLast(req *pb.WorkReq) bool {
+	deps := false
+	ids := map[string]*pb.Block{}
+	for _, b := range req.Blocks {
+		if len(b.DependsOn) > 0 {
+			deps = true
+		}
+		if b.Id != "" {
+			ids[b.Id] = b
+		}
+	}
+	if !deps {
+		return true
+	}
+
+	// Find every block the last block depends on, directly or indirectly.
+	seen := map[*pb.Block]bool{}
+	queue := []*pb.Block{req.Blocks[len(req.Blocks)-1]}
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		for _, id := range b.DependsOn {
+			dep, ok := ids[id]
+			if !ok || seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			queue = append(queue, dep)
+		}
+	}
+	return len(seen) == len(req.Blocks)-1
+}
+
 func (p Policy) startOfBlock(ctx context.Context, block []*pb.Job, s Settings) error {
 	for _, job := range block {
 		if job.Name == s.JobName {
@@ -119,7 +188,7 @@ func (p Policy) startOfBlock(ctx context.Context, block []*pb.Job, s Settings) e
 }
 
 func (p Policy) endOfBlock(ctx context.Context, block []*pb.Job, s Settings) error {
-	for i := len(block); i > 0; i-- {
+	for i := len(block) - 1; i >= 0; i-- {
 		job := block[i]
 		if job.Name == s.JobName {
 			return p.mustHave(ctx, job, s)
@@ -127,7 +196,7 @@ func (p Policy) endOfBlock(ctx context.Context, block []*pb.Job, s Settings) err
 		if s.allowed[job.Name] {
 			continue
 		}
-		return fmt.Errorf("not found at the beginning of the block")
+		return fmt.Errorf("not found at the end of the block")
 	}
 	return fmt.Errorf("not found in the block at all")
 }
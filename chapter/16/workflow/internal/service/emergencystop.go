@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"log"
+	"path"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// EmergencyStop implements our gRPC EmergencyStop admin service. It reads and changes es.Data.
+type EmergencyStop struct {
+	// admins are the identities allowed to call SetStatus(). If empty, anyone can.
+	admins map[string]bool
+
+	// Required for gRPC to run, makes sure we have all the methods defined.
+	pb.UnimplementedEmergencyStopServer
+}
+
+// NewEmergencyStop creates a new EmergencyStop service. If "admins" is set, only those identities
+// can change the emergency stop status, which requires the server to use mTLS.
+func NewEmergencyStop(admins []string) *EmergencyStop {
+	e := &EmergencyStop{admins: map[string]bool{}}
+	for _, a := range admins {
+		e.admins[a] = true
+	}
+	return e
+}
+
+var esSetRateLimit = make(chan struct{}, 10)
+
+// SetStatus sets the emergency stop status of a workflow type. Changing an unscoped entry to
+// ESStop stops running workflows of that type before this returns. Changing a scoped entry
+// to ESStop causes matching Jobs to fail when they are about to run.
+func (e *EmergencyStop) SetStatus(ctx context.Context, req *pb.ESSetStatusReq) (*pb.ESSetStatusResp, error) {
+	select {
+	case esSetRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-esSetRateLimit }()
+
+	id := auth.Identity(ctx)
+	if len(e.admins) > 0 && !e.admins[id] {
+		return nil, status.Errorf(codes.PermissionDenied, "identity(%s) cannot change emergency stop status", id)
+	}
+
+	var s es.Status
+	switch req.Status {
+	case pb.ESStatus_ESGo:
+		s = es.Go
+	case pb.ESStatus_ESStop:
+		s = es.Stop
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "status must be ESGo or ESStop, was %v", req.Status)
+	}
+
+	if _, err := path.Match(req.Machine, ""); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "machine(%s) is not a valid pattern: %s", req.Machine, err)
+	}
+
+	info := es.Info{Name: req.Name, Status: s, Site: req.Site, Machine: req.Machine, Job: req.Job}
+	if err := es.Data.SetStatus(info); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "could not set emergency stop status: %s", err)
+	}
+	if info.Scoped() {
+		log.Printf("Emergency stop for %s %s set to %s by identity(%s)", req.Name, info.Scope(), s, id)
+	} else {
+		log.Printf("Emergency stop for %s set to %s by identity(%s)", req.Name, s, id)
+	}
+	return &pb.ESSetStatusResp{}, nil
+}
+
+var esGetRateLimit = make(chan struct{}, 10)
+
+// GetStatus returns the emergency stop status of a workflow type.
+func (e *EmergencyStop) GetStatus(ctx context.Context, req *pb.ESGetStatusReq) (*pb.ESGetStatusResp, error) {
+	select {
+	case esGetRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-esGetRateLimit }()
+
+	info, ok := es.Data.Info(req.Name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no emergency stop entry for %s, workflows of this type cannot run", req.Name)
+	}
+	return &pb.ESGetStatusResp{Info: esInfoToProto(info)}, nil
+}
+
+var esListRateLimit = make(chan struct{}, 10)
+
+// List returns all emergency stop entries.
+func (e *EmergencyStop) List(ctx context.Context, req *pb.ESListReq) (*pb.ESListResp, error) {
+	select {
+	case esListRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-esListRateLimit }()
+
+	resp := &pb.ESListResp{}
+	for _, info := range es.Data.List() {
+		resp.Infos = append(resp.Infos, esInfoToProto(info))
+	}
+	return resp, nil
+}
+
+func esInfoToProto(info es.Info) *pb.ESInfo {
+	p := &pb.ESInfo{Name: info.Name, Site: info.Site, Machine: info.Machine, Job: info.Job}
+	switch info.Status {
+	case es.Go:
+		p.Status = pb.ESStatus_ESGo
+	case es.Stop:
+		p.Status = pb.ESStatus_ESStop
+	}
+	return p
+}
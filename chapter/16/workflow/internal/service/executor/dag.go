@@ -0,0 +1,238 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// hasDeps returns true if any Block in the WorkReq declares dependencies.
+func hasDeps(req *pb.WorkReq) bool {
+	for _, b := range req.Blocks {
+		if len(b.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// blockDeps returns the indexes of the Blocks that each Block in "req" depends on. If no Block
+// declares dependencies, each Block depends on the Block before it, which executes them in order.
+// "req" must have passed validateDeps().
+func blockDeps(req *pb.WorkReq) [][]int {
+	deps := make([][]int, len(req.Blocks))
+
+	if !hasDeps(req) {
+		for i := 1; i < len(req.Blocks); i++ {
+			deps[i] = []int{i - 1}
+		}
+		return deps
+	}
+
+	ids := map[string]int{}
+	for i, b := range req.Blocks {
+		if b.Id != "" {
+			ids[b.Id] = i
+		}
+	}
+	for i, b := range req.Blocks {
+		for _, id := range b.DependsOn {
+			deps[i] = append(deps[i], ids[id])
+		}
+	}
+	return deps
+}
+
+// validateDeps validates that Block IDs are unique, that dependencies refer to Blocks that
+// exist and that there are no cycles.
+func validateDeps(req *pb.WorkReq) error {
+	ids := map[string]int{}
+	for i, b := range req.Blocks {
+		if b.Id == "" {
+			continue
+		}
+		if prev, ok := ids[b.Id]; ok {
+			return fmt.Errorf("Block(%d) and Block(%d) have the same ID(%s)", prev, i, b.Id)
+		}
+		ids[b.Id] = i
+	}
+
+	for i, b := range req.Blocks {
+		seen := map[string]bool{}
+		for _, id := range b.DependsOn {
+			if seen[id] {
+				return fmt.Errorf("Block(%d) depends on Block(%s) more than once", i, id)
+			}
+			seen[id] = true
+
+			dep, ok := ids[id]
+			if !ok {
+				return fmt.Errorf("Block(%d) depends on Block(%s), which does not exist", i, id)
+			}
+			if dep == i {
+				return fmt.Errorf("Block(%d) depends on itself", i)
+			}
+		}
+	}
+
+	// Remove Blocks that have no remaining dependencies until we can't. If any Blocks are left,
+	// they are part of a cycle.
+	deps := blockDeps(req)
+	remaining := make([]int, len(deps))
+	dependents := make([][]int, len(deps))
+	for i, d := range deps {
+		remaining[i] = len(d)
+		for _, dep := range d {
+			dependents[dep] = append(dependents[dep], i)
+		}
+	}
+	var ready []int
+	for i, r := range remaining {
+		if r == 0 {
+			ready = append(ready, i)
+		}
+	}
+	removed := 0
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		removed++
+		for _, dep := range dependents[i] {
+			remaining[dep]--
+			if remaining[dep] == 0 {
+				ready = append(ready, dep)
+			}
+		}
+	}
+	if removed != len(deps) {
+		for i, r := range remaining {
+			if r > 0 {
+				return fmt.Errorf("Block(%d) is part of a dependency cycle", i)
+			}
+		}
+	}
+	return nil
+}
+
+// runBlocks executes each Block once all the Blocks it depends on have finished. Blocks that
+// are ready at the same time are executed concurrently. If a Block returns an error, such as
+// from a fatal Job error or "ctx" being cancelled, we are stopped: running Blocks are allowed to
+// finish and only Blocks that run on failure or always are started, using "cleanupCtx".
+func (w *Work) runBlocks(ctx, cleanupCtx context.Context) {
+	deps := blockDeps(w.req)
+
+	started := make([]bool, len(w.req.Blocks))
+	finished := make([]bool, len(w.req.Blocks))
+	for i, stat := range w.status.Blocks {
+		// Blocks that have already finished are from an earlier run.
+		if stat.Status.Done() {
+			started[i] = true
+			finished[i] = true
+		}
+	}
+
+	ready := func(i int) bool {
+		for _, dep := range deps[i] {
+			if !finished[dep] {
+				return false
+			}
+		}
+		return true
+	}
+
+	// waiting returns true if a Block is ready to be started.
+	waiting := func() bool {
+		for i := range w.req.Blocks {
+			if !started[i] && ready(i) {
+				return true
+			}
+		}
+		return false
+	}
+
+	doneCh := make(chan blockResult, len(w.req.Blocks))
+	running := 0
+	stop := false
+	for {
+		if !stop && w.waitIfPaused(ctx) != nil {
+			stop = true
+		}
+		// Policies can pause us before we start a new Block, such as when a maintenance
+		// window closes. Blocks that are running are allowed to finish.
+		if !stop && waiting() && w.waitForPolicies(ctx) != nil {
+			stop = true
+		}
+		if ctx.Err() != nil {
+			stop = true
+		}
+
+		blockCtx := ctx
+		if stop {
+			blockCtx = cleanupCtx
+		}
+
+		// Skipping a Block can make others ready, so loop until nothing changes.
+		for changed := true; changed; {
+			changed = false
+			for i, block := range w.req.Blocks {
+				if started[i] || !ready(i) {
+					continue
+				}
+				started[i] = true
+
+				if !w.shouldRun(block, stop) {
+					w.setBlockStatus(w.status.Blocks[i], pb.Status_StatusSkipped)
+					finished[i] = true
+					changed = true
+					continue
+				}
+				running++
+
+				i := i
+				block := block
+				go func() {
+					doneCh <- blockResult{i: i, err: w.runJobs(blockCtx, block, w.status.Blocks[i])}
+				}()
+			}
+		}
+
+		if running == 0 {
+			return
+		}
+		result := <-doneCh
+		running--
+		finished[result.i] = true
+		if result.err != nil {
+			stop = true
+		}
+	}
+}
+
+// shouldRun returns true if "block" should be executed based on its RunOn setting. "stopped"
+// indicates if the Work has been stopped.
+func (w *Work) shouldRun(block *pb.Block, stopped bool) bool {
+	switch block.RunOn {
+	case pb.RunOn_RunOnAlways:
+		return true
+	case pb.RunOn_RunOnFailure:
+		if stopped {
+			return true
+		}
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		for _, stat := range w.status.Blocks {
+			if stat.Status == pb.Status_StatusFailed {
+				return true
+			}
+		}
+		return false
+	}
+	return !stopped
+}
+
+// blockResult is the result of running a Block with runJobs().
+type blockResult struct {
+	i   int
+	err error
+}
@@ -0,0 +1,336 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	"github.com/kylelemons/godebug/pretty"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// orderJob is a Job that records its "id" arg in the order Jobs are run. When registered as
+// "testBarrier", it first waits until every "testBarrier" Job in a test is running, which can
+// only happen if they are running concurrently. When registered as "testFatal", it returns
+// a fatal error instead of recording its id. Its "site" arg is the site it targets.
+type orderJob struct {
+	id    string
+	site  string
+	wait  bool
+	fatal bool
+}
+
+var (
+	barrier  sync.WaitGroup
+	orderMu  sync.Mutex
+	runOrder []string
+)
+
+func init() {
+	jobs.Register("testBarrier", func() jobs.Job { return &orderJob{wait: true} })
+	jobs.Register("testOrder", func() jobs.Job { return &orderJob{} })
+	jobs.Register("testFatal", func() jobs.Job { return &orderJob{fatal: true} })
+}
+
+func (o *orderJob) Validate(job *pb.Job) error {
+	o.id = job.Args["id"]
+	o.site = job.Args["site"]
+	return nil
+}
+
+func (o *orderJob) Targets() jobs.Targets {
+	if o.site == "" {
+		return jobs.Targets{}
+	}
+	return jobs.Targets{Sites: []string{o.site}}
+}
+
+func (o *orderJob) Run(ctx context.Context) error {
+	if o.fatal {
+		return jobs.Fatalf("Job(%s) failed", o.id)
+	}
+	if o.wait {
+		barrier.Done()
+		done := make(chan struct{})
+		go func() {
+			barrier.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("Job(%s) timed out waiting for other Jobs, Blocks did not run concurrently", o.id)
+		}
+	}
+
+	orderMu.Lock()
+	defer orderMu.Unlock()
+	runOrder = append(runOrder, o.id)
+	return nil
+}
+
+func block(id, jobName string, deps ...string) *pb.Block {
+	return &pb.Block{
+		Id:        id,
+		DependsOn: deps,
+		Jobs:      []*pb.Job{{Name: jobName, Args: map[string]string{"id": id}}},
+	}
+}
+
+// statusFor returns a StatusResp for "req" where nothing has started.
+func statusFor(req *pb.WorkReq) *pb.StatusResp {
+	status := &pb.StatusResp{Name: req.Name}
+	for _, b := range req.Blocks {
+		bs := &pb.BlockStatus{Id: b.Id, Status: pb.Status_StatusNotStarted}
+		for _, j := range b.Jobs {
+			bs.Jobs = append(bs.Jobs, &pb.JobStatus{Name: j.Name, Args: j.Args, Status: pb.Status_StatusNotStarted})
+		}
+		status.Blocks = append(status.Blocks, bs)
+	}
+	return status
+}
+
+func TestValidateDeps(t *testing.T) {
+	tests := []struct {
+		desc    string
+		blocks  []*pb.Block
+		wantErr bool
+	}{
+		{
+			desc:   "No dependencies",
+			blocks: []*pb.Block{block("", "testOrder"), block("", "testOrder")},
+		},
+		{
+			desc:   "Diamond",
+			blocks: []*pb.Block{block("a", "testOrder"), block("b", "testOrder", "a"), block("c", "testOrder", "a"), block("d", "testOrder", "b", "c")},
+		},
+		{
+			desc:    "Duplicate ID",
+			blocks:  []*pb.Block{block("a", "testOrder"), block("a", "testOrder")},
+			wantErr: true,
+		},
+		{
+			desc:    "Unknown dependency",
+			blocks:  []*pb.Block{block("a", "testOrder", "b")},
+			wantErr: true,
+		},
+		{
+			desc:    "Depends on itself",
+			blocks:  []*pb.Block{block("a", "testOrder", "a")},
+			wantErr: true,
+		},
+		{
+			desc:    "Duplicate dependency",
+			blocks:  []*pb.Block{block("a", "testOrder"), block("b", "testOrder", "a", "a")},
+			wantErr: true,
+		},
+		{
+			desc:    "Cycle",
+			blocks:  []*pb.Block{block("a", "testOrder", "c"), block("b", "testOrder", "a"), block("c", "testOrder", "b")},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := validateDeps(&pb.WorkReq{Blocks: test.blocks})
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestValidateDeps(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestValidateDeps(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestBlockDeps(t *testing.T) {
+	tests := []struct {
+		desc   string
+		blocks []*pb.Block
+		want   [][]int
+	}{
+		{
+			desc:   "No dependencies runs in order",
+			blocks: []*pb.Block{block("a", "testOrder"), block("", "testOrder"), block("c", "testOrder")},
+			want:   [][]int{nil, {0}, {1}},
+		},
+		{
+			desc:   "Dependencies",
+			blocks: []*pb.Block{block("a", "testOrder"), block("b", "testOrder"), block("c", "testOrder", "b", "a")},
+			want:   [][]int{nil, nil, {1, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		got := blockDeps(&pb.WorkReq{Blocks: test.blocks})
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestBlockDeps(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestRunBlocksConcurrent(t *testing.T) {
+	req := &pb.WorkReq{
+		Name: "test",
+		Blocks: []*pb.Block{
+			block("a", "testBarrier"),
+			block("b", "testBarrier"),
+			block("c", "testOrder", "a", "b"),
+		},
+	}
+	status := statusFor(req)
+	barrier.Add(2)
+
+	w := New(req, status)
+
+	// Drain status updates the same way the service does.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range w.ch {
+		}
+	}()
+
+	w.runBlocks(context.Background(), context.Background())
+	close(w.ch)
+	<-done
+
+	for i, bs := range status.Blocks {
+		if bs.Status != pb.Status_StatusCompleted {
+			t.Errorf("TestRunBlocksConcurrent: Block(%d): got status %v, want %v: %s", i, bs.Status, pb.Status_StatusCompleted, bs.Jobs[0].Error)
+		}
+	}
+
+	orderMu.Lock()
+	defer orderMu.Unlock()
+	if len(runOrder) != 3 || runOrder[2] != "c" {
+		t.Errorf("TestRunBlocksConcurrent: got run order %v, want Block(c) to run last", runOrder)
+	}
+}
+
+func TestRunBlocksRunOn(t *testing.T) {
+	tests := []struct {
+		desc   string
+		first  string
+		runOns []pb.RunOn
+		want   []pb.Status
+	}{
+		{
+			desc:   "Success",
+			first:  "testOrder",
+			runOns: []pb.RunOn{pb.RunOn_RunOnSuccess, pb.RunOn_RunOnFailure, pb.RunOn_RunOnAlways},
+			want:   []pb.Status{pb.Status_StatusCompleted, pb.Status_StatusSkipped, pb.Status_StatusCompleted},
+		},
+		{
+			desc:   "Fatal error",
+			first:  "testFatal",
+			runOns: []pb.RunOn{pb.RunOn_RunOnSuccess, pb.RunOn_RunOnFailure, pb.RunOn_RunOnAlways},
+			want:   []pb.Status{pb.Status_StatusSkipped, pb.Status_StatusCompleted, pb.Status_StatusCompleted},
+		},
+	}
+
+	for _, test := range tests {
+		req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{block("first", test.first)}}
+		for i, runOn := range test.runOns {
+			b := block(fmt.Sprintf("block%d", i), "testOrder")
+			b.RunOn = runOn
+			req.Blocks = append(req.Blocks, b)
+		}
+		status := statusFor(req)
+
+		w := New(req, status)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for range w.ch {
+			}
+		}()
+
+		w.runBlocks(context.Background(), context.Background())
+		close(w.ch)
+		<-done
+
+		var got []pb.Status
+		for _, bs := range status.Blocks[1:] {
+			got = append(got, bs.Status)
+		}
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestRunBlocksRunOn(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestOnTransition(t *testing.T) {
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{block("a", "testOrder"), block("b", "testFatal")}}
+	status := statusFor(req)
+
+	w := New(req, status)
+	var got []Transition
+	w.OnTransition(func(t Transition) {
+		got = append(got, t)
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range w.ch {
+		}
+	}()
+
+	w.runBlocks(context.Background(), context.Background())
+	close(w.ch)
+	<-done
+
+	want := []Transition{
+		{Block: 0, Job: -1, From: pb.Status_StatusNotStarted, To: pb.Status_StatusRunning},
+		{Block: 0, Job: 0, From: pb.Status_StatusNotStarted, To: pb.Status_StatusRunning},
+		{Block: 0, Job: 0, From: pb.Status_StatusRunning, To: pb.Status_StatusCompleted},
+		{Block: 0, Job: -1, From: pb.Status_StatusRunning, To: pb.Status_StatusCompleted},
+		{Block: 1, Job: -1, From: pb.Status_StatusNotStarted, To: pb.Status_StatusRunning},
+		{Block: 1, Job: 0, From: pb.Status_StatusNotStarted, To: pb.Status_StatusRunning},
+		{Block: 1, Job: 0, From: pb.Status_StatusRunning, To: pb.Status_StatusFailed, Err: "Job(b) failed"},
+		{Block: 1, Job: -1, From: pb.Status_StatusRunning, To: pb.Status_StatusFailed},
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestOnTransition: -want/+got:\n%s", diff)
+	}
+}
+
+func TestRunBlocksScopedStop(t *testing.T) {
+	stopped := block("a", "testOrder")
+	stopped.Jobs[0].Args["site"] = "stopped"
+	stopped.Jobs = append(stopped.Jobs, &pb.Job{Name: "testOrder", Args: map[string]string{"id": "a2", "site": "ok"}})
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{stopped, block("b", "testOrder")}}
+	status := statusFor(req)
+
+	w := New(req, status)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range w.ch {
+		}
+	}()
+
+	w.runBlocks(context.Background(), context.Background())
+	close(w.ch)
+	<-done
+
+	// Only the Job for the stopped site fails, the rest of the Work continues.
+	want := [][]pb.Status{
+		{pb.Status_StatusFailed, pb.Status_StatusCompleted},
+		{pb.Status_StatusCompleted},
+	}
+	var got [][]pb.Status
+	for _, bs := range status.Blocks {
+		var l []pb.Status
+		for _, js := range bs.Jobs {
+			l = append(l, js.Status)
+		}
+		got = append(got, l)
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestRunBlocksScopedStop: -want/+got:\n%s", diff)
+	}
+}
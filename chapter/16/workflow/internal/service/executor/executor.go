@@ -13,36 +13,121 @@ After creating a Work object, validate it:
 To run the Work object, do:
 	ch := work.Run()
 
+A running Work object can be paused, resumed or cancelled:
+	if err := work.Pause(); err != nil {
+		// Do something
+	}
+	if err := work.Resume(); err != nil {
+		// Do something
+	}
+	if err := work.Cancel(); err != nil {
+		// Do something
+	}
+
+Pausing only takes effect at Block and Job boundaries, Jobs that are already running
+will finish.
+
+Jobs that implement jobs.Locker get exclusive leases on the resources they change before each
+attempt, so Jobs in other workflows cannot change them at the same time. Call SetID() before
+Run() so that leases are held under the workflow's ID. If another workflow holds a lease, the
+Job waits or fails depending on its on_locked setting.
+
+Policies configured for the WorkReq that implement policy.Pauser, such as maintenanceWindow,
+are checked before each new Block is started. If one says the WorkReq cannot continue, the Work
+is paused until it can. Blocks that are already running finish.
+
+Blocks are executed one at a time in order, unless a Block in the WorkReq has
+dependencies. In that case, each Block is executed as soon as the Blocks it depends on
+have finished, so independent Blocks execute concurrently.
+
+A Block's RunOn setting decides if it is executed when the Blocks before it have finished.
+Blocks that use RunOnFailure or RunOnAlways are executed even after the Work was stopped by
+a fatal error, Cancel() or an emergency stop, which allows them to do cleanup.
+
+To be told about every change in the status of the Work, its Blocks and its Jobs, such as
+for auditing, call OnTransition() before Run():
+	work.OnTransition(func(t executor.Transition) {
+		// Do something
+	})
+
+Each Block and Job is recorded as an OpenTelemetry span that is a child of the span in the
+Context passed to Run(). A Job's Context has its span, so Jobs can add their own spans.
+
 Once Run() returns, the pb.Status object passed will contain the results of running the WorkReq.
 */
 package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/lease"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/config"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
+var tracer = otel.Tracer("github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/executor")
+
+// policyRecheck is the longest we wait before checking the policies that paused a Work again,
+// as the policy config can change.
+const policyRecheck = time.Minute
+
 // Work is an executor for executing a WorkReq received by the server.
 type Work struct {
 	req *pb.WorkReq
+	// id is the ID of the WorkReq, which holds our leases. Set with SetID().
+	id string
 
 	mu     sync.Mutex
 	status *pb.StatusResp
 	ch     chan *pb.StatusResp
+	// cancel cancels the Context that Run() is using. Set in Run().
+	cancel context.CancelFunc
+	// cancelled indicates that Cancel() was called.
+	cancelled bool
+	// pause is non-nil while we are paused. It is closed by Resume().
+	pause chan struct{}
+	// userPaused indicates Pause() was called and Resume() has not been. A policy that stops
+	// pausing us does not resume us while this is set.
+	userPaused bool
+	// onTransition is called with each change in status. Set with OnTransition().
+	onTransition func(Transition)
+}
+
+// Transition describes a change in the status of the Work, one of its Blocks or one of its Jobs.
+type Transition struct {
+	// Block is the index of the Block, or -1 if the Work's status changed.
+	Block int
+	// Job is the index of the Job in the Block, or -1 if a Job's status did not change.
+	Job int
+	// From is the status before the change.
+	From pb.Status
+	// To is the status after the change.
+	To pb.Status
+	// Err is the error recorded with the status, if any.
+	Err string
+	// WasEsStopped indicates the Work was stopped with emergency stop.
+	WasEsStopped bool
 }
 
-// New is the constructor for Work.
+// New is the constructor for Work. If status is from a WorkReq that was partially run,
+// such as when recovering from a server restart, Run() will skip any Blocks that have finished
+// and any Jobs that have completed. If status is StatusPaused, Run() will start in the paused state.
 func New(req *pb.WorkReq, status *pb.StatusResp) *Work {
 	return &Work{
 		req:    req,
@@ -51,12 +136,50 @@ func New(req *pb.WorkReq, status *pb.StatusResp) *Work {
 	}
 }
 
+// OnTransition sets "f" to be called with each change in the status of the Work, its Blocks and
+// its Jobs. "f" is called while the Work is locked, so it must not call methods on the Work.
+// This must be called before Run().
+func (w *Work) OnTransition(f func(Transition)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onTransition = f
+}
+
+// SetID sets the ID of the WorkReq, which is used as the holder of the leases our Jobs acquire
+// and in errors when another workflow waits on them. This must be called before Run().
+func (w *Work) SetID(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.id = id
+}
+
 // Run validates that a WorkReq is correct and passed policy, then executes it.
 func (w *Work) Run(ctx context.Context) chan *pb.StatusResp {
-	w.setWorkStatus(pb.Status_StatusRunning, false)
+	// Blocks that run after we are stopped use parent, as ctx will have been cancelled.
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+
+	w.mu.Lock()
+	w.cancel = cancel
+	// A Work that was paused by a policy starts running, as the policy is checked again
+	// before the next Block is started.
+	paused := w.status.Status == pb.Status_StatusPaused && w.status.PausedBy == ""
+	w.status.PausedBy = ""
+	if paused {
+		w.pause = make(chan struct{})
+		w.userPaused = true
+	}
+	w.mu.Unlock()
+
+	if paused {
+		w.setWorkStatus(pb.Status_StatusPaused, false)
+	} else {
+		w.setWorkStatus(pb.Status_StatusRunning, false)
+	}
 
 	go func() {
 		defer close(w.ch)
+		defer cancel()
 
 		esCh, cancelES := es.Data.Subscribe(w.req.Name)
 		defer cancelES()
@@ -66,10 +189,6 @@ func (w *Work) Run(ctx context.Context) chan *pb.StatusResp {
 			return
 		}
 
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithCancel(ctx)
-		defer cancel()
-
 		// If we get an emergency stop, cancel our context.
 		// If the context gets cancelled, then just exit.
 		go func() {
@@ -83,37 +202,215 @@ func (w *Work) Run(ctx context.Context) chan *pb.StatusResp {
 			}
 		}()
 
-		// Loop through each block one at a time and execute the Jobs located in them
-		// at the rate limit defined for the block.
-		for i, block := range w.req.Blocks {
-			if ctx.Err() != nil {
-				break
-			}
-			stat := w.status.Blocks[i]
+		// Execute each block once its dependencies have finished (or one at a time if there
+		// are no dependencies) and execute the Jobs located in them at the rate limit defined
+		// for the block.
+		w.runBlocks(ctx, parent)
 
-			if err := w.runJobs(ctx, block, stat); err != nil {
-				break
-			}
+		w.setFinalStatus()
+	}()
+
+	return w.ch
+}
+
+// setFinalStatus records our final state based on if we were cancelled or any of our blocks failed.
+func (w *Work) setFinalStatus() {
+	w.mu.Lock()
+	cancelled := w.cancelled
+	esStopped := w.status.WasEsStopped
+	w.mu.Unlock()
+
+	if cancelled {
+		w.setWorkStatus(pb.Status_StatusCancelled, esStopped)
+		return
+	}
+
+	for _, block := range w.status.Blocks {
+		if block.Status == pb.Status_StatusFailed {
+			w.setWorkStatus(pb.Status_StatusFailed, esStopped)
+			return
+		}
+	}
+	if esStopped {
+		w.setWorkStatus(pb.Status_StatusFailed, esStopped)
+		return
+	}
+	w.setWorkStatus(pb.Status_StatusCompleted, false)
+}
+
+// Cancel cancels a running Work. Jobs that are running will have their Context cancelled
+// and no new Blocks or Jobs will be started, except Blocks that run on failure or always.
+func (w *Work) Cancel() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel == nil {
+		return fmt.Errorf("Work has not been started")
+	}
+	if w.cancelled {
+		return fmt.Errorf("Work has already been cancelled")
+	}
+	if w.status.Status.Done() {
+		return fmt.Errorf("Work has already finished with status %v", w.status.Status)
+	}
+	w.cancelled = true
+	w.cancel()
+	return nil
+}
+
+// Pause pauses a running Work. Jobs that are running will continue until they finish, but no
+// new Blocks or Jobs will be started until Resume() is called. A Work that a policy has paused
+// can also be paused, which keeps it paused after the policy allows it to continue.
+func (w *Work) Pause() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancelled {
+		return fmt.Errorf("Work has been cancelled")
+	}
+	if w.status.Status == pb.Status_StatusPaused && w.status.PausedBy != "" && !w.userPaused {
+		w.userPaused = true
+		return nil
+	}
+	if w.status.Status != pb.Status_StatusRunning {
+		return fmt.Errorf("Work can only be paused when running, was %v", w.status.Status)
+	}
+	w.pause = make(chan struct{})
+	w.userPaused = true
+	w.transition(Transition{Block: -1, Job: -1, From: w.status.Status, To: pb.Status_StatusPaused})
+	w.status.Status = pb.Status_StatusPaused
+	w.sendStatus(w.status)
+	return nil
+}
+
+// Resume resumes a Work that was paused with Pause().
+func (w *Work) Resume() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pause == nil {
+		return fmt.Errorf("Work is not paused")
+	}
+	close(w.pause)
+	w.pause = nil
+	w.userPaused = false
+	w.status.PausedBy = ""
+
+	if w.cancelled || w.status.Status != pb.Status_StatusPaused {
+		return nil
+	}
+	w.transition(Transition{Block: -1, Job: -1, From: w.status.Status, To: pb.Status_StatusRunning})
+	w.status.Status = pb.Status_StatusRunning
+	w.sendStatus(w.status)
+	return nil
+}
+
+// waitIfPaused blocks while the Work is paused. It returns an error if the Context is
+// cancelled before we are resumed.
+func (w *Work) waitIfPaused(ctx context.Context) error {
+	w.mu.Lock()
+	pause := w.pause
+	w.mu.Unlock()
+
+	if pause != nil {
+		select {
+		case <-ctx.Done():
+		case <-pause:
 		}
+	}
+	return ctx.Err()
+}
 
-		// Record our final state based on if any of our blocks failed.
-		completed := true
-		for _, block := range w.status.Blocks {
-			if block.Status == pb.Status_StatusFailed {
-				completed = false
-				w.setWorkStatus(pb.Status_StatusFailed, false)
+// waitForPolicies blocks while a policy configured for the WorkReq that implements policy.Pauser
+// says we cannot start a new Block. The Work is paused while we wait and resumed when the policies
+// allow it. If Resume() is called while we wait, the policies are checked again. It returns an
+// error if the Context is cancelled.
+func (w *Work) waitForPolicies(ctx context.Context) error {
+	for {
+		reason, until := pausedByPolicies(ctx, w.req)
+		if reason == "" {
+			if !w.policyResume() {
+				return ctx.Err()
 			}
+			// Pause() was called while a policy paused us, so we wait for Resume() and then
+			// check the policies again.
+			if err := w.waitIfPaused(ctx); err != nil {
+				return err
+			}
+			continue
 		}
-		if completed {
-			w.setWorkStatus(pb.Status_StatusCompleted, false)
+		pause := w.policyPause(reason)
+
+		wait := policyRecheck
+		if !until.IsZero() && time.Until(until) < wait {
+			wait = time.Until(until)
 		}
-	}()
+		if wait < time.Second {
+			wait = time.Second
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		case <-pause:
+		}
+		timer.Stop()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
 
-	return w.ch
+// policyPause pauses a running Work because of a policy and records "reason". It returns the
+// channel that is closed if Resume() is called. If the Work was not running, nothing is changed.
+func (w *Work) policyPause(reason string) chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch {
+	case w.cancelled:
+	case w.status.Status == pb.Status_StatusRunning:
+		w.pause = make(chan struct{})
+		w.transition(Transition{Block: -1, Job: -1, From: w.status.Status, To: pb.Status_StatusPaused, Err: reason})
+		w.status.Status = pb.Status_StatusPaused
+		w.status.PausedBy = reason
+		w.sendStatus(w.status)
+	case w.status.PausedBy != "" && w.status.PausedBy != reason:
+		w.status.PausedBy = reason
+		w.sendStatus(w.status)
+	}
+	return w.pause
+}
+
+// policyResume resumes a Work that was paused by a policy. A Work paused with Pause(), even
+// while a policy paused it, stays paused and "paused" is true.
+func (w *Work) policyResume() (paused bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pause == nil || w.status.PausedBy == "" {
+		return w.pause != nil && w.userPaused
+	}
+	w.status.PausedBy = ""
+	if w.userPaused {
+		w.sendStatus(w.status)
+		return true
+	}
+	close(w.pause)
+	w.pause = nil
+
+	if w.cancelled || w.status.Status != pb.Status_StatusPaused {
+		return false
+	}
+	w.transition(Transition{Block: -1, Job: -1, From: w.status.Status, To: pb.Status_StatusRunning})
+	w.status.Status = pb.Status_StatusRunning
+	w.sendStatus(w.status)
+	return false
 }
 
 func (w *Work) setWorkStatus(status pb.Status, esStopped bool) {
 	w.mu.Lock()
+	w.transition(Transition{Block: -1, Job: -1, From: w.status.Status, To: status, WasEsStopped: esStopped})
 	w.status.Status = status
 	w.status.WasEsStopped = esStopped
 	w.sendStatus(w.status)
@@ -122,6 +419,7 @@ func (w *Work) setWorkStatus(status pb.Status, esStopped bool) {
 
 func (w *Work) setBlockStatus(block *pb.BlockStatus, status pb.Status) {
 	w.mu.Lock()
+	w.transition(Transition{Block: w.blockIndex(block), Job: -1, From: block.Status, To: status})
 	block.Status = status
 	w.sendStatus(w.status)
 	w.mu.Unlock()
@@ -129,12 +427,45 @@ func (w *Work) setBlockStatus(block *pb.BlockStatus, status pb.Status) {
 
 func (w *Work) setJobStatus(job *pb.JobStatus, status pb.Status, err string) {
 	w.mu.Lock()
+	b, j := w.jobIndex(job)
+	w.transition(Transition{Block: b, Job: j, From: job.Status, To: status, Err: err})
 	job.Status = status
 	job.Error = err
 	w.sendStatus(w.status)
 	w.mu.Unlock()
 }
 
+// transition calls onTransition with "t" if the status changed. w.mu must be held.
+func (w *Work) transition(t Transition) {
+	if w.onTransition == nil || t.From == t.To {
+		return
+	}
+	w.onTransition(t)
+}
+
+// blockIndex returns the index of "block" in our status. w.mu must be held.
+func (w *Work) blockIndex(block *pb.BlockStatus) int {
+	for i, b := range w.status.Blocks {
+		if b == block {
+			return i
+		}
+	}
+	return -1
+}
+
+// jobIndex returns the index of the Block holding "job" and the index of "job" in
+// that Block. w.mu must be held.
+func (w *Work) jobIndex(job *pb.JobStatus) (block, index int) {
+	for i, b := range w.status.Blocks {
+		for x, j := range b.Jobs {
+			if j == job {
+				return i, x
+			}
+		}
+	}
+	return -1, -1
+}
+
 // sendStatus sends the status of the WorkReq on our output channel. If the channel
 // is currently blocked with another status update, it removes that update for the newer one.
 func (w *Work) sendStatus(status *pb.StatusResp) {
@@ -156,6 +487,7 @@ func (w *Work) sendStatus(status *pb.StatusResp) {
 
 func (w *Work) runJobs(ctx context.Context, block *pb.Block, blockStatus *pb.BlockStatus) error {
 	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	// Setup our rate limiter.
 	limit := block.RateLimit
@@ -164,6 +496,20 @@ func (w *Work) runJobs(ctx context.Context, block *pb.Block, blockStatus *pb.Blo
 	}
 	rateLimiter := make(chan struct{}, int(limit))
 
+	w.mu.Lock()
+	index := w.blockIndex(blockStatus)
+	w.mu.Unlock()
+	ctx, span := tracer.Start(
+		ctx,
+		"block",
+		trace.WithAttributes(
+			attribute.Int("block.index", index),
+			attribute.String("block.desc", block.Desc),
+			attribute.String("block.id", block.Id),
+		),
+	)
+	defer span.End()
+
 	w.setBlockStatus(blockStatus, pb.Status_StatusRunning)
 
 	// Execute our Jobs.
@@ -172,6 +518,14 @@ func (w *Work) runJobs(ctx context.Context, block *pb.Block, blockStatus *pb.Blo
 		i := i
 		job := job
 
+		if blockStatus.Jobs[i].Status == pb.Status_StatusCompleted {
+			continue
+		}
+
+		if err := w.waitIfPaused(ctx); err != nil {
+			break
+		}
+
 		select {
 		case rateLimiter <- struct{}{}:
 		case <-ctx.Done():
@@ -186,16 +540,36 @@ func (w *Work) runJobs(ctx context.Context, block *pb.Block, blockStatus *pb.Blo
 			defer func() { <-rateLimiter }()
 
 			js := blockStatus.Jobs[i]
+			ctx, span := startJobSpan(ctx, job)
+			defer w.endJobSpan(span, js)
+
 			j, err := jobs.GetJob(job.Name)
 			if err != nil {
 				cancel()
 				w.setJobStatus(js, pb.Status_StatusFailed, fmt.Sprintf("a Job(%s) passed validation but when ran could not be found, bug?", job.Name))
 				return
 			}
+			// Each Job instance must be validated to load its arguments before it is run.
+			// This can fail if something like our site data has changed since Submit().
+			if err := jobs.Validate(j, job); err != nil {
+				w.setJobStatus(js, pb.Status_StatusFailed, fmt.Sprintf("Job(%s) no longer validates: %s", job.Name, err))
+				return
+			}
+			// Emergency stops can be scoped to some Jobs, which fail without stopping the Work.
+			if info, stopped := esStopped(w.req.Name, job, j); stopped {
+				w.esStopJob(js, esStopErr{job: job.Name, info: info})
+				return
+			}
 
 			w.setJobStatus(js, pb.Status_StatusRunning, "")
-			err = j.Run(ctx, job)
+			err = w.runJob(jobs.WithOutput(ctx, &jobOutput{w: w, js: js}), j, job, js)
 			if err != nil {
+				// The stop may have happened while the Job was being retried.
+				var stopErr esStopErr
+				if errors.As(err, &stopErr) {
+					w.esStopJob(js, stopErr)
+					return
+				}
 				if jobs.IsFatal(err) {
 					cancel()
 				}
@@ -209,35 +583,263 @@ func (w *Work) runJobs(ctx context.Context, block *pb.Block, blockStatus *pb.Blo
 
 	wg.Wait()
 
-	// If any Job failed, the block failed.
+	w.mu.Lock()
+	cancelled := w.cancelled
+	failed := false
 	for _, js := range blockStatus.Jobs {
 		if js.Status == pb.Status_StatusFailed {
-			w.setBlockStatus(blockStatus, pb.Status_StatusFailed)
-			return ctx.Err()
+			failed = true
+			blockStatus.HasError = true
+			w.status.HadErrors = true
+			break
 		}
 	}
-	w.setBlockStatus(blockStatus, pb.Status_StatusCompleted)
+	w.mu.Unlock()
+
+	final := pb.Status_StatusCompleted
+	switch {
+	// Blocks that run after a cancellation don't use the cancelled Context.
+	case cancelled && ctx.Err() != nil:
+		final = pb.Status_StatusCancelled
+	// If any Job failed, the block failed.
+	case failed:
+		final = pb.Status_StatusFailed
+	}
+	w.setBlockStatus(blockStatus, final)
+
+	span.SetAttributes(attribute.String("block.status", final.String()))
+	if final != pb.Status_StatusCompleted {
+		span.SetStatus(codes.Error, fmt.Sprintf("Block finished with %v", final))
+	}
 	return ctx.Err()
 }
 
+// startJobSpan starts the span for running "job".
+func startJobSpan(ctx context.Context, job *pb.Job) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{attribute.String("job.name", job.Name)}
+	for k, v := range job.Args {
+		attrs = append(attrs, attribute.String("job.args."+k, v))
+	}
+	return tracer.Start(ctx, "job "+job.Name, trace.WithAttributes(attrs...))
+}
+
+// endJobSpan records the final status of a Job on its span and ends it.
+func (w *Work) endJobSpan(span trace.Span, js *pb.JobStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	span.SetAttributes(
+		attribute.String("job.status", js.Status.String()),
+		attribute.Int("job.attempts", int(js.Attempts)),
+	)
+	if js.Error != "" {
+		span.SetStatus(codes.Error, js.Error)
+	}
+	span.End()
+}
+
+// esStopped returns the scoped emergency stop entry that stops "job" in the workflow "name", if
+// any. "j" must be the validated instance of "job".
+func esStopped(name string, job *pb.Job, j jobs.Job) (es.Info, bool) {
+	var t jobs.Targets
+	if targeter, ok := j.(jobs.Targeter); ok {
+		t = targeter.Targets()
+	}
+	return es.Data.Stopped(name, job.Name, t.Sites, t.Machines)
+}
+
+// esStopErr is the error a Job fails with when a scoped emergency stop applies to it.
+type esStopErr struct {
+	job  string
+	info es.Info
+}
+
+// Error implements error.Error().
+func (e esStopErr) Error() string {
+	return fmt.Sprintf("Job(%s) was stopped by an emergency stop for %s", e.job, e.info.Scope())
+}
+
+// esStopJob fails the Job with "js" because of a scoped emergency stop. Unlike other fatal
+// errors, the rest of the Block continues.
+func (w *Work) esStopJob(js *pb.JobStatus, err esStopErr) {
+	w.mu.Lock()
+	w.status.WasEsStopped = true
+	w.mu.Unlock()
+	w.setJobStatus(js, pb.Status_StatusFailed, err.Error())
+}
+
+// runJob runs a Job until it succeeds or its RetryPolicy says it should not be retried.
+// Each attempt is recorded in the JobStatus. A scoped emergency stop is checked before each
+// attempt, as it may happen while the Job is waiting to be retried or for its leases.
+func (w *Work) runJob(ctx context.Context, j jobs.Job, job *pb.Job, js *pb.JobStatus) error {
+	retry := newRetrier(job.RetryPolicy)
+
+	w.mu.Lock()
+	js.Attempts = 0
+	js.AttemptErrors = nil
+	js.Output = ""
+	w.mu.Unlock()
+
+	span := trace.SpanFromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		w.setJobAttempt(js, attempt)
+
+		release, err := w.acquireLeases(ctx, j, job, js)
+		if err == nil {
+			if info, stopped := esStopped(w.req.Name, job, j); stopped {
+				release()
+				err = jobs.Fatalf("%w", esStopErr{job: job.Name, info: info})
+			}
+		}
+		if err == nil {
+			var done <-chan struct{}
+			done, err = runAttempt(ctx, j, job)
+			// A Job that timed out may still be changing the resources it leased, so it must
+			// return before they are released or it is attempted again.
+			<-done
+			release()
+		}
+		if err == nil {
+			return nil
+		}
+		err = retry.classify(err)
+		w.addJobAttemptErr(js, err)
+		span.RecordError(err, trace.WithAttributes(attribute.Int("job.attempt", attempt)))
+
+		if ctx.Err() != nil || !retry.retry(attempt, err) {
+			return err
+		}
+		if retry.wait(ctx) != nil {
+			return err
+		}
+	}
+}
+
+// acquireLeases acquires leases on the resources "j" changes if it implements jobs.Locker. If
+// another workflow holds one of them, we wait for it to be released or fail, depending on the
+// Job's on_locked setting. The returned function releases the leases.
+func (w *Work) acquireLeases(ctx context.Context, j jobs.Job, job *pb.Job, js *pb.JobStatus) (release func(), err error) {
+	locker, ok := j.(jobs.Locker)
+	if !ok || len(locker.Resources()) == 0 {
+		return func() {}, nil
+	}
+	resources := locker.Resources()
+
+	w.mu.Lock()
+	holder := w.id
+	w.mu.Unlock()
+	if holder == "" {
+		holder = fmt.Sprintf("%p", w)
+	}
+
+	if job.OnLocked == pb.OnLocked_OnLockedFail {
+		release, err := lease.Data.TryAcquire(holder, resources)
+		if err != nil {
+			return nil, fmt.Errorf("%s (retryable)", err)
+		}
+		return release, nil
+	}
+
+	waited := false
+	release, err = lease.Data.Acquire(ctx, holder, resources, func(held *lease.HeldError) {
+		waited = true
+		trace.SpanFromContext(ctx).AddEvent("waiting on lease", trace.WithAttributes(
+			attribute.String("lease.resource", held.Lease.Resource),
+			attribute.String("lease.holder", held.Lease.Holder),
+		))
+		w.setJobWaitingOn(js, held.Error())
+	})
+	if waited {
+		w.setJobWaitingOn(js, "")
+	}
+	return release, err
+}
+
+func (w *Work) setJobWaitingOn(job *pb.JobStatus, waitingOn string) {
+	w.mu.Lock()
+	job.WaitingOn = waitingOn
+	w.sendStatus(w.status)
+	w.mu.Unlock()
+}
+
+// maxOutput is the most output we keep for a Job. Older output is discarded.
+const maxOutput = 64 * 1024
+
+// jobOutput is an io.Writer that records the output of a Job in its JobStatus.
+type jobOutput struct {
+	w  *Work
+	js *pb.JobStatus
+}
+
+// Write implements io.Writer.Write().
+func (o *jobOutput) Write(p []byte) (int, error) {
+	o.w.mu.Lock()
+	defer o.w.mu.Unlock()
+
+	// Protocol buffer strings must be valid UTF-8, which output doesn't have to be.
+	out := o.js.Output + strings.ToValidUTF8(string(p), "\uFFFD")
+	if len(out) > maxOutput {
+		out = out[len(out)-maxOutput:]
+		for len(out) > 0 && !utf8.RuneStart(out[0]) {
+			out = out[1:]
+		}
+	}
+	o.js.Output = out
+	o.w.sendStatus(o.w.status)
+	return len(p), nil
+}
+
+func (w *Work) setJobAttempt(job *pb.JobStatus, attempt int) {
+	w.mu.Lock()
+	job.Attempts = int32(attempt)
+	w.sendStatus(w.status)
+	w.mu.Unlock()
+}
+
+func (w *Work) addJobAttemptErr(job *pb.JobStatus, err error) {
+	w.mu.Lock()
+	job.AttemptErrors = append(job.AttemptErrors, err.Error())
+	w.sendStatus(w.status)
+	w.mu.Unlock()
+}
+
 // Validate validates that a WorkReq is valid. This will check that basic values are set correctly
 // and run all policies for this Workflow.
 func Validate(ctx context.Context, req *pb.WorkReq) error {
+	if err := validateDeps(req); err != nil {
+		return err
+	}
+
 	for blockNum, b := range req.Blocks {
 		if len(b.Jobs) == 0 {
 			return fmt.Errorf("Block(%d) had 0 jobs", blockNum)
 		}
+		if _, ok := pb.RunOn_name[int32(b.RunOn)]; !ok {
+			return fmt.Errorf("Block(%d) had an invalid RunOn(%d)", blockNum, b.RunOn)
+		}
 		for jobNum, j := range b.Jobs {
 			job, err := jobs.GetJob(j.Name)
 			if err != nil {
 				return fmt.Errorf("Block(%d) Job(%d) had a invalid Type(%s)", blockNum, jobNum, j.Name)
 			}
-			if err := job.Validate(j); err != nil {
+			if _, ok := pb.OnLocked_name[int32(j.OnLocked)]; !ok {
+				return fmt.Errorf("Block(%d) Job(%d)(%s) had an invalid OnLocked(%d)", blockNum, jobNum, j.Name, j.OnLocked)
+			}
+			if err := jobs.Validate(job, j); err != nil {
+				return fmt.Errorf("Block(%d) Job(%d)(%s) did not validate: %s)", blockNum, jobNum, j.Name, err)
+			}
+			if err := validateRetry(j); err != nil {
 				return fmt.Errorf("Block(%d) Job(%d)(%s) did not validate: %s)", blockNum, jobNum, j.Name, err)
 			}
 		}
 	}
 
+	return RunPolicies(ctx, req)
+}
+
+// RunPolicies runs all policies configured for the WorkReq's name. This is done by Validate(),
+// but can be used to check policies again before a WorkReq is executed.
+func RunPolicies(ctx context.Context, req *pb.WorkReq) error {
 	conf, err := config.Policies.Read()
 	if err != nil {
 		log.Println("policy config could not be read: ", err)
@@ -248,16 +850,40 @@ func Validate(ctx context.Context, req *pb.WorkReq) error {
 		return fmt.Errorf("Workflow does not have an associated policy in the policy configuration file")
 	}
 
-	args := make([]policy.PolicyArgs, 0, len(workConf.Policies))
-	for _, p := range workConf.Policies {
-		args = append(args, policy.PolicyArgs{Name: p.Name, Settings: p.SettingsTyped})
-	}
-
 	policyContext, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if err := policy.Run(policyContext, req, args...); err != nil {
+	if err := policy.Run(policyContext, req, policyArgs(workConf)...); err != nil {
 		return err
 	}
 	return nil
 }
+
+// pausedByPolicies returns why "req" cannot start a new Block because of a policy configured for
+// it that implements policy.Pauser, or "" if it can. "until" is when to check again, if known.
+// If the policy config cannot be read, we don't know what policies apply, so "req" is paused
+// until it can be.
+func pausedByPolicies(ctx context.Context, req *pb.WorkReq) (reason string, until time.Time) {
+	conf, err := config.Policies.Read()
+	if err != nil {
+		log.Printf("Workflow(%s) is paused, policy config could not be read: %s", req.Name, err)
+		return fmt.Sprintf("policy config could not be read: %s", err), time.Time{}
+	}
+	workConf, ok := conf.Workflows[req.Name]
+	if !ok {
+		return "", time.Time{}
+	}
+
+	policyContext, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	return policy.Paused(policyContext, req, policyArgs(workConf)...)
+}
+
+func policyArgs(workConf config.Workflow) []policy.PolicyArgs {
+	args := make([]policy.PolicyArgs, 0, len(workConf.Policies))
+	for _, p := range workConf.Policies {
+		args = append(args, policy.PolicyArgs{Name: p.Name, Settings: p.SettingsTyped})
+	}
+	return args
+}
@@ -0,0 +1,1041 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/lease"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/config"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/dir"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token/buckets"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/validatedecom"
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/tokenbucket"
+)
+
+// recordJob is a Job that stores the "id" arg in Validate() and records it in Run().
+// If Job instances were shared, concurrent Jobs would record each other's ids.
+type recordJob struct {
+	id string
+}
+
+var (
+	recordMu sync.Mutex
+	recorded = map[string]int{}
+)
+
+// outputJob is a Job that writes the "output" arg to its output "times" times.
+type outputJob struct {
+	output string
+	times  int
+}
+
+// lockJob is a Job that leases its "resource" arg.
+type lockJob struct {
+	resource string
+}
+
+func (l *lockJob) Validate(job *pb.Job) error {
+	l.resource = job.Args["resource"]
+	return nil
+}
+
+func (l *lockJob) Resources() []string {
+	return []string{l.resource}
+}
+
+func (l *lockJob) Run(ctx context.Context) error {
+	return nil
+}
+
+// slowLockJob is a Job that leases its "resource" arg and then sleeps for its "sleep" arg,
+// ignoring its Context. It records how many are running at the same time.
+type slowLockJob struct {
+	resource string
+	sleep    time.Duration
+}
+
+var (
+	slowMu         sync.Mutex
+	slowRunning    int
+	slowMaxRunning int
+	slowStarted    = make(chan struct{}, 10)
+)
+
+func (s *slowLockJob) Validate(job *pb.Job) error {
+	d, err := time.ParseDuration(job.Args["sleep"])
+	if err != nil {
+		return fmt.Errorf("arg(sleep) must be a duration: %s", err)
+	}
+	s.resource, s.sleep = job.Args["resource"], d
+	return nil
+}
+
+func (s *slowLockJob) Resources() []string {
+	return []string{s.resource}
+}
+
+func (s *slowLockJob) Run(ctx context.Context) error {
+	slowMu.Lock()
+	slowRunning++
+	if slowRunning > slowMaxRunning {
+		slowMaxRunning = slowRunning
+	}
+	slowMu.Unlock()
+	slowStarted <- struct{}{}
+
+	time.Sleep(s.sleep)
+
+	slowMu.Lock()
+	slowRunning--
+	slowMu.Unlock()
+	return nil
+}
+
+// gateJob is a Job that waits until the gate named by its "gate" arg is opened or its
+// Context is cancelled.
+type gateJob struct {
+	gate string
+}
+
+// gate is used by a gateJob. "started" is closed when the Job starts and closing "open" lets
+// it finish.
+type gate struct {
+	started chan struct{}
+	open    chan struct{}
+}
+
+var (
+	gateMu sync.Mutex
+	gates  = map[string]*gate{}
+)
+
+// newGate creates the gate called "name".
+func newGate(name string) *gate {
+	gateMu.Lock()
+	defer gateMu.Unlock()
+	g := &gate{started: make(chan struct{}), open: make(chan struct{})}
+	gates[name] = g
+	return g
+}
+
+func (g *gateJob) Validate(job *pb.Job) error {
+	g.gate = job.Args["gate"]
+	return nil
+}
+
+func (g *gateJob) Run(ctx context.Context) error {
+	gateMu.Lock()
+	gt := gates[g.gate]
+	gateMu.Unlock()
+
+	close(gt.started)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-gt.open:
+	}
+	return nil
+}
+
+// esStopJob is a Job that acts on the site in its "site" arg. Its first attempt adds a scoped
+// emergency stop for that site to es.json and fails once the stop is loaded.
+type esStopJob struct {
+	site string
+}
+
+var esStopRuns int32
+
+func (e *esStopJob) Validate(job *pb.Job) error {
+	e.site = job.Args["site"]
+	return nil
+}
+
+func (e *esStopJob) Targets() jobs.Targets {
+	return jobs.Targets{Sites: []string{e.site}}
+}
+
+func (e *esStopJob) Run(ctx context.Context) error {
+	if atomic.AddInt32(&esStopRuns, 1) > 1 {
+		return nil
+	}
+	stop := fmt.Sprintf(`{"Name": "test", "Status": "stop", "Site": %q}`, e.site)
+	if err := os.WriteFile(esPath, []byte(testES+stop), 0600); err != nil {
+		return err
+	}
+	waitES(func() bool {
+		_, stopped := es.Data.Stopped("test", "testEsStop", []string{e.site}, nil)
+		return stopped
+	})
+	return fmt.Errorf("attempt failed")
+}
+
+// waitES waits up to 15 seconds for es.json to be reloaded so that "loaded" returns true.
+func waitES(loaded func() bool) {
+	for start := time.Now(); time.Since(start) < 15*time.Second; time.Sleep(10 * time.Millisecond) {
+		if loaded() {
+			return
+		}
+	}
+}
+
+// pausePolicy is a policy.Pauser that pauses WorkReqs while pauseReason is set, asking to
+// be checked again soon.
+type pausePolicy struct{}
+
+type pauseSettings struct{}
+
+func (pauseSettings) Validate() error { return nil }
+
+var (
+	pauseMu     sync.Mutex
+	pauseReason string
+)
+
+func setPauseReason(reason string) {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	pauseReason = reason
+}
+
+func (pausePolicy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
+	return nil
+}
+
+func (pausePolicy) Pause(ctx context.Context, req *pb.WorkReq, settings policy.Settings) (string, time.Time) {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	return pauseReason, time.Now().Add(100 * time.Millisecond)
+}
+
+func init() {
+	jobs.Register("testRecord", func() jobs.Job { return &recordJob{} })
+	jobs.Register("testOutput", func() jobs.Job { return &outputJob{} })
+	jobs.Register("testLock", func() jobs.Job { return &lockJob{} })
+	jobs.Register("testSlowLock", func() jobs.Job { return &slowLockJob{} })
+	jobs.Register("testGate", func() jobs.Job { return &gateJob{} })
+	jobs.Register("testEsStop", func() jobs.Job { return &esStopJob{} })
+	policy.Register("testPause", pausePolicy{}, pauseSettings{})
+}
+
+func (o *outputJob) Validate(job *pb.Job) error {
+	times, err := strconv.Atoi(job.Args["times"])
+	if err != nil {
+		return fmt.Errorf("arg(times) must be an integer: %s", err)
+	}
+	o.output, o.times = job.Args["output"], times
+	return nil
+}
+
+func (o *outputJob) Run(ctx context.Context) error {
+	out := jobs.Output(ctx)
+	for i := 0; i < o.times; i++ {
+		fmt.Fprint(out, o.output)
+	}
+	return nil
+}
+
+func (r *recordJob) Validate(job *pb.Job) error {
+	id, ok := job.Args["id"]
+	if !ok {
+		return fmt.Errorf("missing required arg(id)")
+	}
+	r.id = id
+	return nil
+}
+
+func (r *recordJob) Run(ctx context.Context) error {
+	id := r.id
+	// Give other Jobs a chance to run between reading and recording our id.
+	time.Sleep(time.Millisecond)
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	recorded[id]++
+	return nil
+}
+
+// testES is the es.json used by tests. Jobs in "test" workflows that act on site "stopped"
+// are emergency stopped.
+const testES = `{"Name": "test", "Status": "go"}
+{"Name": "test", "Status": "stop", "Site": "stopped"}
+{"Name": "testPaused", "Status": "go"}
+`
+
+// testPolicies is the policies.json used by tests. "testPaused" workflows are paused by the
+// testPause policy while pauseReason is set.
+const testPolicies = `{"Name": "testPaused", "Policies": [{"Name": "testPause", "Settings": {}}]}`
+
+// testBuckets is the buckets.json used by tests. Each bucket has a token for 10 Jobs.
+const testBuckets = `{"Name": "concurrent0", "Size": 10, "Incr": 1, "Interval": "1h"}
+{"Name": "concurrent1", "Size": 10, "Incr": 1, "Interval": "1h"}
+{"Name": "concurrent2", "Size": 10, "Incr": 1, "Interval": "1h"}
+`
+
+// esPath and policiesPath are the paths of the es.json and policies.json used by tests.
+var esPath, policiesPath string
+
+// spans records the spans from all tests.
+var spans = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+
+	tmp, err := os.MkdirTemp("", "executor")
+	if err != nil {
+		log.Fatal(err)
+	}
+	esPath = filepath.Join(tmp, "es.json")
+	if err := os.WriteFile(esPath, []byte(testES), 0600); err != nil {
+		log.Fatal(err)
+	}
+	es.Init(esPath)
+	policiesPath = filepath.Join(tmp, "policies.json")
+	if err := os.WriteFile(policiesPath, []byte(testPolicies), 0600); err != nil {
+		log.Fatal(err)
+	}
+	config.Init(policiesPath)
+	// The built-in Jobs validate their args against our site data and token buckets.
+	sites.Init(filepath.Join("..", "..", "..", "data"))
+	storeDir := filepath.Join(tmp, "storage")
+	if err := os.Mkdir(storeDir, 0700); err != nil {
+		log.Fatal(err)
+	}
+	store, err := dir.New(storeDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	p := filepath.Join(tmp, "buckets.json")
+	if err := os.WriteFile(p, []byte(testBuckets), 0600); err != nil {
+		log.Fatal(err)
+	}
+	buckets.Init(p, store)
+
+	code := m.Run()
+	es.Data.Close()
+	config.Policies.Close()
+	buckets.Data.Close()
+	store.Close()
+	os.RemoveAll(tmp)
+	os.Exit(code)
+}
+
+func TestRunJobsConcurrent(t *testing.T) {
+	const numJobs = 100
+
+	block := &pb.Block{Desc: "concurrent", RateLimit: numJobs}
+	blockStatus := &pb.BlockStatus{Desc: "concurrent", Status: pb.Status_StatusNotStarted}
+	for i := 0; i < numJobs; i++ {
+		job := &pb.Job{Name: "testRecord", Args: map[string]string{"id": strconv.Itoa(i)}}
+		block.Jobs = append(block.Jobs, job)
+		blockStatus.Jobs = append(
+			blockStatus.Jobs,
+			&pb.JobStatus{Name: job.Name, Args: job.Args, Status: pb.Status_StatusNotStarted},
+		)
+	}
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{block}}
+	status := &pb.StatusResp{Name: "test", Blocks: []*pb.BlockStatus{blockStatus}}
+
+	w := New(req, status)
+
+	// Drain status updates the same way the service does.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range w.ch {
+		}
+	}()
+
+	if err := w.runJobs(context.Background(), block, blockStatus); err != nil {
+		t.Fatalf("TestRunJobsConcurrent: runJobs() had error: %s", err)
+	}
+	close(w.ch)
+	<-done
+
+	if blockStatus.Status != pb.Status_StatusCompleted {
+		t.Errorf("TestRunJobsConcurrent: got block status %v, want %v", blockStatus.Status, pb.Status_StatusCompleted)
+	}
+	for i, js := range blockStatus.Jobs {
+		if js.Status != pb.Status_StatusCompleted {
+			t.Errorf("TestRunJobsConcurrent: job(%d): got status %v, want %v: %s", i, js.Status, pb.Status_StatusCompleted, js.Error)
+		}
+	}
+
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	for i := 0; i < numJobs; i++ {
+		if got := recorded[strconv.Itoa(i)]; got != 1 {
+			t.Errorf("TestRunJobsConcurrent: job with id(%d) ran %d times, want 1", i, got)
+		}
+	}
+}
+
+func TestBuiltinJobsConcurrent(t *testing.T) {
+	const perType = 30
+
+	// The Jobs for each type have different args, so Jobs sharing an instance would race on
+	// them and act on the wrong site, machine or bucket.
+	decom := []string{"aap", "adg", "adv"}
+	machines := sites.Data.Sites["aap"].Machines
+	block := &pb.Block{Desc: "builtin", RateLimit: 4 * perType}
+	for i := 0; i < perType; i++ {
+		block.Jobs = append(
+			block.Jobs,
+			&pb.Job{Name: "sleep", Args: map[string]string{"seconds": "1"}},
+			&pb.Job{Name: "validateDecom", Args: map[string]string{"site": decom[i%len(decom)], "type": "satellite"}},
+			&pb.Job{Name: "tokenBucket", Args: map[string]string{"bucket": fmt.Sprintf("concurrent%d", i%3), "fatal": "true"}},
+			// diskErase takes 30 seconds, so it is stopped by a timeout once it has leased its machine.
+			&pb.Job{
+				Name:     "diskErase",
+				Args:     map[string]string{"site": "aap", "machine": machines[i].Name},
+				Timeout:  durationpb.New(100 * time.Millisecond),
+				OnLocked: pb.OnLocked_OnLockedFail,
+			},
+		)
+	}
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{block}}
+	status := statusFor(req)
+	blockStatus := status.Blocks[0]
+
+	w := New(req, status)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range w.ch {
+		}
+	}()
+	start := time.Now()
+	w.runJobs(context.Background(), block, blockStatus)
+	close(w.ch)
+	<-done
+
+	if since := time.Since(start); since > 10*time.Second {
+		t.Errorf("TestBuiltinJobsConcurrent: Jobs took %v, want them to run concurrently", since)
+	}
+	for i, js := range blockStatus.Jobs {
+		switch js.Name {
+		case "diskErase":
+			if js.Status != pb.Status_StatusFailed || js.Error != "Job timed out after 100ms" {
+				t.Errorf("TestBuiltinJobsConcurrent: Job(%d)(%s): got status %v, error %q, want failed with a timeout", i, js.Name, js.Status, js.Error)
+			}
+		default:
+			if js.Status != pb.Status_StatusCompleted {
+				t.Errorf("TestBuiltinJobsConcurrent: Job(%d)(%s): got status %v, want %v: %s", i, js.Name, js.Status, pb.Status_StatusCompleted, js.Error)
+			}
+		}
+	}
+	// Each bucket had exactly enough tokens for the Jobs that used it.
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("concurrent%d", i)
+		if info, _ := buckets.Data.Info(name); info.Available != 0 {
+			t.Errorf("TestBuiltinJobsConcurrent: bucket(%s): got %d tokens left, want 0", name, info.Available)
+		}
+	}
+}
+
+func TestSpans(t *testing.T) {
+	b := block("a", "testOrder")
+	b.Desc = "spans"
+	b.Jobs = append(b.Jobs, &pb.Job{Name: "testFatal", Args: map[string]string{"id": "fatal"}})
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{b}}
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "root")
+	w := New(req, statusFor(req))
+	go func() {
+		for range w.ch {
+		}
+	}()
+	w.runBlocks(ctx, ctx)
+	root.End()
+	close(w.ch)
+
+	// Find the spans for this test, which are in the root's trace.
+	got := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans.Ended() {
+		if s.SpanContext().TraceID() == root.SpanContext().TraceID() {
+			got[s.Name()] = s
+		}
+	}
+
+	block, ok := got["block"]
+	if !ok {
+		t.Fatalf("TestSpans: no span for the Block")
+	}
+	if block.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Errorf("TestSpans: Block span is not a child of the root span")
+	}
+	if block.Status().Code != codes.Error {
+		t.Errorf("TestSpans: Block span: got status %v, want %v", block.Status().Code, codes.Error)
+	}
+
+	tests := []struct {
+		name     string
+		wantCode codes.Code
+		wantAttr attribute.KeyValue
+	}{
+		{name: "job testOrder", wantCode: codes.Unset, wantAttr: attribute.String("job.args.id", "a")},
+		{name: "job testFatal", wantCode: codes.Error, wantAttr: attribute.Int("job.attempts", 1)},
+	}
+	for _, test := range tests {
+		s, ok := got[test.name]
+		if !ok {
+			t.Errorf("TestSpans(%s): no span", test.name)
+			continue
+		}
+		if s.Parent().SpanID() != block.SpanContext().SpanID() {
+			t.Errorf("TestSpans(%s): span is not a child of the Block span", test.name)
+		}
+		if s.Status().Code != test.wantCode {
+			t.Errorf("TestSpans(%s): got status %v, want %v", test.name, s.Status().Code, test.wantCode)
+		}
+		found := false
+		for _, attr := range s.Attributes() {
+			if attr == test.wantAttr {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("TestSpans(%s): did not have attribute %v=%v", test.name, test.wantAttr.Key, test.wantAttr.Value.Emit())
+		}
+	}
+}
+
+func TestJobOutput(t *testing.T) {
+	tests := []struct {
+		desc   string
+		output string
+		times  int
+		want   string
+	}{
+		{desc: "Small output", output: "line\n", times: 2, want: "line\nline\n"},
+		{
+			desc:   "Output over the limit keeps the end",
+			output: "0123456789abcdef",
+			times:  maxOutput/16 + 1,
+			want:   strings.Repeat("0123456789abcdef", maxOutput/16),
+		},
+		{
+			// 3 byte runes don't divide evenly into maxOutput, so the first rune kept is cut.
+			desc:   "Output over the limit is valid UTF-8",
+			output: "世",
+			times:  maxOutput/3 + 1,
+			want:   strings.Repeat("世", maxOutput/3),
+		},
+		{desc: "Invalid UTF-8 is replaced", output: "a\xffb", times: 1, want: "a\uFFFDb"},
+	}
+
+	for _, test := range tests {
+		b := &pb.Block{
+			Jobs: []*pb.Job{
+				{Name: "testOutput", Args: map[string]string{"output": test.output, "times": strconv.Itoa(test.times)}},
+			},
+		}
+		req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{b}}
+		status := statusFor(req)
+		w := New(req, status)
+		go func() {
+			for range w.ch {
+			}
+		}()
+
+		if err := w.runJobs(context.Background(), b, status.Blocks[0]); err != nil {
+			t.Fatalf("TestJobOutput(%s): runJobs() had error: %s", test.desc, err)
+		}
+		close(w.ch)
+
+		if got := status.Blocks[0].Jobs[0].Output; got != test.want {
+			t.Errorf("TestJobOutput(%s): got output of %d bytes starting %q, want %d bytes starting %q",
+				test.desc, len(got), head(got), len(test.want), head(test.want))
+		}
+	}
+}
+
+// head returns the start of "s" for error messages.
+func head(s string) string {
+	if len(s) > 20 {
+		return s[:20]
+	}
+	return s
+}
+
+func TestPolicyPause(t *testing.T) {
+	setPauseReason("window closed")
+	defer setPauseReason("")
+
+	req := &pb.WorkReq{Name: "testPaused", Blocks: []*pb.Block{block("a", "testOrder"), block("b", "testOrder")}}
+	status := statusFor(req)
+	status.Status = pb.Status_StatusRunning
+
+	w := New(req, status)
+	var got []Transition
+	w.OnTransition(func(t Transition) {
+		if t.Block == -1 {
+			got = append(got, t)
+		}
+	})
+	go func() {
+		for range w.ch {
+		}
+	}()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.runBlocks(context.Background(), context.Background())
+	}()
+
+	// waitForPauses waits until the Work has been paused "n" times.
+	waitForPauses := func(n int) {
+		t.Helper()
+		for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+			w.mu.Lock()
+			pauses := 0
+			for _, tr := range got {
+				if tr.To == pb.Status_StatusPaused {
+					pauses++
+				}
+			}
+			w.mu.Unlock()
+			if pauses == n {
+				return
+			}
+		}
+		t.Fatalf("TestPolicyPause: Work was not paused %d times", n)
+	}
+
+	waitForPauses(1)
+	w.mu.Lock()
+	if status.PausedBy == "" || status.Blocks[0].Status != pb.Status_StatusNotStarted {
+		t.Errorf("TestPolicyPause: got PausedBy %q, Block(0) %v, want PausedBy set and Block(0) not started", status.PausedBy, status.Blocks[0].Status)
+	}
+	w.mu.Unlock()
+
+	// Resuming while the policy still pauses us must pause us again.
+	if err := w.Resume(); err != nil {
+		t.Fatalf("TestPolicyPause: Resume() had error: %s", err)
+	}
+	waitForPauses(2)
+
+	// Once the policy allows it, we must resume by ourselves.
+	setPauseReason("")
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestPolicyPause: Work did not resume after the policy allowed it")
+	}
+
+	for i, bs := range status.Blocks {
+		if bs.Status != pb.Status_StatusCompleted {
+			t.Errorf("TestPolicyPause: Block(%d): got status %v, want %v", i, bs.Status, pb.Status_StatusCompleted)
+		}
+	}
+	reason := "policy(testPause): window closed"
+	want := []Transition{
+		{Block: -1, Job: -1, From: pb.Status_StatusRunning, To: pb.Status_StatusPaused, Err: reason},
+		{Block: -1, Job: -1, From: pb.Status_StatusPaused, To: pb.Status_StatusRunning},
+		{Block: -1, Job: -1, From: pb.Status_StatusRunning, To: pb.Status_StatusPaused, Err: reason},
+		{Block: -1, Job: -1, From: pb.Status_StatusPaused, To: pb.Status_StatusRunning},
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestPolicyPause: -want/+got:\n%s", diff)
+	}
+	if status.PausedBy != "" {
+		t.Errorf("TestPolicyPause: got PausedBy %q after resuming, want \"\"", status.PausedBy)
+	}
+}
+
+func TestLeases(t *testing.T) {
+	release, err := lease.Data.TryAcquire("other", []string{"site"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// runLock runs a Work with a Job that leases "site/machine" and returns its JobStatus.
+	// "running" is called with the JobStatus while the Work runs until it returns true.
+	runLock := func(onLocked pb.OnLocked, running func(js *pb.JobStatus) bool) *pb.JobStatus {
+		job := &pb.Job{Name: "testLock", Args: map[string]string{"resource": "site/machine"}, OnLocked: onLocked}
+		req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{{Jobs: []*pb.Job{job}}}}
+		status := statusFor(req)
+
+		w := New(req, status)
+		w.SetID("mine")
+		done := make(chan struct{})
+		go func() {
+			for range w.ch {
+			}
+		}()
+		go func() {
+			defer close(done)
+			w.runBlocks(context.Background(), context.Background())
+		}()
+
+		for start := time.Now(); running != nil; time.Sleep(10 * time.Millisecond) {
+			w.mu.Lock()
+			ok := running(status.Blocks[0].Jobs[0])
+			w.mu.Unlock()
+			if ok {
+				break
+			}
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("TestLeases: timed out waiting on the Job")
+			}
+		}
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("TestLeases: Work did not finish")
+		}
+		return status.Blocks[0].Jobs[0]
+	}
+
+	js := runLock(pb.OnLocked_OnLockedFail, nil)
+	if js.Status != pb.Status_StatusFailed || !strings.Contains(js.Error, "(retryable)") || !strings.Contains(js.Error, "workflow(other)") {
+		t.Errorf("TestLeases(OnLockedFail): got status %v, error %q, want failed with a retryable error naming workflow(other)", js.Status, js.Error)
+	}
+
+	js = runLock(pb.OnLocked_OnLockedWait, func(js *pb.JobStatus) bool {
+		if strings.Contains(js.WaitingOn, "workflow(other)") {
+			release()
+			return true
+		}
+		return false
+	})
+	if js.Status != pb.Status_StatusCompleted || js.WaitingOn != "" {
+		t.Errorf("TestLeases(OnLockedWait): got status %v, waiting on %q, want %v and not waiting", js.Status, js.WaitingOn, pb.Status_StatusCompleted)
+	}
+
+	// Our leases must be released when the Job finishes.
+	r, err := lease.Data.TryAcquire("other", []string{"site/machine"})
+	if err != nil {
+		t.Errorf("TestLeases: lease was not released: %s", err)
+	} else {
+		r()
+	}
+}
+
+func TestTimeoutKeepsLeases(t *testing.T) {
+	job := &pb.Job{
+		Name:    "testSlowLock",
+		Args:    map[string]string{"resource": "slow/machine", "sleep": "300ms"},
+		Timeout: durationpb.New(50 * time.Millisecond),
+		RetryPolicy: &pb.RetryPolicy{
+			MaxAttempts:    2,
+			InitialBackoff: durationpb.New(time.Millisecond),
+		},
+	}
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{{Jobs: []*pb.Job{job}}}}
+	status := statusFor(req)
+
+	w := New(req, status)
+	w.SetID("mine")
+	go func() {
+		for range w.ch {
+		}
+	}()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.runBlocks(context.Background(), context.Background())
+	}()
+
+	// After the first attempt has timed out but before its Run() returns, the lease must
+	// still be held.
+	<-slowStarted
+	time.Sleep(150 * time.Millisecond)
+	if r, err := lease.Data.TryAcquire("other", []string{"slow/machine"}); err == nil {
+		r()
+		t.Errorf("TestTimeoutKeepsLeases: lease was released while the timed out Job was still running")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestTimeoutKeepsLeases: Work did not finish")
+	}
+	<-slowStarted
+
+	slowMu.Lock()
+	maxRunning := slowMaxRunning
+	slowMu.Unlock()
+	if maxRunning != 1 {
+		t.Errorf("TestTimeoutKeepsLeases: got %d attempts running at the same time, want 1", maxRunning)
+	}
+
+	js := status.Blocks[0].Jobs[0]
+	if js.Status != pb.Status_StatusFailed || js.Attempts != 2 || len(js.AttemptErrors) != 2 {
+		t.Errorf("TestTimeoutKeepsLeases: got status %v, attempts %d, attempt errors %v, want failed after 2 attempts", js.Status, js.Attempts, js.AttemptErrors)
+	}
+	for _, e := range js.AttemptErrors {
+		if !strings.Contains(e, "timed out") {
+			t.Errorf("TestTimeoutKeepsLeases: got attempt error %q, want a timeout", e)
+		}
+	}
+
+	r, err := lease.Data.TryAcquire("other", []string{"slow/machine"})
+	if err != nil {
+		t.Errorf("TestTimeoutKeepsLeases: lease was not released: %s", err)
+	} else {
+		r()
+	}
+}
+
+func TestEsStopDuringRetry(t *testing.T) {
+	atomic.StoreInt32(&esStopRuns, 0)
+	defer func() {
+		if err := os.WriteFile(esPath, []byte(testES), 0600); err != nil {
+			t.Fatal(err)
+		}
+		waitES(func() bool {
+			_, stopped := es.Data.Stopped("test", "testEsStop", []string{"later"}, nil)
+			return !stopped
+		})
+	}()
+
+	job := &pb.Job{
+		Name:        "testEsStop",
+		Args:        map[string]string{"site": "later"},
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: durationpb.New(time.Millisecond)},
+	}
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{{Jobs: []*pb.Job{job}}}}
+	_, status, done := runWork(req)
+	waitDone(t, done, "TestEsStopDuringRetry")
+
+	if runs := atomic.LoadInt32(&esStopRuns); runs != 1 {
+		t.Errorf("TestEsStopDuringRetry: Job was run %d times, want 1", runs)
+	}
+	js := status.Blocks[0].Jobs[0]
+	if js.Status != pb.Status_StatusFailed || !strings.Contains(js.Error, "emergency stop for site(later)") {
+		t.Errorf("TestEsStopDuringRetry: got status %v, error %q, want failed by the emergency stop", js.Status, js.Error)
+	}
+	if js.Attempts != 2 || len(js.AttemptErrors) != 2 {
+		t.Errorf("TestEsStopDuringRetry: got %d attempts with errors %v, want 2 attempts", js.Attempts, js.AttemptErrors)
+	}
+	if status.Status != pb.Status_StatusFailed || !status.WasEsStopped {
+		t.Errorf("TestEsStopDuringRetry: got Work status %v, WasEsStopped %v, want %v, true", status.Status, status.WasEsStopped, pb.Status_StatusFailed)
+	}
+}
+
+// runWork runs a Work for "req" with Run(). The returned channel is closed when it finishes.
+func runWork(req *pb.WorkReq) (*Work, *pb.StatusResp, chan struct{}) {
+	status := statusFor(req)
+	w := New(req, status)
+	ch := w.Run(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+	return w, status, done
+}
+
+// waitFor waits until "f" returns true, which is called with the Work locked.
+func waitFor(t *testing.T, w *Work, desc string, f func() bool) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		w.mu.Lock()
+		ok := f()
+		w.mu.Unlock()
+		if ok {
+			return
+		}
+	}
+	t.Fatalf("%s: timed out waiting", desc)
+}
+
+// waitDone waits for "done" to be closed.
+func waitDone(t *testing.T, done chan struct{}, desc string) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%s: Work did not finish", desc)
+	}
+}
+
+// gateBlock returns a Block with a gateJob for the gate "name".
+func gateBlock(name string) *pb.Block {
+	return &pb.Block{Jobs: []*pb.Job{{Name: "testGate", Args: map[string]string{"gate": name}}}}
+}
+
+func TestPauseResume(t *testing.T) {
+	g := newGate("pauseResume")
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{gateBlock("pauseResume"), block("b", "testRecord")}}
+	w, status, done := runWork(req)
+	<-g.started
+
+	if err := w.Resume(); err == nil {
+		t.Errorf("TestPauseResume: Resume() when not paused: got err == nil, want err != nil")
+	}
+	if err := w.Pause(); err != nil {
+		t.Fatalf("TestPauseResume: Pause() had error: %s", err)
+	}
+	if err := w.Pause(); err == nil {
+		t.Errorf("TestPauseResume: Pause() when paused: got err == nil, want err != nil")
+	}
+
+	// The running Job finishes, but the next Block must not start while we are paused.
+	close(g.open)
+	waitFor(t, w, "TestPauseResume", func() bool { return status.Blocks[0].Status == pb.Status_StatusCompleted })
+	time.Sleep(50 * time.Millisecond)
+	w.mu.Lock()
+	if status.Status != pb.Status_StatusPaused || status.Blocks[1].Status != pb.Status_StatusNotStarted {
+		t.Errorf("TestPauseResume: got status %v, Block(1) %v, want %v and Block(1) %v", status.Status, status.Blocks[1].Status, pb.Status_StatusPaused, pb.Status_StatusNotStarted)
+	}
+	w.mu.Unlock()
+
+	if err := w.Resume(); err != nil {
+		t.Fatalf("TestPauseResume: Resume() had error: %s", err)
+	}
+	waitDone(t, done, "TestPauseResume")
+
+	if status.Status != pb.Status_StatusCompleted {
+		t.Errorf("TestPauseResume: got status %v, want %v", status.Status, pb.Status_StatusCompleted)
+	}
+	for i, bs := range status.Blocks {
+		if bs.Status != pb.Status_StatusCompleted {
+			t.Errorf("TestPauseResume: Block(%d): got status %v, want %v", i, bs.Status, pb.Status_StatusCompleted)
+		}
+	}
+	if err := w.Pause(); err == nil {
+		t.Errorf("TestPauseResume: Pause() after finishing: got err == nil, want err != nil")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	// Cancelling while paused must stop us waiting to be resumed.
+	g := newGate("cancelPaused")
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{gateBlock("cancelPaused"), block("b", "testRecord")}}
+	w, status, done := runWork(req)
+	<-g.started
+
+	if err := w.Pause(); err != nil {
+		t.Fatalf("TestCancel(Cancel while paused): Pause() had error: %s", err)
+	}
+	close(g.open)
+	waitFor(t, w, "TestCancel(Cancel while paused)", func() bool { return status.Blocks[0].Status == pb.Status_StatusCompleted })
+
+	if err := w.Cancel(); err != nil {
+		t.Fatalf("TestCancel(Cancel while paused): Cancel() had error: %s", err)
+	}
+	waitDone(t, done, "TestCancel(Cancel while paused)")
+
+	if status.Status != pb.Status_StatusCancelled || status.Blocks[1].Status != pb.Status_StatusSkipped {
+		t.Errorf("TestCancel(Cancel while paused): got status %v, Block(1) %v, want %v and Block(1) %v", status.Status, status.Blocks[1].Status, pb.Status_StatusCancelled, pb.Status_StatusSkipped)
+	}
+	if err := w.Cancel(); err == nil {
+		t.Errorf("TestCancel(Cancel while paused): Cancel() after cancelling: got err == nil, want err != nil")
+	}
+
+	// Pausing after cancelling is an error, as we are stopping.
+	g = newGate("pauseCancelled")
+	req = &pb.WorkReq{Name: "test", Blocks: []*pb.Block{gateBlock("pauseCancelled")}}
+	w, status, done = runWork(req)
+	<-g.started
+
+	if err := w.Cancel(); err != nil {
+		t.Fatalf("TestCancel(Pause after Cancel): Cancel() had error: %s", err)
+	}
+	if err := w.Pause(); err == nil {
+		t.Errorf("TestCancel(Pause after Cancel): Pause(): got err == nil, want err != nil")
+	}
+	waitDone(t, done, "TestCancel(Pause after Cancel)")
+	if status.Status != pb.Status_StatusCancelled {
+		t.Errorf("TestCancel(Pause after Cancel): got status %v, want %v", status.Status, pb.Status_StatusCancelled)
+	}
+}
+
+func TestPauseWhilePolicyPaused(t *testing.T) {
+	setPauseReason("window closed")
+	defer setPauseReason("")
+
+	req := &pb.WorkReq{Name: "testPaused", Blocks: []*pb.Block{block("a", "testRecord")}}
+	w, status, done := runWork(req)
+	waitFor(t, w, "TestPauseWhilePolicyPaused", func() bool { return status.PausedBy != "" })
+
+	if err := w.Pause(); err != nil {
+		t.Fatalf("TestPauseWhilePolicyPaused: Pause() had error: %s", err)
+	}
+	if err := w.Pause(); err == nil {
+		t.Errorf("TestPauseWhilePolicyPaused: Pause() when paused: got err == nil, want err != nil")
+	}
+
+	// Once the policy allows it, we must stay paused until Resume() is called.
+	setPauseReason("")
+	waitFor(t, w, "TestPauseWhilePolicyPaused", func() bool { return status.PausedBy == "" })
+	time.Sleep(50 * time.Millisecond)
+	w.mu.Lock()
+	if status.Status != pb.Status_StatusPaused || status.Blocks[0].Status != pb.Status_StatusNotStarted {
+		t.Errorf("TestPauseWhilePolicyPaused: got status %v, Block(0) %v, want %v and Block(0) %v", status.Status, status.Blocks[0].Status, pb.Status_StatusPaused, pb.Status_StatusNotStarted)
+	}
+	w.mu.Unlock()
+
+	if err := w.Resume(); err != nil {
+		t.Fatalf("TestPauseWhilePolicyPaused: Resume() had error: %s", err)
+	}
+	waitDone(t, done, "TestPauseWhilePolicyPaused")
+	if status.Status != pb.Status_StatusCompleted {
+		t.Errorf("TestPauseWhilePolicyPaused: got status %v, want %v", status.Status, pb.Status_StatusCompleted)
+	}
+}
+
+func TestPausedByPoliciesConfigError(t *testing.T) {
+	ctx := context.Background()
+	req := &pb.WorkReq{Name: "test"}
+
+	// waitConfig waits until reading the policy config has an error if "bad" is set, or no error.
+	waitConfig := func(bad bool) {
+		t.Helper()
+		for start := time.Now(); time.Since(start) < 15*time.Second; time.Sleep(10 * time.Millisecond) {
+			if _, err := config.Policies.Read(); (err != nil) == bad {
+				return
+			}
+		}
+		t.Fatalf("TestPausedByPoliciesConfigError: policy config was not reloaded")
+	}
+
+	if reason, _ := pausedByPolicies(ctx, req); reason != "" {
+		t.Fatalf("TestPausedByPoliciesConfigError: got reason %q before the config was broken, want \"\"", reason)
+	}
+
+	if err := os.WriteFile(policiesPath, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	waitConfig(true)
+	defer func() {
+		if err := os.WriteFile(policiesPath, []byte(testPolicies), 0600); err != nil {
+			t.Fatal(err)
+		}
+		waitConfig(false)
+	}()
+
+	// Workflows without Pauser policies are paused too, as we don't know the config.
+	reason, _ := pausedByPolicies(ctx, req)
+	if !strings.Contains(reason, "policy config could not be read") {
+		t.Errorf("TestPausedByPoliciesConfigError: got reason %q, want the Work paused because of the config", reason)
+	}
+}
@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Plan reports what would happen if "req" was submitted and executed now. It does the same checks
+// as Validate() and the emergency stop checks done before execution, then asks each Job that
+// implements jobs.Planner what it would do. Unlike Validate(), it does not stop at the first
+// problem so that the report is complete. Nothing is executed.
+func Plan(ctx context.Context, req *pb.WorkReq) *pb.PlanResp {
+	resp := &pb.PlanResp{}
+	addErr := func(format string, a ...interface{}) {
+		resp.Errors = append(resp.Errors, fmt.Sprintf(format, a...))
+	}
+
+	if esStatus := es.Data.Status(req.Name); esStatus != es.Go {
+		addErr("emergency stop for(%s) was %s", req.Name, esStatus)
+	}
+	if err := validateDeps(req); err != nil {
+		addErr("%s", err)
+	}
+
+	jobsValid := true
+	for blockNum, b := range req.Blocks {
+		bp := &pb.BlockPlan{Id: b.Id, Desc: b.Desc, DependsOn: b.DependsOn, RunOn: b.RunOn}
+		resp.Blocks = append(resp.Blocks, bp)
+
+		if len(b.Jobs) == 0 {
+			addErr("Block(%d) had 0 jobs", blockNum)
+		}
+		if _, ok := pb.RunOn_name[int32(b.RunOn)]; !ok {
+			addErr("Block(%d) had an invalid RunOn(%d)", blockNum, b.RunOn)
+		}
+
+		for jobNum, j := range b.Jobs {
+			jp, valid := planJob(ctx, req.Name, j)
+			bp.Jobs = append(bp.Jobs, jp)
+			if !valid {
+				jobsValid = false
+			}
+			if jp.Error != "" {
+				addErr("Block(%d) Job(%d)(%s): %s", blockNum, jobNum, j.Name, jp.Error)
+			}
+		}
+	}
+
+	// Policies can assume Jobs are valid, as they are only run by Validate() after that is true.
+	if jobsValid {
+		if err := RunPolicies(ctx, req); err != nil {
+			addErr("%s", err)
+		}
+	}
+
+	resp.Ok = len(resp.Errors) == 0
+	return resp
+}
+
+// planJob returns the JobPlan for a Job in the workflow "name". Any problem with the Job is
+// recorded in JobPlan.Error. "valid" is false if the Job did not pass validation.
+func planJob(ctx context.Context, name string, j *pb.Job) (jp *pb.JobPlan, valid bool) {
+	jp = &pb.JobPlan{Name: j.Name, Desc: j.Desc}
+
+	job, err := jobs.GetJob(j.Name)
+	if err != nil {
+		jp.Error = fmt.Sprintf("invalid Type(%s)", j.Name)
+		return jp, false
+	}
+	if err := jobs.Validate(job, j); err != nil {
+		jp.Error = fmt.Sprintf("did not validate: %s", err)
+		return jp, false
+	}
+	if err := validateRetry(j); err != nil {
+		jp.Error = fmt.Sprintf("did not validate: %s", err)
+		return jp, false
+	}
+
+	if info, stopped := esStopped(name, j, job); stopped {
+		jp.Error = fmt.Sprintf("would be stopped by an emergency stop for %s", info.Scope())
+	}
+
+	planner, ok := job.(jobs.Planner)
+	if !ok {
+		jp.Action = fmt.Sprintf("run Job(%s) with args %v", j.Name, j.Args)
+		return jp, true
+	}
+	plan, err := planner.Plan(ctx)
+	jp.Action = plan.Action
+	jp.Sites = plan.Sites
+	jp.Machines = plan.Machines
+	if err != nil && jp.Error == "" {
+		jp.Error = fmt.Sprintf("would fail: %s", err)
+	}
+	return jp, true
+}
@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestPlanJob(t *testing.T) {
+	tests := []struct {
+		desc      string
+		job       *pb.Job
+		wantValid bool
+		wantErr   bool
+	}{
+		{
+			desc:      "Valid Job without a Plan method",
+			job:       &pb.Job{Name: "testOrder", Args: map[string]string{"id": "a"}},
+			wantValid: true,
+		},
+		{
+			desc:    "Unknown Job",
+			job:     &pb.Job{Name: "notAJob"},
+			wantErr: true,
+		},
+		{
+			desc:    "Job that does not validate",
+			job:     &pb.Job{Name: "testRecord"},
+			wantErr: true,
+		},
+		{
+			desc:      "Job that is emergency stopped",
+			job:       &pb.Job{Name: "testOrder", Args: map[string]string{"id": "a", "site": "stopped"}},
+			wantValid: true,
+			wantErr:   true,
+		},
+		{
+			desc:    "Invalid RetryPolicy",
+			job:     &pb.Job{Name: "testOrder", RetryPolicy: &pb.RetryPolicy{MaxAttempts: -1}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		jp, valid := planJob(context.Background(), "test", test.job)
+		if valid != test.wantValid {
+			t.Errorf("TestPlanJob(%s): got valid == %v, want %v", test.desc, valid, test.wantValid)
+		}
+		switch {
+		case jp.Error == "" && test.wantErr:
+			t.Errorf("TestPlanJob(%s): got JobPlan.Error == \"\", want an error", test.desc)
+		case jp.Error != "" && !test.wantErr:
+			t.Errorf("TestPlanJob(%s): got JobPlan.Error == %q, want no error", test.desc, jp.Error)
+		}
+		if test.wantValid && jp.Action == "" {
+			t.Errorf("TestPlanJob(%s): got JobPlan.Action == \"\", want an action", test.desc)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+const (
+	// defaultInitialBackoff is used when a RetryPolicy does not set initial_backoff.
+	defaultInitialBackoff = 1 * time.Second
+	// defaultMaxBackoff is used when a RetryPolicy does not set max_backoff.
+	defaultMaxBackoff = 1 * time.Minute
+)
+
+// retrier decides if and when a Job should be retried based on its RetryPolicy.
+type retrier struct {
+	policy  *pb.RetryPolicy
+	backoff time.Duration
+	max     time.Duration
+}
+
+func newRetrier(policy *pb.RetryPolicy) *retrier {
+	r := &retrier{
+		policy:  policy,
+		backoff: defaultInitialBackoff,
+		max:     defaultMaxBackoff,
+	}
+	if policy == nil {
+		r.policy = &pb.RetryPolicy{}
+	}
+	if d := r.policy.GetInitialBackoff(); d != nil {
+		r.backoff = d.AsDuration()
+	}
+	if d := r.policy.GetMaxBackoff(); d != nil {
+		r.max = d.AsDuration()
+	}
+	return r
+}
+
+// classify converts errors that match the RetryPolicy's fatal_errors into a jobs.FatalErr.
+func (r *retrier) classify(err error) error {
+	if jobs.IsFatal(err) {
+		return err
+	}
+	msg := err.Error()
+	for _, s := range r.policy.FatalErrors {
+		if strings.Contains(msg, s) {
+			return jobs.Fatalf("%w", err)
+		}
+	}
+	return err
+}
+
+// retry returns true if a Job that failed with err on attempt number "attempt" should be retried.
+// err must have been passed through classify().
+func (r *retrier) retry(attempt int, err error) bool {
+	if attempt >= int(r.policy.MaxAttempts) {
+		return false
+	}
+	if jobs.IsFatal(err) {
+		return false
+	}
+	if len(r.policy.RetryableErrors) == 0 {
+		return true
+	}
+	msg := err.Error()
+	for _, s := range r.policy.RetryableErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// wait waits for the next backoff period or until the Context is cancelled.
+func (r *retrier) wait(ctx context.Context) error {
+	timer := time.NewTimer(r.backoff)
+	defer timer.Stop()
+
+	r.backoff *= 2
+	if r.backoff > r.max {
+		r.backoff = r.max
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	return nil
+}
+
+// validateRetry validates the RetryPolicy and timeout for a Job.
+func validateRetry(job *pb.Job) error {
+	if job.Timeout != nil {
+		if err := job.Timeout.CheckValid(); err != nil {
+			return fmt.Errorf("timeout is invalid: %s", err)
+		}
+		if job.Timeout.AsDuration() <= 0 {
+			return fmt.Errorf("timeout must be > 0")
+		}
+	}
+
+	p := job.RetryPolicy
+	if p == nil {
+		return nil
+	}
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("retry_policy.max_attempts cannot be negative")
+	}
+	initial, max := defaultInitialBackoff, defaultMaxBackoff
+	if p.InitialBackoff != nil {
+		if err := p.InitialBackoff.CheckValid(); err != nil {
+			return fmt.Errorf("retry_policy.initial_backoff is invalid: %s", err)
+		}
+		initial = p.InitialBackoff.AsDuration()
+	}
+	if p.MaxBackoff != nil {
+		if err := p.MaxBackoff.CheckValid(); err != nil {
+			return fmt.Errorf("retry_policy.max_backoff is invalid: %s", err)
+		}
+		max = p.MaxBackoff.AsDuration()
+	}
+	if initial <= 0 || max <= 0 {
+		return fmt.Errorf("retry_policy backoffs must be > 0")
+	}
+	if initial > max {
+		return fmt.Errorf("retry_policy.initial_backoff(%v) cannot be greater than max_backoff(%v)", initial, max)
+	}
+	return nil
+}
+
+// runAttempt runs a single attempt of a Job. If the Job has a timeout and the attempt does not
+// finish before it, an error is returned even if the Job ignores its Context. As such a Job can
+// still be running, the returned channel is closed when Run() returns and the caller must wait
+// on it before releasing the Job's leases or retrying it.
+func runAttempt(ctx context.Context, j jobs.Job, job *pb.Job) (done <-chan struct{}, err error) {
+	ran := make(chan struct{})
+	if job.Timeout == nil {
+		defer close(ran)
+		return ran, j.Run(ctx)
+	}
+
+	timeout := job.Timeout.AsDuration()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ran)
+		ch <- j.Run(ctx)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-ch:
+		return ran, err
+	case <-timer.C:
+		return ran, fmt.Errorf("Job timed out after %v", timeout)
+	}
+}
@@ -0,0 +1,346 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/diskerase"
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/sleep"
+)
+
+// flakyJob is a Job that fails with "errs" in order and then succeeds.
+type flakyJob struct {
+	errs []error
+	runs int
+}
+
+func (f *flakyJob) Validate(job *pb.Job) error {
+	return nil
+}
+
+func (f *flakyJob) Run(ctx context.Context) error {
+	f.runs++
+	if f.runs <= len(f.errs) {
+		return f.errs[f.runs-1]
+	}
+	return nil
+}
+
+// funcJob is a Job that calls "run" when it is run.
+type funcJob struct {
+	run func(ctx context.Context) error
+}
+
+func (f funcJob) Validate(job *pb.Job) error {
+	return nil
+}
+
+func (f funcJob) Run(ctx context.Context) error {
+	return f.run(ctx)
+}
+
+func TestNewRetrier(t *testing.T) {
+	tests := []struct {
+		desc        string
+		policy      *pb.RetryPolicy
+		wantBackoff time.Duration
+		wantMax     time.Duration
+	}{
+		{desc: "No policy", policy: nil, wantBackoff: defaultInitialBackoff, wantMax: defaultMaxBackoff},
+		{desc: "No backoffs", policy: &pb.RetryPolicy{MaxAttempts: 3}, wantBackoff: defaultInitialBackoff, wantMax: defaultMaxBackoff},
+		{
+			desc:        "Backoffs set",
+			policy:      &pb.RetryPolicy{InitialBackoff: durationpb.New(time.Millisecond), MaxBackoff: durationpb.New(time.Second)},
+			wantBackoff: time.Millisecond,
+			wantMax:     time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		r := newRetrier(test.policy)
+		if r.policy == nil {
+			t.Errorf("TestNewRetrier(%s): got nil policy, want non-nil", test.desc)
+			continue
+		}
+		if r.backoff != test.wantBackoff || r.max != test.wantMax {
+			t.Errorf("TestNewRetrier(%s): got backoff %v, max %v, want %v, %v", test.desc, r.backoff, r.max, test.wantBackoff, test.wantMax)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	policy := &pb.RetryPolicy{FatalErrors: []string{"permission denied"}}
+
+	tests := []struct {
+		desc      string
+		err       error
+		wantFatal bool
+	}{
+		{desc: "Fatal error", err: jobs.Fatalf("bad arg"), wantFatal: true},
+		{desc: "Matches fatal_errors", err: errors.New("open /dev/sda: permission denied"), wantFatal: true},
+		{desc: "Wrapped error matches fatal_errors", err: fmt.Errorf("erase: %w", errors.New("permission denied")), wantFatal: true},
+		{desc: "Does not match", err: errors.New("connection refused"), wantFatal: false},
+	}
+
+	for _, test := range tests {
+		got := newRetrier(policy).classify(test.err)
+		if jobs.IsFatal(got) != test.wantFatal {
+			t.Errorf("TestClassify(%s): got fatal == %v, want %v", test.desc, jobs.IsFatal(got), test.wantFatal)
+		}
+		if got.Error() != test.err.Error() {
+			t.Errorf("TestClassify(%s): got error %q, want %q", test.desc, got, test.err)
+		}
+	}
+
+	// Without fatal_errors, only fatal errors are fatal.
+	if err := newRetrier(nil).classify(errors.New("permission denied")); jobs.IsFatal(err) {
+		t.Errorf("TestClassify(No policy): got fatal error, want not fatal")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	retryable := errors.New("connection refused")
+
+	tests := []struct {
+		desc    string
+		policy  *pb.RetryPolicy
+		attempt int
+		err     error
+		want    bool
+	}{
+		{desc: "No policy", policy: nil, attempt: 1, err: retryable, want: false},
+		{desc: "Before max_attempts", policy: &pb.RetryPolicy{MaxAttempts: 3}, attempt: 2, err: retryable, want: true},
+		{desc: "At max_attempts", policy: &pb.RetryPolicy{MaxAttempts: 3}, attempt: 3, err: retryable, want: false},
+		{desc: "Fatal error", policy: &pb.RetryPolicy{MaxAttempts: 3}, attempt: 1, err: jobs.Fatalf("bad"), want: false},
+		{
+			desc:    "Matches retryable_errors",
+			policy:  &pb.RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"refused", "timeout"}},
+			attempt: 1,
+			err:     retryable,
+			want:    true,
+		},
+		{
+			desc:    "Does not match retryable_errors",
+			policy:  &pb.RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"timeout"}},
+			attempt: 1,
+			err:     retryable,
+			want:    false,
+		},
+		{
+			desc:    "Fatal error matches retryable_errors",
+			policy:  &pb.RetryPolicy{MaxAttempts: 3, RetryableErrors: []string{"refused"}},
+			attempt: 1,
+			err:     jobs.Fatalf("connection refused"),
+			want:    false,
+		},
+	}
+
+	for _, test := range tests {
+		got := newRetrier(test.policy).retry(test.attempt, test.err)
+		if got != test.want {
+			t.Errorf("TestRetry(%s): got %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	r := newRetrier(&pb.RetryPolicy{InitialBackoff: durationpb.New(10 * time.Millisecond), MaxBackoff: durationpb.New(30 * time.Millisecond)})
+
+	wants := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond}
+	var got []time.Duration
+	for range wants {
+		want := r.backoff
+		start := time.Now()
+		if err := r.wait(context.Background()); err != nil {
+			t.Fatalf("TestWait: wait() had error: %s", err)
+		}
+		if since := time.Since(start); since < want {
+			t.Errorf("TestWait: wait() returned after %v, want at least %v", since, want)
+		}
+		got = append(got, want)
+	}
+	if diff := pretty.Compare(wants, got); diff != "" {
+		t.Errorf("TestWait: backoffs: -want/+got:\n%s", diff)
+	}
+
+	// A cancelled Context stops the wait.
+	r = newRetrier(&pb.RetryPolicy{InitialBackoff: durationpb.New(time.Hour)})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("TestWait(Cancelled): got err == %v, want context.Canceled", err)
+	}
+}
+
+func TestRunAttempt(t *testing.T) {
+	jobErr := errors.New("failed")
+
+	tests := []struct {
+		desc    string
+		timeout time.Duration
+		run     func(ctx context.Context) error
+		wantErr string
+	}{
+		{desc: "No timeout", run: func(ctx context.Context) error { return nil }},
+		{desc: "No timeout error", run: func(ctx context.Context) error { return jobErr }, wantErr: "failed"},
+		{desc: "Within timeout", timeout: time.Second, run: func(ctx context.Context) error { return nil }},
+		{desc: "Within timeout error", timeout: time.Second, run: func(ctx context.Context) error { return jobErr }, wantErr: "failed"},
+		{
+			desc:    "Honors Context",
+			timeout: 10 * time.Millisecond,
+			run: func(ctx context.Context) error {
+				<-ctx.Done()
+				// Give the timer a chance to fire first.
+				time.Sleep(10 * time.Millisecond)
+				return ctx.Err()
+			},
+			wantErr: "Job timed out after 10ms",
+		},
+		{
+			desc:    "Ignores Context",
+			timeout: 10 * time.Millisecond,
+			run: func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return nil
+			},
+			wantErr: "Job timed out after 10ms",
+		},
+	}
+
+	for _, test := range tests {
+		job := &pb.Job{}
+		if test.timeout > 0 {
+			job.Timeout = durationpb.New(test.timeout)
+		}
+		returned := make(chan struct{})
+		run := test.run
+		j := funcJob{run: func(ctx context.Context) error {
+			defer close(returned)
+			return run(ctx)
+		}}
+
+		done, err := runAttempt(context.Background(), j, job)
+		switch {
+		case err == nil && test.wantErr != "":
+			t.Errorf("TestRunAttempt(%s): got err == nil, want %q", test.desc, test.wantErr)
+		case err != nil && err.Error() != test.wantErr:
+			t.Errorf("TestRunAttempt(%s): got err == %q, want %q", test.desc, err, test.wantErr)
+		}
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("TestRunAttempt(%s): done was never closed", test.desc)
+		}
+		select {
+		case <-returned:
+		default:
+			t.Errorf("TestRunAttempt(%s): done was closed before Run() returned", test.desc)
+		}
+	}
+}
+
+func TestRunAttemptBuiltinJobs(t *testing.T) {
+	tests := []struct {
+		desc string
+		job  *pb.Job
+	}{
+		{desc: "sleep", job: &pb.Job{Name: "sleep", Args: map[string]string{"seconds": "3600"}}},
+		// diskErase only uses its args to plan, so they are not validated here.
+		{desc: "diskErase", job: &pb.Job{Name: "diskErase"}},
+	}
+
+	for _, test := range tests {
+		j, err := jobs.GetJob(test.job.Name)
+		if err != nil {
+			t.Fatalf("TestRunAttemptBuiltinJobs(%s): %s", test.desc, err)
+		}
+		if len(test.job.Args) > 0 {
+			if err := jobs.Validate(j, test.job); err != nil {
+				t.Fatalf("TestRunAttemptBuiltinJobs(%s): Validate() had error: %s", test.desc, err)
+			}
+		}
+		test.job.Timeout = durationpb.New(10 * time.Millisecond)
+
+		done, err := runAttempt(context.Background(), j, test.job)
+		if err == nil || err.Error() != "Job timed out after 10ms" {
+			t.Errorf("TestRunAttemptBuiltinJobs(%s): got err == %v, want a timeout", test.desc, err)
+		}
+		// The Job must stop when its Context is cancelled, not after it has finished sleeping.
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Errorf("TestRunAttemptBuiltinJobs(%s): Run() did not return after the timeout", test.desc)
+		}
+	}
+}
+
+func TestRunJobAttempts(t *testing.T) {
+	job := &pb.Job{
+		Name:        "flaky",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoff: durationpb.New(time.Millisecond)},
+	}
+	req := &pb.WorkReq{Name: "test", Blocks: []*pb.Block{{Jobs: []*pb.Job{job}}}}
+
+	tests := []struct {
+		desc         string
+		errs         []error
+		wantErr      bool
+		wantAttempts int32
+		wantErrs     []string
+	}{
+		{desc: "Success", wantAttempts: 1},
+		{
+			desc:         "Fails then succeeds",
+			errs:         []error{errors.New("first"), errors.New("second")},
+			wantAttempts: 3,
+			wantErrs:     []string{"first", "second"},
+		},
+		{
+			desc:         "Fails every attempt",
+			errs:         []error{errors.New("first"), errors.New("second"), errors.New("third")},
+			wantErr:      true,
+			wantAttempts: 3,
+			wantErrs:     []string{"first", "second", "third"},
+		},
+		{
+			desc:         "Fatal error",
+			errs:         []error{jobs.Fatalf("fatal")},
+			wantErr:      true,
+			wantAttempts: 1,
+			wantErrs:     []string{"fatal"},
+		},
+	}
+
+	for _, test := range tests {
+		status := statusFor(req)
+		w := New(req, status)
+		js := status.Blocks[0].Jobs[0]
+
+		err := w.runJob(context.Background(), &flakyJob{errs: test.errs}, job, js)
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestRunJobAttempts(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestRunJobAttempts(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+
+		if js.Attempts != test.wantAttempts {
+			t.Errorf("TestRunJobAttempts(%s): got Attempts %d, want %d", test.desc, js.Attempts, test.wantAttempts)
+		}
+		if diff := pretty.Compare(test.wantErrs, js.AttemptErrors); diff != "" {
+			t.Errorf("TestRunJobAttempts(%s): AttemptErrors: -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
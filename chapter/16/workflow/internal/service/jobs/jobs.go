@@ -4,12 +4,27 @@ Jobs.
 
 Packages that contain jobs can register themselves by doing:
 	func init() {
-		jobs.Register("name", job)
+		jobs.Register("name", newJob)
 	}
 If there is a duplicate name, this will panic.
 
 Fetching a Job is simply:
 	GetJob(jt string) (Job, error)
+
+Every call to GetJob() returns a new Job instance, so a Job can store the arguments it parses in
+Validate() for use in Run() without affecting other Jobs of the same type that are running concurrently.
+
+A Job can optionally implement Describer to describe itself and the args it takes, which
+Validate() uses to check its args before calling Job.Validate(). Jobs should be validated with
+Validate() instead of calling Job.Validate() directly.
+
+A Job can optionally implement Planner to describe what it would do without doing it,
+Targeter to report the sites and machines it acts on, which scoped emergency stops use, and
+Locker to report the resources it changes, which the executor leases so that Jobs in other
+workflows cannot change them at the same time.
+
+A Job can record output, such as from a command it ran, in its pb.JobStatus by writing to
+Output(ctx) in Run().
 */
 package jobs
 
@@ -17,34 +32,58 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"sort"
 	"strings"
 
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
-var jobs = map[string]Job{}
+var jobs = map[string]New{}
 
-// Register registers a Job so that it can be executed.
-func Register(name string, job Job) {
+// New returns a new instance of a Job.
+type New func() Job
+
+// Register registers a Job so that it can be executed. "newJob" is called to create
+// a new instance of the Job for every pb.Job that is validated or run.
+func Register(name string, newJob New) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		panic("cannot Register empty JobType")
 	}
+	if newJob == nil {
+		panic(fmt.Sprintf("cannot register Job(%s) with a nil New", name))
+	}
 	if _, ok := jobs[name]; ok {
 		panic(fmt.Sprintf("cannot register Job(%s) twice", name))
 	}
+	if d, ok := newJob().(Describer); ok {
+		if err := d.Describe().validate(); err != nil {
+			panic(fmt.Sprintf("Job(%s) Schema %s", name, err))
+		}
+	}
 	log.Println("Registered Job: ", name)
-	jobs[name] = job
+	jobs[name] = newJob
 }
 
-// GetJob returns a Job by its type from the registry.
+// GetJob returns a new instance of a Job by its type from the registry.
 func GetJob(name string) (Job, error) {
-	j, ok := jobs[name]
+	newJob, ok := jobs[name]
 	if !ok {
 		return nil, fmt.Errorf("Job(%v) not found", name)
 	}
-	return j, nil
+	return newJob(), nil
+}
+
+// List returns the names of all registered Jobs, sorted.
+func List() []string {
+	l := make([]string, 0, len(jobs))
+	for name := range jobs {
+		l = append(l, name)
+	}
+	sort.Strings(l)
+	return l
 }
 
 // FatalErr is a an error that should terminate a Workflow.
@@ -84,10 +123,73 @@ func (f FatalErr) Unwrap() error {
 	return errors.Unwrap(f.err)
 }
 
-// Job executes some type of work.
+// Job executes some type of work. A Job instance is only used for a single pb.Job.
 type Job interface {
-	// Validate validates that the Job settings sent to the server are valid.
+	// Validate validates that the Job settings sent to the server are valid. The Job
+	// should store any parsed settings it needs for Run(). Validate() is always called
+	// before Run().
 	Validate(job *pb.Job) error
-	// Run runs the Job settings.
-	Run(ctx context.Context, job *pb.Job) error
+	// Run runs the Job with the settings passed to Validate(). "ctx" has the OpenTelemetry span
+	// for the Job, so the Job can add its own spans with it, and the Job's Output(). Run should
+	// return when "ctx" is cancelled, as that is how the Job's timeout and cancellation stop it.
+	Run(ctx context.Context) error
+}
+
+type outputKey struct{}
+
+// WithOutput returns a Context that Output() returns "w" for. This is used by the executor.
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, w)
+}
+
+// Output returns where a Job's output is written from the Context passed to Job.Run(). What is
+// written is recorded in the Job's pb.JobStatus. If there is none, such as when not run by the
+// executor, output is discarded.
+func Output(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(outputKey{}).(io.Writer); ok {
+		return w
+	}
+	return io.Discard
+}
+
+// Plan describes what a Job would do if it was run.
+type Plan struct {
+	// Action is a human readable description of what the Job would do.
+	Action string
+	// Sites are the sites the Job would act on.
+	Sites []string
+	// Machines are the machines the Job would act on.
+	Machines []string
+}
+
+// Planner is an optional interface that a Job can implement to describe what it would do
+// if it was run.
+type Planner interface {
+	// Plan returns what the Job would do with the settings passed to Validate(). It must
+	// not have any side effects. An error indicates the Job would fail if it was run now.
+	Plan(ctx context.Context) (Plan, error)
+}
+
+// Targets are the sites and machines a Job acts on.
+type Targets struct {
+	// Sites are the sites the Job acts on.
+	Sites []string
+	// Machines are the machines the Job acts on.
+	Machines []string
+}
+
+// Locker is an optional interface that a Job can implement to report the resources it changes.
+// Before each attempt of the Job, the executor acquires exclusive leases on them for the workflow,
+// so Jobs in other workflows cannot change them at the same time. See the lease package for how
+// resources are named, such as "site" or "site/machine".
+type Locker interface {
+	// Resources returns the resources the Job changes with the settings passed to Validate().
+	Resources() []string
+}
+
+// Targeter is an optional interface that a Job can implement to report the sites and machines
+// it acts on. Emergency stops scoped to a site or machine only apply to Jobs that implement this.
+type Targeter interface {
+	// Targets returns what the Job acts on with the settings passed to Validate().
+	Targets() Targets
 }
@@ -8,6 +8,8 @@ Args:
 	"site"(mandatory): The name of the site, like "aaa" or "aba"
 Result:
 	Erases a disk on a machine, except this is a demo, so it really just sleeps for 30 seconds.
+Leases:
+	"[site]/[machine]", so Jobs in other workflows cannot act on the machine at the same time.
 */
 package diskerase
 
@@ -23,7 +25,15 @@ import (
 
 // This registers our Job on server startup.
 func init() {
-	jobs.Register("diskErase", newJob())
+	jobs.Register("diskErase", newJob)
+}
+
+var schema = jobs.Schema{
+	Desc: "Erases the disk on a machine",
+	Args: []jobs.Arg{
+		{Name: "machine", Desc: `The name of the machine, like "aa01" or "ab02"`, Required: true},
+		{Name: "site", Desc: `The name of the site, like "aaa" or "aba"`, Required: true},
+	},
 }
 
 type args struct {
@@ -31,33 +41,12 @@ type args struct {
 	site    string
 }
 
+// validate parses "args", which have been checked against our schema.
 func (a *args) validate(args map[string]string) error {
-	must := map[string]bool{
-		"machine": false,
-		"site":    false,
-	}
-
-	for k, v := range args {
-		switch k {
-		case "machine":
-			must["machine"] = true
-			a.machine = v
-		case "site":
-			if _, ok := sites.Data.Sites[v]; !ok {
-				return fmt.Errorf("site(%s) arg was not a valid site", v)
-			}
-			must["site"] = true
-			a.site = v
-
-		default:
-			return fmt.Errorf("invalid arg(%s)", k)
-		}
-	}
-
-	for k, v := range must {
-		if !v {
-			return fmt.Errorf("missing required arg(%s)", k)
-		}
+	a.machine = args["machine"]
+	a.site = args["site"]
+	if _, ok := sites.Data.Sites[a.site]; !ok {
+		return fmt.Errorf("site(%s) arg was not a valid site", a.site)
 	}
 
 	fullName := fmt.Sprintf("%s.%s", a.machine, a.site)
@@ -74,7 +63,7 @@ type Job struct {
 	args args
 }
 
-func newJob() *Job {
+func newJob() jobs.Job {
 	return &Job{}
 }
 
@@ -88,8 +77,44 @@ func (j *Job) Validate(job *pb.Job) error {
 	return nil
 }
 
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
 // Run implements jobs.Job.Run().
-func (j *Job) Run(ctx context.Context, job *pb.Job) error {
-	time.Sleep(30 * time.Second) // A crude and inaccurate simulation of a disk erasure
-	return nil
+func (j *Job) Run(ctx context.Context) error {
+	// A crude and inaccurate simulation of a disk erasure.
+	timer := time.NewTimer(30 * time.Second)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Plan implements jobs.Planner.Plan().
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	t := j.Targets()
+	return jobs.Plan{
+		Action:   fmt.Sprintf("erase the disk on machine(%s)", j.args.machine),
+		Sites:    t.Sites,
+		Machines: t.Machines,
+	}, nil
+}
+
+// Resources implements jobs.Locker.Resources().
+func (j *Job) Resources() []string {
+	return []string{j.args.site + "/" + j.args.machine}
+}
+
+// Targets implements jobs.Targeter.Targets().
+func (j *Job) Targets() jobs.Targets {
+	return jobs.Targets{
+		Sites:    []string{j.args.site},
+		Machines: []string{j.args.machine},
+	}
 }
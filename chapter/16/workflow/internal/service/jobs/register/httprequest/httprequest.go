@@ -0,0 +1,234 @@
+/*
+Package httprequest registers a job that sends an HTTP request, such as to call a webhook or
+another service's API.
+
+Register name: "httpRequest"
+Args:
+	"url"(mandatory): The http or https URL to send the request to
+	"method"(optional): The HTTP method, defaults to "GET"
+	"header.[name]"(optional): Sets the header [name], like "header.Content-Type": "application/json"
+	"body"(optional): A text/template for the request body. Vars are available as {{.Vars.[name]}}
+		and the "json" function quotes a value as a JSON string, like {{json .Vars.machine}}
+	"var.[name]"(optional): A value available to the body template
+	"status"(optional): Comma separated status codes that mean success, like "200,204". Defaults
+		to any 2xx status
+Result:
+	The request is sent and the response had an expected status. The status and the start of the
+	response body are recorded in the Job's output.
+
+	A request that takes longer than 1 minute, or the Job's timeout if that is shorter, fails.
+	Errors that may go away if the Job is retried, such as the server being unreachable, not
+	responding in time or returning a 5xx or 429 status, contain "(retryable)", which can be
+	used in a RetryPolicy's retryable_errors. Other unexpected statuses are fatal.
+*/
+package httprequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This registers our Job on server startup.
+func init() {
+	jobs.Register("httpRequest", newJob)
+}
+
+// maxOutput is how much of the response body is recorded in the Job's output.
+const maxOutput = 4 * 1024
+
+// client sends our requests. Its timeout stops a server that never responds from blocking a Job
+// that does not have a timeout, and the resources it leased, forever.
+var client = &http.Client{Timeout: 1 * time.Minute}
+
+var schema = jobs.Schema{
+	Desc: "Sends an HTTP request",
+	Args: []jobs.Arg{
+		{Name: "url", Desc: "The http or https URL to send the request to", Required: true},
+		{
+			Name:    "method",
+			Desc:    "The HTTP method",
+			Allowed: []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions},
+			Default: http.MethodGet,
+		},
+		{Name: "header.", Desc: `Sets the header named after the prefix, like "header.Content-Type"`, Prefix: true},
+		{Name: "body", Desc: "A text/template for the request body. Vars are available as {{.Vars.[name]}}"},
+		{Name: "var.", Desc: "A value available to the body template as the name after the prefix", Prefix: true},
+		{Name: "status", Desc: `Comma separated status codes that mean success, like "200,204". Defaults to any 2xx status`},
+	},
+}
+
+var funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+type args struct {
+	url     string
+	method  string
+	headers http.Header
+	body    string
+	status  map[int]bool
+}
+
+// validate parses "args", which have been checked against our schema.
+func (a *args) validate(args map[string]string) error {
+	a.method = http.MethodGet
+	a.headers = http.Header{}
+
+	var (
+		body string
+		vars = map[string]string{}
+	)
+	for k, v := range args {
+		switch {
+		case k == "url":
+			u, err := url.Parse(v)
+			if err != nil {
+				return fmt.Errorf("arg(url) is not a valid URL: %w", err)
+			}
+			if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("arg(url) must be an http or https URL, was %q", v)
+			}
+			a.url = v
+		case k == "method":
+			a.method = v
+		case strings.HasPrefix(k, "header."):
+			a.headers.Set(strings.TrimPrefix(k, "header."), v)
+		case k == "body":
+			body = v
+		case strings.HasPrefix(k, "var."):
+			vars[strings.TrimPrefix(k, "var.")] = v
+		case k == "status":
+			codes, err := parseStatus(v)
+			if err != nil {
+				return err
+			}
+			a.status = codes
+		}
+	}
+
+	if body == "" {
+		return nil
+	}
+	// We render the body here so that problems are found before the workflow runs.
+	tmpl, err := template.New("body").Funcs(funcs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return fmt.Errorf("arg(body) is not a valid template: %w", err)
+	}
+	buff := strings.Builder{}
+	if err := tmpl.Execute(&buff, struct{ Vars map[string]string }{vars}); err != nil {
+		return fmt.Errorf("arg(body) could not be rendered: %w", err)
+	}
+	a.body = buff.String()
+	return nil
+}
+
+// parseStatus parses the "status" arg.
+func parseStatus(s string) (map[int]bool, error) {
+	codes := map[int]bool{}
+	for _, v := range strings.Split(s, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("arg(status) must be comma separated HTTP status codes, had %q", v)
+		}
+		codes[code] = true
+	}
+	return codes, nil
+}
+
+// expected returns true if "code" means the request was successful.
+func (a *args) expected(code int) bool {
+	if a.status == nil {
+		return code >= 200 && code <= 299
+	}
+	return a.status[code]
+}
+
+// Job implements jobs.Job.
+type Job struct {
+	args args
+}
+
+func newJob() jobs.Job {
+	return &Job{}
+}
+
+// Validate implements jobs.Job.Validate().
+func (j *Job) Validate(job *pb.Job) error {
+	a := args{}
+	if err := a.validate(job.Args); err != nil {
+		return err
+	}
+	j.args = a
+	return nil
+}
+
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
+// Run implements jobs.Job.Run().
+func (j *Job) Run(ctx context.Context) error {
+	out := jobs.Output(ctx)
+
+	var body io.Reader
+	if j.args.body != "" {
+		body = strings.NewReader(j.args.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, j.args.method, j.args.url, body)
+	if err != nil {
+		return jobs.Fatalf("could not create request: %s", err)
+	}
+	req.Header = j.args.headers.Clone()
+
+	fmt.Fprintf(out, "%s %s\n", j.args.method, j.args.url)
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s %s: %w", j.args.method, j.args.url, ctx.Err())
+		}
+		return fmt.Errorf("%s %s (retryable): %w", j.args.method, j.args.url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutput))
+	fmt.Fprintf(out, "%s\n", resp.Status)
+	if len(respBody) > 0 {
+		out.Write(respBody)
+		if !bytes.HasSuffix(respBody, []byte("\n")) {
+			fmt.Fprintln(out)
+		}
+	}
+
+	if j.args.expected(resp.StatusCode) {
+		return nil
+	}
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%s %s returned %s (retryable)", j.args.method, j.args.url, resp.Status)
+	}
+	return jobs.Fatalf("%s %s returned %s", j.args.method, j.args.url, resp.Status)
+}
+
+// Plan implements jobs.Planner.Plan().
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	action := fmt.Sprintf("send %s %s", j.args.method, j.args.url)
+	if j.args.body != "" {
+		action += fmt.Sprintf(" with a %d byte body", len(j.args.body))
+	}
+	return jobs.Plan{Action: action}, nil
+}
@@ -0,0 +1,182 @@
+package httprequest
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc     string
+		args     map[string]string
+		wantBody string
+		wantErr  bool
+	}{
+		{desc: "Only a URL", args: map[string]string{"url": "https://example.com/hook"}},
+		{
+			desc: "Body template",
+			args: map[string]string{
+				"url":         "https://example.com/hook",
+				"method":      "POST",
+				"var.machine": `aa01"`,
+				"body":        `{"machine": {{json .Vars.machine}}}`,
+			},
+			wantBody: `{"machine": "aa01\""}`,
+		},
+		{desc: "Missing URL", args: map[string]string{"method": "GET"}, wantErr: true},
+		{desc: "Not http", args: map[string]string{"url": "ftp://example.com"}, wantErr: true},
+		{desc: "Bad method", args: map[string]string{"url": "https://example.com", "method": "get"}, wantErr: true},
+		{desc: "Bad status", args: map[string]string{"url": "https://example.com", "status": "200,ok"}, wantErr: true},
+		{desc: "Unknown arg", args: map[string]string{"url": "https://example.com", "machine": "aa01"}, wantErr: true},
+		{desc: "Empty header name", args: map[string]string{"url": "https://example.com", "header.": "a"}, wantErr: true},
+		{desc: "Bad template", args: map[string]string{"url": "https://example.com", "body": "{{.Vars"}, wantErr: true},
+		{desc: "Missing var", args: map[string]string{"url": "https://example.com", "body": "{{.Vars.site}}"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		j := &Job{}
+		err := jobs.Validate(j, &pb.Job{Name: "httpRequest", Args: test.args})
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestValidate(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestValidate(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+		if j.args.body != test.wantBody {
+			t.Errorf("TestValidate(%s): got body %q, want %q", test.desc, j.args.body, test.wantBody)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotMethod, gotHeader, gotBody = r.Method, r.Header.Get("X-Test"), string(b)
+
+		switch r.URL.Path {
+		case "/ok":
+			w.Write([]byte("all good"))
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		desc       string
+		args       map[string]string
+		wantErr    bool
+		wantFatal  bool
+		wantOutput string
+	}{
+		{
+			desc: "Success",
+			args: map[string]string{
+				"url":           srv.URL + "/ok",
+				"method":        "PUT",
+				"header.X-Test": "yes",
+				"var.v":         "hello",
+				"body":          "{{.Vars.v}}",
+			},
+			wantOutput: "PUT " + srv.URL + "/ok\n200 OK\nall good\n",
+		},
+		{desc: "Other 2xx", args: map[string]string{"url": srv.URL + "/created"}},
+		{desc: "Not an expected status", args: map[string]string{"url": srv.URL + "/created", "status": "200"}, wantErr: true, wantFatal: true},
+		{desc: "Expected 404", args: map[string]string{"url": srv.URL + "/missing", "status": "404"}},
+		{desc: "404", args: map[string]string{"url": srv.URL + "/missing"}, wantErr: true, wantFatal: true},
+		{desc: "503", args: map[string]string{"url": srv.URL + "/unavailable"}, wantErr: true},
+		{desc: "429", args: map[string]string{"url": srv.URL + "/limited"}, wantErr: true},
+		{desc: "Unreachable", args: map[string]string{"url": "http://127.0.0.1:1/"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		j := &Job{}
+		if err := j.Validate(&pb.Job{Name: "httpRequest", Args: test.args}); err != nil {
+			t.Fatalf("TestRun(%s): Validate() had error: %s", test.desc, err)
+		}
+		out := &bytes.Buffer{}
+
+		err := j.Run(jobs.WithOutput(context.Background(), out))
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestRun(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestRun(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if jobs.IsFatal(err) != test.wantFatal {
+				t.Errorf("TestRun(%s): got IsFatal() == %v, want %v", test.desc, jobs.IsFatal(err), test.wantFatal)
+			}
+			if retryable := strings.Contains(err.Error(), "(retryable)"); retryable == test.wantFatal {
+				t.Errorf("TestRun(%s): got retryable == %v, want %v: %s", test.desc, retryable, !test.wantFatal, err)
+			}
+		}
+
+		if test.wantOutput != "" && out.String() != test.wantOutput {
+			t.Errorf("TestRun(%s): got output %q, want %q", test.desc, out.String(), test.wantOutput)
+		}
+	}
+
+	// Check the request of the first test was sent as configured.
+	j := &Job{}
+	if err := j.Validate(&pb.Job{Name: "httpRequest", Args: tests[0].args}); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Run(context.Background()); err != nil {
+		t.Fatalf("TestRun: Run() had error: %s", err)
+	}
+	if gotMethod != "PUT" || gotHeader != "yes" || gotBody != "hello" {
+		t.Errorf("TestRun: got request %s with X-Test %q and body %q, want PUT with X-Test \"yes\" and body \"hello\"", gotMethod, gotHeader, gotBody)
+	}
+}
+
+func TestRunHangingServer(t *testing.T) {
+	// The server does not respond until the test is done.
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer srv.Close()
+	defer close(hang)
+
+	old := client
+	client = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { client = old }()
+
+	j := &Job{}
+	if err := j.Validate(&pb.Job{Name: "httpRequest", Args: map[string]string{"url": srv.URL}}); err != nil {
+		t.Fatalf("TestRunHangingServer: Validate() had error: %s", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- j.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "(retryable)") {
+			t.Errorf("TestRunHangingServer: got err == %v, want a retryable error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestRunHangingServer: Run() did not return when the server did not respond")
+	}
+}
@@ -0,0 +1,325 @@
+/*
+Package remoteexec registers a job that runs a program on a machine using the system agent
+from chapter 8. The agent runs a program by installing a package, a .zip file holding the
+program, into a container on the machine and starting it with systemd. The workflow server
+connects to the agent over SSH.
+
+Register name: "remoteExec"
+Args:
+	"machine"(mandatory): The name of the machine, like "aa01" or "ab02"
+	"site"(mandatory): The name of the site, like "aaa" or "aba"
+	"endpoint"(optional): The host:port of SSH on the machine, defaults to "[machine].[site]:22"
+	"action"(mandatory): "install" to install a package and run its binary, "remove" to stop and remove it
+	"name"(mandatory): The name of the package on the agent
+	"package"(mandatory for install): The .zip file to install, relative to Config.PackageDir
+	"binary"(mandatory for install): The binary in the package to run
+	"args"(optional): Space separated arguments to run the binary with
+Result:
+	The package is installed and running, or is removed. The agent does not return the
+	program's output, so the Job's output records each step taken with the agent.
+
+	Errors that may go away if the Job is retried, such as the machine being unreachable,
+	contain "(retryable)", which can be used in a RetryPolicy's retryable_errors. Errors that
+	will not, such as failing SSH authentication or the agent rejecting the package, are fatal.
+Leases:
+	"[site]/[machine]", so Jobs in other workflows cannot act on the machine at the same time.
+
+The server must call Init() to say how to connect to agents before running these Jobs.
+*/
+package remoteexec
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/8/agent/client"
+	agentpb "github.com/PacktPublishing/Go-for-DevOps/chapter/8/agent/proto"
+)
+
+// This registers our Job on server startup.
+func init() {
+	jobs.Register("remoteExec", newJob)
+}
+
+// Config is how we connect to agents.
+type Config struct {
+	// SSHKeyFile is the file holding the private key used to connect with SSH. If not set,
+	// the SSH agent at $SSH_AUTH_SOCK is used.
+	SSHKeyFile string
+	// PackageDir is the directory "package" args are relative to.
+	PackageDir string
+}
+
+var (
+	conf   = Config{PackageDir: "packages"}
+	signer ssh.Signer
+)
+
+// Init sets how we connect to agents. This must be called before any Jobs are validated or run.
+func Init(c Config) error {
+	if c.SSHKeyFile != "" {
+		b, err := os.ReadFile(c.SSHKeyFile)
+		if err != nil {
+			return fmt.Errorf("could not read SSH key: %w", err)
+		}
+		s, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			return fmt.Errorf("could not parse SSH key(%s): %w", c.SSHKeyFile, err)
+		}
+		signer = s
+	}
+	conf = c
+	return nil
+}
+
+// agent is the part of the agent client we use. This allows us to fake it in tests.
+type agent interface {
+	Install(ctx context.Context, req *agentpb.InstallReq) (*agentpb.InstallResp, error)
+	Remove(ctx context.Context, req *agentpb.RemoveReq) (*agentpb.RemoveResp, error)
+	Close() error
+}
+
+// dial connects to the agent on the machine with SSH at "endpoint".
+var dial = func(endpoint string, auth []ssh.AuthMethod) (agent, error) {
+	return client.New(endpoint, auth)
+}
+
+type args struct {
+	machine  string
+	site     string
+	endpoint string
+	action   string
+	name     string
+	pkg      string
+	binary   string
+	args     []string
+}
+
+var schema = jobs.Schema{
+	Desc: "Installs or removes a package on a machine with the system agent",
+	Args: []jobs.Arg{
+		{Name: "machine", Desc: `The name of the machine, like "aa01" or "ab02"`, Required: true},
+		{Name: "site", Desc: `The name of the site, like "aaa" or "aba"`, Required: true},
+		{Name: "endpoint", Desc: "The host:port of SSH on the machine", Default: "[machine].[site]:22"},
+		{
+			Name:     "action",
+			Desc:     "install to install a package and run its binary, remove to stop and remove it",
+			Required: true,
+			Allowed:  []string{"install", "remove"},
+		},
+		{Name: "name", Desc: "The name of the package on the agent", Required: true},
+		{Name: "package", Desc: "The .zip file to install, relative to the package directory. Required for install"},
+		{Name: "binary", Desc: "The binary in the package to run. Required for install"},
+		{Name: "args", Desc: "Space separated arguments to run the binary with"},
+	},
+}
+
+// validate parses "args", which have been checked against our schema.
+func (a *args) validate(args map[string]string) error {
+	a.machine = args["machine"]
+	a.site = args["site"]
+	a.endpoint = args["endpoint"]
+	a.action = args["action"]
+	a.name = args["name"]
+	a.pkg = args["package"]
+	a.binary = args["binary"]
+	if v, ok := args["args"]; ok {
+		a.args = strings.Fields(v)
+	}
+
+	if _, ok := sites.Data.Sites[a.site]; !ok {
+		return fmt.Errorf("site(%s) arg was not a valid site", a.site)
+	}
+	if a.endpoint != "" {
+		if _, _, err := net.SplitHostPort(a.endpoint); err != nil {
+			return fmt.Errorf("arg(endpoint) must be host:port, was %q", a.endpoint)
+		}
+	}
+	if strings.TrimSpace(a.name) == "" {
+		return fmt.Errorf("arg(name) cannot be empty")
+	}
+
+	fullName := fmt.Sprintf("%s.%s", a.machine, a.site)
+	if _, ok := sites.Data.Machines[fullName]; !ok {
+		return fmt.Errorf("invalid arg(machine): machine(%s) does not exist", fullName)
+	}
+	if a.endpoint == "" {
+		a.endpoint = net.JoinHostPort(fullName, "22")
+	}
+
+	if a.action == "remove" {
+		if a.pkg != "" || a.binary != "" || a.args != nil {
+			return fmt.Errorf("args(package, binary, args) cannot be used with action(remove)")
+		}
+		return nil
+	}
+	if a.binary == "" {
+		return fmt.Errorf("missing required arg(binary) for action(install)")
+	}
+	return a.validatePackage()
+}
+
+// validatePackage checks that the package is a .zip file in the package directory.
+func (a *args) validatePackage() error {
+	if a.pkg == "" {
+		return fmt.Errorf("missing required arg(package) for action(install)")
+	}
+	p := filepath.Clean(a.pkg)
+	if filepath.IsAbs(p) || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("arg(package) must be relative to the package directory, was %q", a.pkg)
+	}
+	if filepath.Ext(p) != ".zip" {
+		return fmt.Errorf("arg(package) must be a .zip file, was %q", a.pkg)
+	}
+	a.pkg = filepath.Join(conf.PackageDir, p)
+	fi, err := os.Stat(a.pkg)
+	if err != nil {
+		return fmt.Errorf("arg(package): %w", err)
+	}
+	if !fi.Mode().IsRegular() {
+		return fmt.Errorf("arg(package) %q is not a file", a.pkg)
+	}
+	return nil
+}
+
+// Job implements jobs.Job.
+type Job struct {
+	args args
+}
+
+func newJob() jobs.Job {
+	return &Job{}
+}
+
+// Validate implements jobs.Job.Validate().
+func (j *Job) Validate(job *pb.Job) error {
+	a := args{}
+	if err := a.validate(job.Args); err != nil {
+		return err
+	}
+	j.args = a
+	return nil
+}
+
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
+// Run implements jobs.Job.Run().
+func (j *Job) Run(ctx context.Context) error {
+	out := jobs.Output(ctx)
+
+	var pkg []byte
+	if j.args.action == "install" {
+		var err error
+		pkg, err = os.ReadFile(j.args.pkg)
+		if err != nil {
+			return jobs.Fatalf("could not read package(%s): %s", j.args.pkg, err)
+		}
+	}
+
+	auth, closeAuth, err := sshAuth()
+	if err != nil {
+		return jobs.Fatalf("could not get SSH credentials: %s", err)
+	}
+	defer closeAuth()
+
+	fmt.Fprintf(out, "connecting to the agent on %s\n", j.args.endpoint)
+	c, err := dial(j.args.endpoint, []ssh.AuthMethod{auth})
+	if err != nil {
+		fmt.Fprintf(out, "could not connect: %s\n", err)
+		return dialErr(j.args.endpoint, err)
+	}
+	defer c.Close()
+
+	switch j.args.action {
+	case "install":
+		fmt.Fprintf(out, "installing package(%s) from %s and running: %s\n", j.args.name, j.args.pkg, strings.Join(append([]string{j.args.binary}, j.args.args...), " "))
+		_, err = c.Install(ctx, &agentpb.InstallReq{Name: j.args.name, Package: pkg, Binary: j.args.binary, Args: j.args.args})
+	case "remove":
+		fmt.Fprintf(out, "removing package(%s)\n", j.args.name)
+		_, err = c.Remove(ctx, &agentpb.RemoveReq{Name: j.args.name})
+	}
+	if err != nil {
+		fmt.Fprintf(out, "agent returned an error: %s\n", err)
+		return agentErr(ctx, j.args.endpoint, err)
+	}
+	fmt.Fprintf(out, "%s of package(%s) completed\n", j.args.action, j.args.name)
+	return nil
+}
+
+// Plan implements jobs.Planner.Plan().
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	t := j.Targets()
+	action := fmt.Sprintf("remove package(%s) with the agent on %s", j.args.name, j.args.endpoint)
+	if j.args.action == "install" {
+		action = fmt.Sprintf(
+			"install package(%s) from %s with the agent on %s and run: %s",
+			j.args.name, j.args.pkg, j.args.endpoint, strings.Join(append([]string{j.args.binary}, j.args.args...), " "),
+		)
+	}
+	return jobs.Plan{Action: action, Sites: t.Sites, Machines: t.Machines}, nil
+}
+
+// Resources implements jobs.Locker.Resources().
+func (j *Job) Resources() []string {
+	return []string{j.args.site + "/" + j.args.machine}
+}
+
+// Targets implements jobs.Targeter.Targets().
+func (j *Job) Targets() jobs.Targets {
+	return jobs.Targets{
+		Sites:    []string{j.args.site},
+		Machines: []string{j.args.machine},
+	}
+}
+
+// sshAuth returns how we authenticate with SSH. The returned function must be called
+// when the connection is no longer needed.
+func sshAuth() (ssh.AuthMethod, func(), error) {
+	if signer != nil {
+		return ssh.PublicKeys(signer), func() {}, nil
+	}
+	conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("no SSH key was configured and could not connect to the SSH agent: %w", err)
+	}
+	return ssh.PublicKeysCallback(sshagent.NewClient(conn).Signers), func() { conn.Close() }, nil
+}
+
+// dialErr converts an error connecting to "endpoint" to a Job error. Failing to authenticate
+// will not be fixed by retrying, other errors such as the machine being down might be.
+func dialErr(endpoint string, err error) error {
+	if strings.Contains(err.Error(), "unable to authenticate") {
+		return jobs.Fatalf("could not authenticate to %s: %s", endpoint, err)
+	}
+	return fmt.Errorf("could not connect to the agent on %s (retryable): %w", endpoint, err)
+}
+
+// agentErr converts an error returned by the agent on "endpoint" to a Job error.
+func agentErr(ctx context.Context, endpoint string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("agent on %s: %w", endpoint, ctx.Err())
+	}
+	switch status.Code(err) {
+	// These mean the request is wrong or can't be done, which retrying won't change.
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied,
+		codes.Unauthenticated, codes.Unimplemented, codes.FailedPrecondition, codes.OutOfRange:
+		return jobs.Fatalf("agent on %s: %s", endpoint, err)
+	}
+	// This includes errors that don't have a code, such as from systemd on the machine.
+	return fmt.Errorf("agent on %s (retryable): %w", endpoint, err)
+}
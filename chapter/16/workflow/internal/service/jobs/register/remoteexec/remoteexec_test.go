@@ -0,0 +1,151 @@
+package remoteexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	agentpb "github.com/PacktPublishing/Go-for-DevOps/chapter/8/agent/proto"
+)
+
+type fakeAgent struct {
+	err     error
+	install *agentpb.InstallReq
+	remove  *agentpb.RemoveReq
+	closed  bool
+}
+
+func (f *fakeAgent) Install(ctx context.Context, req *agentpb.InstallReq) (*agentpb.InstallResp, error) {
+	f.install = req
+	return &agentpb.InstallResp{}, f.err
+}
+
+func (f *fakeAgent) Remove(ctx context.Context, req *agentpb.RemoveReq) (*agentpb.RemoveResp, error) {
+	f.remove = req
+	return &agentpb.RemoveResp{}, f.err
+}
+
+func (f *fakeAgent) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	pkg := filepath.Join(t.TempDir(), "hello.zip")
+	if err := os.WriteFile(pkg, []byte("zip"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	signer = fakeSigner{}
+	defer func() { signer = nil }()
+
+	tests := []struct {
+		desc      string
+		action    string
+		dialErr   error
+		agentErr  error
+		wantErr   bool
+		wantFatal bool
+	}{
+		{desc: "Install", action: "install"},
+		{desc: "Remove", action: "remove"},
+		{
+			desc:    "Machine unreachable",
+			action:  "install",
+			dialErr: errors.New("ssh.Dial failed: dial tcp: connect: connection refused"),
+			wantErr: true,
+		},
+		{
+			desc:      "SSH authentication failed",
+			action:    "install",
+			dialErr:   errors.New("ssh.Dial failed: ssh: handshake failed: ssh: unable to authenticate"),
+			wantErr:   true,
+			wantFatal: true,
+		},
+		{
+			desc:      "Agent rejected the package",
+			action:    "install",
+			agentErr:  status.Error(codes.InvalidArgument, "binary not in package"),
+			wantErr:   true,
+			wantFatal: true,
+		},
+		{
+			desc:     "Agent unavailable",
+			action:   "remove",
+			agentErr: status.Error(codes.Unavailable, "transport is closing"),
+			wantErr:  true,
+		},
+		{
+			desc:     "Agent error without a code",
+			action:   "install",
+			agentErr: errors.New("systemd unit failed to start"),
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		fake := &fakeAgent{err: test.agentErr}
+		dial = func(endpoint string, auth []ssh.AuthMethod) (agent, error) {
+			if test.dialErr != nil {
+				return nil, test.dialErr
+			}
+			return fake, nil
+		}
+
+		j := &Job{args: args{endpoint: "aa01.aaa:22", action: test.action, name: "hello"}}
+		if test.action == "install" {
+			j.args.pkg = pkg
+			j.args.binary = "hello"
+			j.args.args = []string{"-v"}
+		}
+		out := &bytes.Buffer{}
+
+		err := j.Run(jobs.WithOutput(context.Background(), out))
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestRun(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestRun(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if jobs.IsFatal(err) != test.wantFatal {
+				t.Errorf("TestRun(%s): got IsFatal() == %v, want %v", test.desc, jobs.IsFatal(err), test.wantFatal)
+			}
+			if retryable := strings.Contains(err.Error(), "(retryable)"); retryable == test.wantFatal {
+				t.Errorf("TestRun(%s): got retryable == %v, want %v: %s", test.desc, retryable, !test.wantFatal, err)
+			}
+		}
+
+		if !strings.Contains(out.String(), "connecting to the agent on aa01.aaa:22") {
+			t.Errorf("TestRun(%s): output did not record connecting, got:\n%s", test.desc, out)
+		}
+		if test.dialErr != nil {
+			continue
+		}
+		if !fake.closed {
+			t.Errorf("TestRun(%s): connection to the agent was not closed", test.desc)
+		}
+		switch test.action {
+		case "install":
+			if fake.install == nil || fake.install.Binary != "hello" || string(fake.install.Package) != "zip" {
+				t.Errorf("TestRun(%s): got InstallReq %v, want the package and binary", test.desc, fake.install)
+			}
+		case "remove":
+			if fake.remove == nil || fake.remove.Name != "hello" {
+				t.Errorf("TestRun(%s): got RemoveReq %v, want Name == hello", test.desc, fake.remove)
+			}
+		}
+	}
+}
+
+// fakeSigner lets Run() get SSH credentials without a key or an SSH agent.
+type fakeSigner struct{ ssh.Signer }
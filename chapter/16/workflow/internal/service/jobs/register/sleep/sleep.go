@@ -16,7 +16,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
 
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
@@ -24,50 +23,36 @@ import (
 
 // This registers our Job on server startup.
 func init() {
-	jobs.Register("sleep", newJob())
+	jobs.Register("sleep", newJob)
+}
+
+var schema = jobs.Schema{
+	Desc: "Sleeps for a number of seconds",
+	Args: []jobs.Arg{
+		{Name: "seconds", Desc: "The number of seconds to sleep, at least 1", Type: pb.ArgType_ArgInt, Required: true},
+	},
 }
 
 type args struct {
 	d time.Duration
 }
 
+// validate parses "args", which have been checked against our schema.
 func (a *args) validate(args map[string]string) error {
-	must := map[string]bool{
-		"seconds": false,
-	}
-
-	for k, v := range args {
-		switch k {
-		case "seconds":
-			i, err := strconv.Atoi(v)
-			if err != nil {
-				return fmt.Errorf("arg(seconds) is not an integer(%s)", v)
-			}
-			if i < 1 {
-				return fmt.Errorf("arg(seconds) cannot be less than 1(%d)", i)
-			}
-			must["seconds"] = true
-			a.d = time.Duration(i) * time.Second
-		default:
-			return fmt.Errorf("validateDecom had invalid arg(%s)", k)
-		}
-	}
-
-	for k, v := range must {
-		if !v {
-			return fmt.Errorf("missing required arg(%s)", k)
-		}
+	i, _ := strconv.Atoi(args["seconds"])
+	if i < 1 {
+		return fmt.Errorf("arg(seconds) cannot be less than 1(%d)", i)
 	}
+	a.d = time.Duration(i) * time.Second
 	return nil
 }
 
 // Job implements jobs.Job.
 type Job struct {
-	sites map[string]sites.Site
-	args  args
+	args args
 }
 
-func newJob() *Job {
+func newJob() jobs.Job {
 	return &Job{}
 }
 
@@ -81,8 +66,25 @@ func (j *Job) Validate(job *pb.Job) error {
 	return nil
 }
 
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
 // Run implements jobs.Job.Run().
-func (j *Job) Run(ctx context.Context, job *pb.Job) error {
-	time.Sleep(j.args.d)
-	return nil
+func (j *Job) Run(ctx context.Context) error {
+	timer := time.NewTimer(j.args.d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Plan implements jobs.Planner.Plan().
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	return jobs.Plan{Action: fmt.Sprintf("sleep for %v", j.args.d)}, nil
 }
@@ -1,6 +1,8 @@
 /*
 Package tokenbucket registers a job that is used to fetch a token from a token bucket.
 
+Buckets are defined in the server's bucket config file, see the buckets package.
+
 Register name: "tokenBucket"
 Args:
 	"bucket"(mandatory): The name of the bucket
@@ -12,27 +14,34 @@ package tokenbucket
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
-	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token/buckets"
 
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
-var buckets = map[string]*token.Bucket{}
-
 // This registers our Job on server startup.
 func init() {
-	jobs.Register("tokenBucket", newJob())
+	jobs.Register("tokenBucket", newJob)
+}
 
-	satDiskErase, err := token.New(1, 1, 30*time.Minute)
-	if err != nil {
-		panic(err)
-	}
-	buckets["diskEraseSatellite"] = satDiskErase
+var schema = jobs.Schema{
+	Desc: "Gets a token from a token bucket",
+	Args: []jobs.Arg{
+		{Name: "bucket", Desc: "The name of the bucket", Required: true},
+		{
+			Name:     "fatal",
+			Desc:     "true if a failure should cause a fatal error, false if it should block until it gets one",
+			Type:     pb.ArgType_ArgBool,
+			Required: true,
+			Allowed:  []string{"true", "false"},
+		},
+	},
 }
 
 type args struct {
@@ -40,51 +49,22 @@ type args struct {
 	fatal  bool
 }
 
+// validate parses "args", which have been checked against our schema.
 func (a *args) validate(args map[string]string) error {
-	must := map[string]bool{
-		"bucket": false,
-		"fatal":  false,
-	}
-
-	for k, v := range args {
-		switch k {
-		case "bucket":
-			if _, ok := buckets[v]; !ok {
-				return fmt.Errorf("bucket(%s) was not a valid", v)
-			}
-			must["bucket"] = true
-			a.bucket = v
-		case "fatal":
-			switch v {
-			case "true":
-				a.fatal = true
-			case "false":
-				a.fatal = true
-			default:
-				return fmt.Errorf("arg(fatal) was not true or false, was %q", v)
-			}
-			must["fatal"] = true
-		default:
-			return fmt.Errorf("invalid arg(%s)", k)
-		}
-	}
-
-	for k, v := range must {
-		if !v {
-			return fmt.Errorf("missing required arg(%s)", k)
-		}
+	a.bucket = args["bucket"]
+	a.fatal = args["fatal"] == "true"
+	if _, ok := buckets.Data.Info(a.bucket); !ok {
+		return fmt.Errorf("bucket(%s) was not a valid", a.bucket)
 	}
-
 	return nil
 }
 
 // Job implements jobs.Job.
 type Job struct {
-	sites map[string]sites.Site
-	args  args
+	args args
 }
 
-func newJob() *Job {
+func newJob() jobs.Job {
 	return &Job{}
 }
 
@@ -98,18 +78,47 @@ func (j *Job) Validate(job *pb.Job) error {
 	return nil
 }
 
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
 // Run implements jobs.Job.Run().
-func (j *Job) Run(ctx context.Context, job *pb.Job) error {
+func (j *Job) Run(ctx context.Context) error {
 	if j.args.fatal {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, 1*time.Second)
 		defer cancel()
 	}
-	if err := buckets[j.args.bucket].Token(ctx); err != nil {
+	start := time.Now()
+	if err := buckets.Data.Token(ctx, j.args.bucket); err != nil {
+		metrics.TokenWait.WithLabelValues(j.args.bucket, metrics.ResultFailed).Observe(time.Since(start).Seconds())
+		if errors.Is(err, buckets.ErrNotFound) {
+			return jobs.Fatalf("bucket(%s) was removed from the bucket config", j.args.bucket)
+		}
 		if j.args.fatal {
 			return jobs.Fatalf("token(%s) not available", j.args.bucket)
 		}
 		return jobs.Fatalf("workflow cancelled before token(%s) was available", j.args.bucket)
 	}
+	metrics.TokenWait.WithLabelValues(j.args.bucket, metrics.ResultSuccess).Observe(time.Since(start).Seconds())
 	return nil
 }
+
+// Plan implements jobs.Planner.Plan(). If no tokens are available now and "fatal" is set,
+// this returns an error, as Run() would fail.
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	plan := jobs.Plan{Action: fmt.Sprintf("get a token from bucket(%s)", j.args.bucket)}
+	info, ok := buckets.Data.Info(j.args.bucket)
+	if !ok {
+		return plan, fmt.Errorf("bucket(%s) does not exist", j.args.bucket)
+	}
+	if info.Available > 0 {
+		return plan, nil
+	}
+	if j.args.fatal {
+		return plan, fmt.Errorf("token(%s) not available", j.args.bucket)
+	}
+	plan.Action += fmt.Sprintf(", no tokens are available now so it would wait for one at %s", info.NextRefill().Format(time.RFC3339))
+	return plan, nil
+}
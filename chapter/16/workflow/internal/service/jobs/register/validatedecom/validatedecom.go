@@ -4,7 +4,7 @@ Package validatedecom registers a job that is used to validate a site is set to
 Register name: "validateDecom"
 Args:
 	"site"(mandatory): The name of the site, like "aaa" or "aba"
-	"siteType"(mandatory): The type of the site, like "satellite" or "cluster"
+	"type"(mandatory): The type of the site, "satellite" or "cluster"
 Result:
 	If the site is not in decom, will return a fatal error.
 */
@@ -22,7 +22,15 @@ import (
 
 // This registers our Job on server startup.
 func init() {
-	jobs.Register("validateDecom", newJob(sites.Data.Sites))
+	jobs.Register("validateDecom", newJob)
+}
+
+var schema = jobs.Schema{
+	Desc: `Validates a site is in the "decom" state`,
+	Args: []jobs.Arg{
+		{Name: "site", Desc: `The name of the site, like "aaa" or "aba"`, Required: true},
+		{Name: "type", Desc: "The type of the site", Required: true, Allowed: []string{"satellite", "cluster"}},
+	},
 }
 
 type args struct {
@@ -30,29 +38,14 @@ type args struct {
 	siteType string
 }
 
+// validate parses "args", which have been checked against our schema.
 func (a *args) validate(args map[string]string) error {
-	must := map[string]bool{
-		"site": false,
-		"type": false,
-	}
-	var siteData sites.Site
-
-	for k, v := range args {
-		switch k {
-		case "site":
-			s, ok := sites.Data.Sites[v]
-			if !ok {
-				return fmt.Errorf("site(%s) was not a valid site", v)
-			}
-			must["site"] = true
-			a.site = v
-			siteData = s
-		case "type":
-			must["type"] = true
-			a.siteType = v
-		default:
-			return fmt.Errorf("invalid arg(%s)", k)
-		}
+	a.site = args["site"]
+	a.siteType = args["type"]
+
+	siteData, ok := sites.Data.Sites[a.site]
+	if !ok {
+		return fmt.Errorf("site(%s) was not a valid site", a.site)
 	}
 
 	if siteData.Type != a.siteType {
@@ -62,24 +55,16 @@ func (a *args) validate(args map[string]string) error {
 	if siteData.Status != "decom" {
 		return fmt.Errorf("site(%s) is not in the decom state, was in %q", a.site, siteData.Status)
 	}
-
-	for k, v := range must {
-		if !v {
-			return fmt.Errorf("missing required arg(%s)", k)
-		}
-	}
-
 	return nil
 }
 
 // Job implements jobs.Job.
 type Job struct {
-	sites map[string]sites.Site
-	args  args
+	args args
 }
 
-func newJob(sites map[string]sites.Site) *Job {
-	return &Job{sites: sites}
+func newJob() jobs.Job {
+	return &Job{}
 }
 
 // Validate implements jobs.Job.Validate().
@@ -92,8 +77,13 @@ func (j *Job) Validate(job *pb.Job) error {
 	return nil
 }
 
+// Describe implements jobs.Describer.Describe().
+func (j *Job) Describe() jobs.Schema {
+	return schema
+}
+
 // Run implements jobs.Job.Run().
-func (j *Job) Run(ctx context.Context, job *pb.Job) error {
+func (j *Job) Run(ctx context.Context) error {
 	site, ok := sites.Data.Sites[j.args.site]
 	if !ok {
 		return jobs.Fatalf("site(%s) is no longer in the sites file", j.args.site)
@@ -104,3 +94,16 @@ func (j *Job) Run(ctx context.Context, job *pb.Job) error {
 	}
 	return nil
 }
+
+// Plan implements jobs.Planner.Plan().
+func (j *Job) Plan(ctx context.Context) (jobs.Plan, error) {
+	return jobs.Plan{
+		Action: fmt.Sprintf("validate site(%s) is a %s in the decom state", j.args.site, j.args.siteType),
+		Sites:  j.Targets().Sites,
+	}, nil
+}
+
+// Targets implements jobs.Targeter.Targets().
+func (j *Job) Targets() jobs.Targets {
+	return jobs.Targets{Sites: []string{j.args.site}}
+}
@@ -0,0 +1,201 @@
+package jobs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Arg describes an argument a Job takes.
+type Arg struct {
+	// Name is the name of the arg. If Prefix is set, this is a prefix like "header.".
+	Name string
+	// Desc describes the arg.
+	Desc string
+	// Type is the type of the arg. Only ArgString, ArgInt, ArgBool and ArgDuration can be used.
+	Type pb.ArgType
+	// Required indicates the arg must be set.
+	Required bool
+	// Allowed, if set, are the only values the arg can have.
+	Allowed []string
+	// Default is the value the Job uses if the arg is not set. This is only a description,
+	// the Job must use it itself.
+	Default string
+	// Prefix indicates that any arg whose name starts with Name and has something after it,
+	// like "header.Content-Type", is this arg.
+	Prefix bool
+}
+
+// match returns true if the arg called "name" is "a".
+func (a Arg) match(name string) bool {
+	if a.Prefix {
+		return strings.HasPrefix(name, a.Name) && len(name) > len(a.Name)
+	}
+	return name == a.Name
+}
+
+// check checks that "v" is valid for the arg called "name".
+func (a Arg) check(name, v string) error {
+	var err error
+	switch a.Type {
+	case pb.ArgType_ArgString:
+	case pb.ArgType_ArgInt:
+		_, err = strconv.Atoi(v)
+	case pb.ArgType_ArgBool:
+		_, err = strconv.ParseBool(v)
+	case pb.ArgType_ArgDuration:
+		_, err = time.ParseDuration(v)
+	default:
+		return fmt.Errorf("arg(%s) has a type(%v) Jobs cannot use", name, a.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("arg(%s) must be of type %s, was %q", name, argTypeName(a.Type), v)
+	}
+
+	if len(a.Allowed) == 0 {
+		return nil
+	}
+	for _, allow := range a.Allowed {
+		if v == allow {
+			return nil
+		}
+	}
+	return fmt.Errorf("arg(%s) must be one of %s, was %q", name, strings.Join(a.Allowed, ", "), v)
+}
+
+func argTypeName(t pb.ArgType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "Arg"))
+}
+
+// Schema describes what a Job does and the args it takes.
+type Schema struct {
+	// Desc describes what the Job does.
+	Desc string
+	// Args are the args the Job takes. A Job that implements Describer cannot be passed
+	// args that are not listed.
+	Args []Arg
+}
+
+// Describer is an optional interface that a Job can implement to describe itself and the args it
+// takes. The args of a Job that implements it are checked against its Schema by Validate() before
+// Job.Validate() is called, so the Job only needs to parse them and do any checks a Schema
+// cannot, such as that a site exists.
+type Describer interface {
+	// Describe returns the Job's Schema. It must not depend on the settings passed to Validate().
+	Describe() Schema
+}
+
+// CheckArgs checks "args" against the Args in "s". Every arg must be in the Schema, must be of
+// its Type and must be one of its Allowed values if it has them. Every Required arg must be set.
+func (s Schema) CheckArgs(args map[string]string) error {
+	// Check args in a stable order so the same args always return the same error.
+	names := make([]string, 0, len(args))
+	for k := range args {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, k := range names {
+		a, ok := s.arg(k)
+		if !ok {
+			return fmt.Errorf("invalid arg(%s)", k)
+		}
+		if err := a.check(k, args[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range s.Args {
+		if !a.Required {
+			continue
+		}
+		if a.Prefix {
+			found := false
+			for _, k := range names {
+				if a.match(k) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("missing required arg(%s[name])", a.Name)
+			}
+			continue
+		}
+		if _, ok := args[a.Name]; !ok {
+			return fmt.Errorf("missing required arg(%s)", a.Name)
+		}
+	}
+	return nil
+}
+
+// arg returns the Arg for the arg called "name".
+func (s Schema) arg(name string) (Arg, bool) {
+	for _, a := range s.Args {
+		if a.match(name) {
+			return a, true
+		}
+	}
+	return Arg{}, false
+}
+
+// validate checks that the Schema itself is valid, which is done when a Job is registered.
+func (s Schema) validate() error {
+	seen := map[string]bool{}
+	for _, a := range s.Args {
+		if a.Name == "" {
+			return fmt.Errorf("has an Arg with an empty Name")
+		}
+		if seen[a.Name] {
+			return fmt.Errorf("has Arg(%s) twice", a.Name)
+		}
+		seen[a.Name] = true
+
+		switch a.Type {
+		case pb.ArgType_ArgString, pb.ArgType_ArgInt, pb.ArgType_ArgBool, pb.ArgType_ArgDuration:
+		default:
+			return fmt.Errorf("has Arg(%s) with a Type(%v) Jobs cannot use", a.Name, a.Type)
+		}
+		if a.Default != "" {
+			if err := a.check(a.Name, a.Default); err != nil {
+				return fmt.Errorf("has a bad Default: %s", err)
+			}
+		}
+		for _, v := range a.Allowed {
+			if err := a.check(a.Name, v); err != nil {
+				return fmt.Errorf("has a bad Allowed value: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Describe returns the Schema for the Job called "name". "ok" is false if the Job does not
+// implement Describer.
+func Describe(name string) (s Schema, ok bool, err error) {
+	j, err := GetJob(name)
+	if err != nil {
+		return Schema{}, false, err
+	}
+	d, ok := j.(Describer)
+	if !ok {
+		return Schema{}, false, nil
+	}
+	return d.Describe(), true, nil
+}
+
+// Validate validates that "job" is valid for "j", which must be the Job registered for job.Name.
+// If "j" implements Describer, the args are checked against its Schema first. This should be used
+// instead of calling j.Validate() directly.
+func Validate(j Job, job *pb.Job) error {
+	if d, ok := j.(Describer); ok {
+		if err := d.Describe().CheckArgs(job.Args); err != nil {
+			return err
+		}
+	}
+	return j.Validate(job)
+}
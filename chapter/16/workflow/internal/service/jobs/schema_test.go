@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func TestCheckArgs(t *testing.T) {
+	s := Schema{
+		Args: []Arg{
+			{Name: "site", Required: true},
+			{Name: "count", Type: pb.ArgType_ArgInt},
+			{Name: "fatal", Type: pb.ArgType_ArgBool},
+			{Name: "wait", Type: pb.ArgType_ArgDuration},
+			{Name: "action", Allowed: []string{"install", "remove"}},
+			{Name: "header.", Prefix: true},
+		},
+	}
+
+	tests := []struct {
+		desc    string
+		args    map[string]string
+		wantErr bool
+	}{
+		{desc: "Only required", args: map[string]string{"site": "aap"}},
+		{
+			desc: "All args",
+			args: map[string]string{
+				"site":                "aap",
+				"count":               "2",
+				"fatal":               "true",
+				"wait":                "1m",
+				"action":              "remove",
+				"header.Content-Type": "application/json",
+			},
+		},
+		{desc: "Missing required", args: map[string]string{"count": "2"}, wantErr: true},
+		{desc: "Unknown arg", args: map[string]string{"site": "aap", "machine": "aa00"}, wantErr: true},
+		{desc: "Bad int", args: map[string]string{"site": "aap", "count": "two"}, wantErr: true},
+		{desc: "Bad bool", args: map[string]string{"site": "aap", "fatal": "maybe"}, wantErr: true},
+		{desc: "Bad duration", args: map[string]string{"site": "aap", "wait": "1"}, wantErr: true},
+		{desc: "Not allowed", args: map[string]string{"site": "aap", "action": "reboot"}, wantErr: true},
+		{desc: "Prefix without a name", args: map[string]string{"site": "aap", "header.": "a"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := s.CheckArgs(test.args)
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestCheckArgs(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestCheckArgs(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		schema  Schema
+		wantErr bool
+	}{
+		{desc: "Valid", schema: Schema{Args: []Arg{{Name: "a"}, {Name: "b", Type: pb.ArgType_ArgInt, Default: "1"}}}},
+		{desc: "Empty name", schema: Schema{Args: []Arg{{}}}, wantErr: true},
+		{desc: "Duplicate", schema: Schema{Args: []Arg{{Name: "a"}, {Name: "a"}}}, wantErr: true},
+		{desc: "Bad Default", schema: Schema{Args: []Arg{{Name: "a", Type: pb.ArgType_ArgInt, Default: "x"}}}, wantErr: true},
+		{desc: "Bad Allowed", schema: Schema{Args: []Arg{{Name: "a", Type: pb.ArgType_ArgBool, Allowed: []string{"yes"}}}}, wantErr: true},
+		{desc: "Policy only type", schema: Schema{Args: []Arg{{Name: "a", Type: pb.ArgType_ArgStringList}}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := test.schema.validate()
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestSchemaValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestSchemaValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+// describedJob is a Job that implements Describer.
+type describedJob struct {
+	validated bool
+}
+
+func (d *describedJob) Validate(job *pb.Job) error {
+	d.validated = true
+	return nil
+}
+
+func (d *describedJob) Run(ctx context.Context) error { return nil }
+
+func (d *describedJob) Describe() Schema {
+	return Schema{Args: []Arg{{Name: "site", Required: true}}}
+}
+
+func TestValidate(t *testing.T) {
+	j := &describedJob{}
+	if err := Validate(j, &pb.Job{Args: map[string]string{}}); err == nil {
+		t.Errorf("TestValidate(missing arg): got err == nil, want err != nil")
+	}
+	if j.validated {
+		t.Errorf("TestValidate(missing arg): Job.Validate() was called, want it only called when args match the Schema")
+	}
+
+	if err := Validate(j, &pb.Job{Args: map[string]string{"site": "aap"}}); err != nil {
+		t.Errorf("TestValidate(valid): got err == %s, want err == nil", err)
+	}
+	if !j.validated {
+		t.Errorf("TestValidate(valid): Job.Validate() was not called")
+	}
+}
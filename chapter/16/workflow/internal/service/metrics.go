@@ -0,0 +1,75 @@
+package service
+
+import (
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/executor"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// workMetrics records metrics from the status changes of a single workflow.
+// It is only used from executor.Work.OnTransition(), which is never called concurrently.
+type workMetrics struct {
+	req *pb.WorkReq
+
+	finished bool
+	blocks   map[int]time.Time
+	jobs     map[[2]int]time.Time
+}
+
+func newWorkMetrics(req *pb.WorkReq) *workMetrics {
+	return &workMetrics{
+		req:    req,
+		blocks: map[int]time.Time{},
+		jobs:   map[[2]int]time.Time{},
+	}
+}
+
+// transition records metrics for "t". Durations are from when a Block or Job started running
+// until it finished. Blocks and Jobs that never ran, such as Jobs that failed validation,
+// do not record a duration.
+func (m *workMetrics) transition(t executor.Transition) {
+	now := time.Now()
+	switch {
+	case t.Job >= 0:
+		k := [2]int{t.Block, t.Job}
+		if t.To == pb.Status_StatusRunning {
+			if _, ok := m.jobs[k]; !ok {
+				m.jobs[k] = now
+			}
+			return
+		}
+		start, ok := m.jobs[k]
+		if !ok || !t.To.Done() {
+			return
+		}
+		delete(m.jobs, k)
+		metrics.JobDuration.WithLabelValues(m.jobName(t.Block, t.Job), t.To.String()).Observe(now.Sub(start).Seconds())
+	case t.Block >= 0:
+		if t.To == pb.Status_StatusRunning {
+			m.blocks[t.Block] = now
+			return
+		}
+		start, ok := m.blocks[t.Block]
+		if !ok || !t.To.Done() {
+			return
+		}
+		delete(m.blocks, t.Block)
+		metrics.BlockDuration.WithLabelValues(m.req.Name, t.To.String()).Observe(now.Sub(start).Seconds())
+	default:
+		// The Work can go from failed to cancelled, but we only want to count it once.
+		if m.finished || !t.To.Done() {
+			return
+		}
+		m.finished = true
+		metrics.Workflows.WithLabelValues(m.req.Name, t.To.String()).Inc()
+	}
+}
+
+func (m *workMetrics) jobName(block, job int) string {
+	if block >= len(m.req.Blocks) || job >= len(m.req.Blocks[block].Jobs) {
+		return "unknown"
+	}
+	return m.req.Blocks[block].Jobs[job].Name
+}
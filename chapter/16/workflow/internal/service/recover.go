@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/audit"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/notify"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Recovery is the policy used for workflows that were executing when the server stopped.
+type Recovery string
+
+const (
+	// RecoverFail marks workflows that were interrupted by a restart as failed.
+	RecoverFail Recovery = "fail"
+	// RecoverResume resumes workflows that were interrupted by a restart, starting at the
+	// first Block that did not finish. Jobs that completed are not run again.
+	RecoverResume Recovery = "resume"
+)
+
+// interruptedMsg is the error recorded on workflows and Jobs that were interrupted by a restart.
+const interruptedMsg = "interrupted by restart"
+
+// Recover finds workflows in storage that were executing when the server stopped and
+// either resumes them or marks them failed, depending on the Recovery policy.
+// This must be called before the server begins serving requests.
+func (w *Workflow) Recover(policy Recovery) error {
+	switch policy {
+	case RecoverFail, RecoverResume:
+	default:
+		return fmt.Errorf("unknown Recovery policy(%s)", policy)
+	}
+
+	ctx := context.Background()
+
+	// WorkReqs that were never executed are also StatusNotStarted, we skip those below.
+	filter := storage.Filter{
+		Statuses: []pb.Status{pb.Status_StatusNotStarted, pb.Status_StatusRunning, pb.Status_StatusPaused},
+	}
+	entries, err := w.store.List(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("could not list storage: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, entry := range entries {
+		id := entry.ID
+
+		statusResp, err := w.store.GetStatus(ctx, id)
+		if err != nil {
+			// This WorkReq was never executed.
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			log.Printf("Workflow(%s) could not be recovered: %s", id, err)
+			continue
+		}
+		if statusResp.Status.Done() {
+			continue
+		}
+
+		if policy == RecoverResume {
+			workReq, err := w.store.GetWork(ctx, id)
+			if err == nil {
+				log.Printf("Workflow(%s) was interrupted by a restart, resuming", id)
+				w.run(id, workReq, statusResp)
+				continue
+			}
+			log.Printf("Workflow(%s) could not be resumed, marking failed: %s", id, err)
+		}
+
+		log.Printf("Workflow(%s) was interrupted by a restart, marking failed", id)
+		w.auditLog.Record(audit.Record{
+			ID:    id,
+			Kind:  audit.KindWork,
+			Error: interruptedMsg,
+			From:  statusResp.Status.String(),
+			To:    pb.Status_StatusFailed.String(),
+		})
+		failInterrupted(statusResp)
+		if err := w.store.PutStatus(ctx, id, statusResp); err != nil {
+			return fmt.Errorf("could not write Workflow(%s) status: %w", id, err)
+		}
+		w.notifier.Send(notify.NewSummary(id, statusResp))
+	}
+	return nil
+}
+
+// failInterrupted marks a StatusResp and any Blocks and Jobs that had not finished as failed
+// because they were interrupted by a restart.
+func failInterrupted(resp *pb.StatusResp) {
+	resp.Status = pb.Status_StatusFailed
+	resp.Error = interruptedMsg
+
+	for _, block := range resp.Blocks {
+		switch block.Status {
+		case pb.Status_StatusRunning, pb.Status_StatusPaused:
+		default:
+			continue
+		}
+		block.Status = pb.Status_StatusFailed
+		block.HasError = true
+		for _, job := range block.Jobs {
+			if job.Status != pb.Status_StatusRunning {
+				continue
+			}
+			job.Status = pb.Status_StatusFailed
+			job.Error = interruptedMsg
+		}
+	}
+}
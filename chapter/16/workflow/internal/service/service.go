@@ -3,22 +3,38 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/audit"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/notify"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/executor"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/templates"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/tracing"
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
@@ -26,42 +42,122 @@ import (
 type active struct {
 	work   *executor.Work
 	status atomic.Value // *pb.StatusResp
+
+	// mu protects watchers and done.
+	mu sync.Mutex
+	// watchers are channels that receive status updates from Watch() calls.
+	watchers map[chan *pb.StatusResp]bool
+	// done is set when the workflow has finished and watchers have been closed.
+	done bool
+}
+
+// setStatus records the latest status and sends it to all watchers.
+func (a *active) setStatus(status *pb.StatusResp) {
+	a.status.Store(status)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for ch := range a.watchers {
+		sendLatest(ch, status)
+	}
+}
+
+// watch returns a channel that receives the current status and every status update after that.
+// The channel is closed after the final status is sent. Call the returned func when you are no
+// longer interested in updates.
+func (a *active) watch() (chan *pb.StatusResp, func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ch := make(chan *pb.StatusResp, 1)
+	ch <- a.status.Load().(*pb.StatusResp)
+	if a.done {
+		close(ch)
+		return ch, func() {}
+	}
+
+	if a.watchers == nil {
+		a.watchers = map[chan *pb.StatusResp]bool{}
+	}
+	a.watchers[ch] = true
+
+	return ch, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.watchers, ch)
+	}
+}
+
+// close closes all watchers. Any watch() call after this will receive only the final status.
+func (a *active) close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.done = true
+	for ch := range a.watchers {
+		close(ch)
+		delete(a.watchers, ch)
+	}
+}
+
+// sendLatest sends status on ch. If ch is full, the older status is removed for the newer one.
+func sendLatest(ch chan *pb.StatusResp, status *pb.StatusResp) {
+	for {
+		select {
+		case ch <- status:
+			return
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
 }
 
 // Workflow implements our gRPC service.
 type Workflow struct {
-	// storageDir is where we store workflow information.
-	storageDir string
+	// store is where we store workflow information.
+	store storage.Data
+	// auditLog is where we record changes in the status of workflows.
+	auditLog *audit.Log
+	// notifier tells webhooks when workflows finish. It may be nil.
+	notifier *notify.Notifier
 
 	// mu protects active
 	mu sync.Mutex
-	// active tracks all active work that is occuring.
+	// active tracks all active work that is occuring. A nil entry is an ID that Exec() has
+	// reserved while it checks if the workflow can be executed.
 	active map[string]*active
 
 	// Required for gRPC to run, makes sure we have all the methods defined.
 	pb.UnimplementedWorkflowServer
 }
 
-// New creates a new Workflow service.
-func New(storageDir string) (*Workflow, error) {
-	stat, err := os.Stat(storageDir)
-	if err != nil {
-		return nil, fmt.Errorf("could not stat the workflow storage(%s): %w", storageDir, err)
+// Option is an optional argument to New().
+type Option func(w *Workflow)
+
+// WithNotifier sends a notify.Summary to "n" when a workflow finishes.
+func WithNotifier(n *notify.Notifier) Option {
+	return func(w *Workflow) {
+		w.notifier = n
 	}
-	if !stat.IsDir() {
-		return nil, fmt.Errorf("storageDir(%s) is not a directory", storageDir)
+}
+
+// New creates a new Workflow service. RPCs are not recorded in "auditLog", use its interceptors
+// with the gRPC server for that.
+func New(store storage.Data, auditLog *audit.Log, opts ...Option) (*Workflow, error) {
+	if store == nil {
+		return nil, fmt.Errorf("storage cannot be nil")
 	}
-	u := "ping_" + uuid.NewString()
-	p := filepath.Join(storageDir, u)
-	f, err := os.OpenFile(p, os.O_CREATE+os.O_RDWR, 0600)
-	if err != nil {
-		return nil, fmt.Errorf("could not open a file in storage(%s) for RDWR: %w", storageDir, err)
+	if auditLog == nil {
+		return nil, fmt.Errorf("audit log cannot be nil")
 	}
-	f.Close()
-	if err := os.Remove(p); err != nil {
-		return nil, fmt.Errorf("could not remove ping file(%s) in storage(%s)", p, storageDir)
+	w := &Workflow{store: store, auditLog: auditLog, active: map[string]*active{}}
+	for _, opt := range opts {
+		opt(w)
 	}
-	return &Workflow{storageDir: storageDir, active: map[string]*active{}}, nil
+	return w, nil
 }
 
 var submitRateLimit = make(chan struct{}, 10)
@@ -75,105 +171,327 @@ func (w *Workflow) Submit(ctx context.Context, req *pb.WorkReq) (*pb.WorkResp, e
 	}
 	defer func() { <-submitRateLimit }()
 
+	return w.submit(ctx, req)
+}
+
+// submit validates "req" and stores it for execution.
+func (w *Workflow) submit(ctx context.Context, req *pb.WorkReq) (*pb.WorkResp, error) {
 	esStatus := es.Data.Status(req.Name)
 	if esStatus != es.Go {
 		return nil, status.Errorf(codes.Aborted, "emergency stop for(%s) was %s", req.Name, esStatus)
 	}
 
-	validateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	// Record who submitted this, replacing anything the client sent.
+	req.Submitter = auth.Identity(ctx)
+	req.Approvals = nil
+	// Record our span so that the trace of the execution can link to it.
+	req.SubmitTrace = tracing.Traceparent(ctx)
+
+	validateCtx, cancel := context.WithTimeout(auth.WithOperation(ctx, auth.OpSubmit), 30*time.Second)
 	defer cancel()
 
 	if err := executor.Validate(validateCtx, req); err != nil {
 		if errors.Is(err, context.Canceled) {
 			return nil, status.Error(codes.DeadlineExceeded, err.Error())
 		}
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	var (
-		id string
-		p  string
-	)
+	var id string
 
-	// Loop until we get a unique ID that doesn't exist on the filesystem.
+	// Loop until we get a unique ID that doesn't exist in storage.
 	for {
 		u, err := uuid.NewUUID()
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "problem getting UUIDv1; %s", err.Error())
 		}
 		id = u.String()
-		p = filepath.Join(w.storageDir, id)
 
-		_, err = os.Stat(p) // Make sure this doesn't alreay exist.
-		if err != nil {
+		_, err = w.store.GetWork(ctx, id) // Make sure this doesn't alreay exist.
+		if errors.Is(err, storage.ErrNotFound) {
 			break
 		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "problem reading storage: %s", err)
+		}
 	}
 
-	resp := &pb.WorkResp{Id: id}
+	if err := w.store.PutWork(ctx, id, req); err != nil {
+		return nil, status.Errorf(codes.Internal, "problem writing request to storage: %s", err)
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("workflow.id", id))
 
-	b, err := proto.Marshal(req)
-	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "could not marshal the request: %s", err)
+	return &pb.WorkResp{Id: id}, nil
+}
+
+var submitTemplateRateLimit = make(chan struct{}, 10)
+
+// SubmitTemplate builds a WorkReq from a template and submits it like Submit().
+func (w *Workflow) SubmitTemplate(ctx context.Context, req *pb.SubmitTemplateReq) (*pb.WorkResp, error) {
+	select {
+	case submitTemplateRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
 	}
+	defer func() { <-submitTemplateRateLimit }()
 
-	f, err := os.OpenFile(p, os.O_CREATE+os.O_WRONLY, 0600)
+	wr, err := templates.Expand(ctx, req.Template, req.Params)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not open file in storageDir(%s): %s", w.storageDir, err)
+		if errors.Is(err, templates.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "template(%s) does not exist", req.Template)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "template(%s): %s", req.Template, err)
 	}
-	defer f.Close()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("workflow.template", req.Template))
 
-	if _, err := f.Write(b); err != nil {
-		return nil, status.Errorf(codes.Internal, "problem writing request to storage: %s", err)
+	return w.submit(ctx, wr)
+}
+
+var listTemplatesRateLimit = make(chan struct{}, 10)
+
+// ListTemplates lists the templates that are registered and the parameters they take.
+func (w *Workflow) ListTemplates(ctx context.Context, req *pb.ListTemplatesReq) (*pb.ListTemplatesResp, error) {
+	select {
+	case listTemplatesRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
 	}
+	defer func() { <-listTemplatesRateLimit }()
 
+	resp := &pb.ListTemplatesResp{}
+	for _, t := range templates.List() {
+		resp.Templates = append(resp.Templates, t.Info())
+	}
 	return resp, nil
 }
 
-var executeRateLimit = make(chan struct{}, 10)
+var listJobsRateLimit = make(chan struct{}, 10)
 
-// Exec requests that the system execute a submitted workflow.
-func (w *Workflow) Exec(ctx context.Context, req *pb.ExecReq) (*pb.ExecResp, error) {
+// ListJobs lists the Jobs that are registered and the args they take.
+func (w *Workflow) ListJobs(ctx context.Context, req *pb.ListJobsReq) (*pb.ListJobsResp, error) {
 	select {
-	case executeRateLimit <- struct{}{}:
+	case listJobsRateLimit <- struct{}{}:
 	default:
 		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
 	}
-	defer func() { <-executeRateLimit }()
+	defer func() { <-listJobsRateLimit }()
+
+	resp := &pb.ListJobsResp{}
+	for _, name := range jobs.List() {
+		js := &pb.JobSchema{Name: name}
+		s, ok, err := jobs.Describe(name)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not describe Job(%s): %s", name, err)
+		}
+		if ok {
+			js.Desc = s.Desc
+			for _, a := range s.Args {
+				js.Args = append(
+					js.Args,
+					&pb.ArgSchema{
+						Name:     a.Name,
+						Desc:     a.Desc,
+						Type:     a.Type,
+						Required: a.Required,
+						Allowed:  a.Allowed,
+						Default:  a.Default,
+						Prefix:   a.Prefix,
+					},
+				)
+			}
+		}
+		resp.Jobs = append(resp.Jobs, js)
+	}
+	return resp, nil
+}
+
+var listPoliciesRateLimit = make(chan struct{}, 10)
+
+// ListPolicies lists the policies that are registered and the settings they take.
+func (w *Workflow) ListPolicies(ctx context.Context, req *pb.ListPoliciesReq) (*pb.ListPoliciesResp, error) {
+	select {
+	case listPoliciesRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-listPoliciesRateLimit }()
+
+	resp := &pb.ListPoliciesResp{}
+	for _, name := range policy.List() {
+		ps := &pb.PolicySchema{Name: name}
+		s, ok, err := policy.Describe(name)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "could not describe policy(%s): %s", name, err)
+		}
+		if ok {
+			ps.Desc = s.Desc
+			for _, setting := range s.Settings {
+				ps.Settings = append(
+					ps.Settings,
+					&pb.ArgSchema{
+						Name:     setting.Name,
+						Desc:     setting.Desc,
+						Type:     setting.Type,
+						Required: setting.Required,
+						Allowed:  setting.Allowed,
+					},
+				)
+			}
+		}
+		resp.Policies = append(resp.Policies, ps)
+	}
+	return resp, nil
+}
 
-	p := filepath.Join(w.storageDir, req.Id)
-	statP := filepath.Join(w.storageDir, req.Id+"_status")
+var planRateLimit = make(chan struct{}, 10)
 
+// Plan reports what would happen if a workflow was submitted and executed now.
+func (w *Workflow) Plan(ctx context.Context, req *pb.WorkReq) (*pb.PlanResp, error) {
+	select {
+	case planRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-planRateLimit }()
+
+	req.Submitter = auth.Identity(ctx)
+	req.Approvals = nil
+
+	planCtx, cancel := context.WithTimeout(auth.WithOperation(ctx, auth.OpPlan), 30*time.Second)
+	defer cancel()
+
+	resp := executor.Plan(planCtx, req)
+	if planCtx.Err() != nil {
+		return nil, status.Error(codes.DeadlineExceeded, planCtx.Err().Error())
+	}
+	return resp, nil
+}
+
+var approveRateLimit = make(chan struct{}, 10)
+
+// Approve records the caller's approval of a submitted workflow.
+func (w *Workflow) Approve(ctx context.Context, req *pb.ApproveReq) (*pb.ApproveResp, error) {
+	select {
+	case approveRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-approveRateLimit }()
+
+	id := auth.Identity(ctx)
+	if id == "" {
+		return nil, status.Errorf(codes.PermissionDenied, "approvals require the caller to have an identity, the server must use mTLS")
+	}
+
+	if _, err := uuid.Parse(req.Id); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Id(%s) is not a valid value: %s", req.Id, err)
+	}
+
+	// This prevents an Exec() or another Approve() from happening while we check and record the approval.
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	_, ok := w.active[req.Id]
-	if ok {
-		return nil, status.Errorf(codes.AlreadyExists, "Workflow(%s) is already running", req.Id)
+	// An Exec() that has reserved the ID may have already read the approvals.
+	if _, ok := w.active[req.Id]; ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) already executing or executed", req.Id)
 	}
 
-	_, err := os.Stat(statP)
-	if err == nil {
-		return nil, status.Errorf(codes.AlreadyExists, "Workflow(%s) already executing or executed", req.Id)
+	workReq, err := w.store.GetWork(ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Workflow(%s) not found", req.Id)
+		}
+		return nil, status.Errorf(codes.Internal, "Workflow(%s) could not be read: %s", req.Id, err)
+	}
+	if workReq.Submitter == id {
+		return nil, status.Errorf(codes.PermissionDenied, "identity(%s) submitted Workflow(%s) and cannot approve it", id, req.Id)
 	}
 
+	_, err = w.store.GetStatus(ctx, req.Id)
+	switch {
+	case err == nil:
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) already executing or executed", req.Id)
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, status.Errorf(codes.Internal, "problem reading storage: %s", err)
+	}
+
+	approvals, err := w.store.GetApprovals(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Workflow(%s) approvals could not be read: %s", req.Id, err)
+	}
+	for _, a := range approvals {
+		if a.Approver == id {
+			return nil, status.Errorf(codes.AlreadyExists, "identity(%s) already approved Workflow(%s)", id, req.Id)
+		}
+	}
+
+	approval := &pb.Approval{Approver: id, Time: timestamppb.Now(), Comment: req.Comment}
+	if err := w.store.AddApproval(ctx, req.Id, approval); err != nil {
+		return nil, status.Errorf(codes.Internal, "problem writing approval to storage: %s", err)
+	}
+	log.Printf("Workflow(%s) was approved by %s", req.Id, id)
+
+	return &pb.ApproveResp{Approvals: append(approvals, approval)}, nil
+}
+
+var executeRateLimit = make(chan struct{}, 10)
+
+// Exec requests that the system execute a submitted workflow.
+func (w *Workflow) Exec(ctx context.Context, req *pb.ExecReq) (*pb.ExecResp, error) {
+	select {
+	case executeRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-executeRateLimit }()
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("workflow.id", req.Id))
+
 	u, err := uuid.Parse(req.Id)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Id(%s) is not a valid value: %s", req.Id, err)
 	}
 
+	// Reserve the ID so no other Exec() or Approve() can act on it. w.mu is not held while we
+	// read storage and run policies, as that can be slow and other RPCs need it.
+	w.mu.Lock()
+	if _, ok := w.active[req.Id]; ok {
+		w.mu.Unlock()
+		return nil, status.Errorf(codes.AlreadyExists, "Workflow(%s) is already running", req.Id)
+	}
+	w.active[req.Id] = nil
+	w.mu.Unlock()
+
+	started := false
+	defer func() {
+		if !started {
+			w.mu.Lock()
+			delete(w.active, req.Id)
+			w.mu.Unlock()
+		}
+	}()
+
+	_, err = w.store.GetStatus(ctx, req.Id)
+	switch {
+	case err == nil:
+		return nil, status.Errorf(codes.AlreadyExists, "Workflow(%s) already executing or executed", req.Id)
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, status.Errorf(codes.Internal, "problem reading storage: %s", err)
+	}
+
 	t := time.Unix(u.Time().UnixTime())
 	if time.Now().Sub(t) > 1*time.Hour {
 		return nil, status.Errorf(codes.FailedPrecondition, "Id(%s) is older than 1 hour and cannot be started", req.Id)
 	}
 
-	b, err := os.ReadFile(p)
+	workReq, err := w.store.GetWork(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "Workflow(%s) not found", req.Id)
-	}
-
-	workReq := &pb.WorkReq{}
-	if err := proto.Unmarshal(b, workReq); err != nil {
-		return nil, status.Errorf(codes.Internal, "Workflow(%s) could not be unmarshalled: %s", req.Id, err)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Workflow(%s) not found", req.Id)
+		}
+		return nil, status.Errorf(codes.Internal, "Workflow(%s) could not be read: %s", req.Id, err)
 	}
 
 	esStatus := es.Data.Status(workReq.Name)
@@ -181,52 +499,144 @@ func (w *Workflow) Exec(ctx context.Context, req *pb.ExecReq) (*pb.ExecResp, err
 		return nil, status.Errorf(codes.Aborted, "emergency stop for(%s) was %s", workReq.Name, esStatus)
 	}
 
-	// Write our status file to indicate we have started working on this.
-	statusResp := statusFromWork(workReq)
-	statusB, err := proto.Marshal(statusResp)
+	approvals, err := w.store.GetApprovals(ctx, req.Id)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "could not marshal the work's status proto: %s", err)
+		return nil, status.Errorf(codes.Internal, "Workflow(%s) approvals could not be read: %s", req.Id, err)
+	}
+	workReq.Approvals = approvals
+
+	// Policies are checked again, as they can restrict who may execute a workflow and
+	// require approvals.
+	policyCtx, cancel := context.WithTimeout(auth.WithOperation(ctx, auth.OpExec), 30*time.Second)
+	defer cancel()
+	if err := executor.RunPolicies(policyCtx, workReq); err != nil {
+		if errors.Is(err, auth.ErrPermissionDenied) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) does not pass policy: %s", req.Id, err)
 	}
 
-	if err := os.WriteFile(statP, statusB, 0600); err != nil {
+	// Write our status to indicate we have started working on this.
+	statusResp := statusFromWork(workReq)
+	statusResp.ExecutedBy = auth.Identity(ctx)
+	statusResp.Approvals = approvals
+	if err := w.store.PutStatus(ctx, req.Id, statusResp); err != nil {
 		return nil, status.Errorf(codes.Internal, "problem writing status to storage: %s", err)
 	}
 
+	// The trace of the execution is linked to this RPC's span.
+	var links []trace.Link
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		links = append(links, trace.Link{SpanContext: sc})
+	}
+	w.mu.Lock()
+	w.run(req.Id, workReq, statusResp, links...)
+	started = true
+	w.mu.Unlock()
+
+	return &pb.ExecResp{}, nil
+}
+
+var tracer = otel.Tracer("github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service")
+
+// run executes the WorkReq with "id" and records the status changes in memory and in storage.
+// The execution is a new trace that is linked to the Submit RPC and to "links".
+// w.mu must be held by the caller.
+func (w *Workflow) run(id string, workReq *pb.WorkReq, statusResp *pb.StatusResp, links ...trace.Link) {
+	if l, ok := tracing.Link(workReq.SubmitTrace); ok {
+		links = append(links, l)
+	}
+	ctx, span := tracer.Start(
+		context.Background(),
+		"workflow",
+		trace.WithLinks(links...),
+		trace.WithAttributes(
+			attribute.String("workflow.id", id),
+			attribute.String("workflow.name", workReq.Name),
+		),
+	)
+	if span.SpanContext().HasTraceID() {
+		statusResp.TraceId = span.SpanContext().TraceID().String()
+	}
+
 	work := executor.New(workReq, statusResp)
+	work.SetID(id)
+	wm := newWorkMetrics(workReq)
+	work.OnTransition(func(t executor.Transition) {
+		w.auditLog.Record(transitionRecord(id, t))
+		wm.transition(t)
+	})
 	active := &active{work: work}
 	active.status.Store(proto.Clone(statusResp).(*pb.StatusResp))
-	w.active[req.Id] = active
+	w.active[id] = active
+	metrics.ActiveWorkflows.WithLabelValues(workReq.Name).Inc()
 
 	// Run our work and get the first state change.
-	ch := work.Run(context.Background())
-	active.status.Store(<-ch)
-	writeIn := statusWriter(statP)
+	ch := work.Run(ctx)
+	last := <-ch
+	active.setStatus(last)
+	writeIn, written := w.statusWriter(id)
 
 	// Update our status as it changes in memory and on disk.
 	// Cleanup our list of active work when we are done.
 	go func() {
 		for status := range ch {
-			// Record our status in memory
-			active.status.Store(status)
+			// Record our status in memory and send it to any watchers.
+			active.setStatus(status)
 
-			// Record our status on disk. If there is an entry pending,
+			// Record our status in storage. If there is an entry pending,
 			// remove it for the latest entry.
-			select {
-			case writeIn <- status:
-			default:
-				select {
-				case <-writeIn:
-				default:
-				}
-				writeIn <- status
-			}
+			sendLatest(writeIn, status)
+			last = status
 		}
+		endWorkSpan(span, last)
+		w.notifier.Send(notify.NewSummary(id, last))
+
+		// Wait for our final status to be in storage before we stop being active, so
+		// anyone reading from storage after this sees the final status.
+		close(writeIn)
+		<-written
+		active.close()
+
 		w.mu.Lock()
-		delete(w.active, req.Id)
+		delete(w.active, id)
 		w.mu.Unlock()
+		metrics.ActiveWorkflows.WithLabelValues(workReq.Name).Dec()
 	}()
+}
 
-	return &pb.ExecResp{}, nil
+// endWorkSpan records the final status of a workflow on its span and ends it.
+func endWorkSpan(span trace.Span, status *pb.StatusResp) {
+	span.SetAttributes(
+		attribute.String("workflow.status", status.Status.String()),
+		attribute.Bool("workflow.es_stopped", status.WasEsStopped),
+	)
+	if status.Status != pb.Status_StatusCompleted {
+		span.SetStatus(otelcodes.Error, fmt.Sprintf("workflow finished with %v", status.Status))
+	}
+	span.End()
+}
+
+// transitionRecord converts a change in status of the workflow with "id" to an audit.Record.
+func transitionRecord(id string, t executor.Transition) audit.Record {
+	r := audit.Record{
+		ID:           id,
+		Kind:         audit.KindWork,
+		Error:        t.Err,
+		From:         t.From.String(),
+		To:           t.To.String(),
+		WasEsStopped: t.WasEsStopped,
+	}
+	switch {
+	case t.Job >= 0:
+		r.Kind = audit.KindJob
+		r.Block = t.Block
+		r.Job = t.Job
+	case t.Block >= 0:
+		r.Kind = audit.KindBlock
+		r.Block = t.Block
+	}
+	return r
 }
 
 var statusRateLimit = make(chan struct{}, 10)
@@ -247,24 +657,279 @@ func (w *Workflow) Status(ctx context.Context, req *pb.StatusReq) (*pb.StatusRes
 		return a.status.Load().(*pb.StatusResp), nil
 	}
 	// This ID is not currently running, so look in storage.
-	p := filepath.Join(w.storageDir, req.Id+"_status")
-	b, err := os.ReadFile(p)
+	resp, err := w.store.GetStatus(ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.InvalidArgument, "work ID(%s) was not found", req.Id)
+		}
+		return nil, status.Errorf(codes.Internal, "work ID(%s) could not be read from storage: %s", req.Id, err)
+	}
+	return resp, nil
+}
+
+var watchRateLimit = make(chan struct{}, 100)
+
+// Watch streams the status of a workflow until it finishes.
+func (w *Workflow) Watch(req *pb.StatusReq, stream pb.Workflow_WatchServer) error {
+	select {
+	case watchRateLimit <- struct{}{}:
+	default:
+		return status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-watchRateLimit }()
+
+	w.mu.Lock()
+	a := w.active[req.Id]
+	w.mu.Unlock()
+
+	// This ID is not currently running, so send what is in storage.
+	if a == nil {
+		resp, err := w.store.GetStatus(stream.Context(), req.Id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return status.Errorf(codes.NotFound, "work ID(%s) was not found", req.Id)
+			}
+			return status.Errorf(codes.Internal, "work ID(%s) could not be read from storage: %s", req.Id, err)
+		}
+		return stream.Send(resp)
+	}
+
+	ch, cancel := a.watch()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case resp, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+var controlRateLimit = make(chan struct{}, 10)
+
+// Cancel cancels a running workflow.
+func (w *Workflow) Cancel(ctx context.Context, req *pb.CancelReq) (*pb.CancelResp, error) {
+	select {
+	case controlRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-controlRateLimit }()
+
+	a, err := w.getActive(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.work.Cancel(); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) could not be cancelled: %s", req.Id, err)
+	}
+	log.Printf("Workflow(%s) was cancelled", req.Id)
+	return &pb.CancelResp{}, nil
+}
+
+// Pause pauses a running workflow.
+func (w *Workflow) Pause(ctx context.Context, req *pb.PauseReq) (*pb.PauseResp, error) {
+	select {
+	case controlRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-controlRateLimit }()
+
+	a, err := w.getActive(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.work.Pause(); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) could not be paused: %s", req.Id, err)
+	}
+	log.Printf("Workflow(%s) was paused", req.Id)
+	return &pb.PauseResp{}, nil
+}
+
+// Resume resumes a paused workflow.
+func (w *Workflow) Resume(ctx context.Context, req *pb.ResumeReq) (*pb.ResumeResp, error) {
+	select {
+	case controlRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-controlRateLimit }()
+
+	a, err := w.getActive(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.work.Resume(); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Workflow(%s) could not be resumed: %s", req.Id, err)
+	}
+	log.Printf("Workflow(%s) was resumed", req.Id)
+	return &pb.ResumeResp{}, nil
+}
+
+var listRateLimit = make(chan struct{}, 10)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// List lists the WorkReqs in storage that match the filters in the request.
+func (w *Workflow) List(ctx context.Context, req *pb.ListReq) (*pb.ListResp, error) {
+	select {
+	case listRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-listRateLimit }()
+
+	size := int(req.PageSize)
+	switch {
+	case size < 1:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	filter := storage.Filter{Name: req.Name, Statuses: req.Statuses}
+	if req.SubmittedAfter != nil {
+		filter.SubmittedAfter = req.SubmittedAfter.AsTime()
+	}
+	if req.SubmittedBefore != nil {
+		filter.SubmittedBefore = req.SubmittedBefore.AsTime()
+	}
+
+	var last storage.Entry
+	if req.PageToken != "" {
+		var err error
+		last, err = decodePageToken(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "page_token is invalid: %s", err)
+		}
+	}
+
+	entries, err := w.store.List(ctx, filter)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "work ID(%s) was not found", req.Id)
+		return nil, status.Errorf(codes.Internal, "problem reading storage: %s", err)
+	}
+
+	// Entries are sorted by submit time and ID, so skip everything up to and including
+	// the last entry of the previous page.
+	if req.PageToken != "" {
+		i := sort.Search(
+			len(entries),
+			func(i int) bool {
+				e := entries[i]
+				if e.Submitted.Equal(last.Submitted) {
+					return e.ID > last.ID
+				}
+				return e.Submitted.After(last.Submitted)
+			},
+		)
+		entries = entries[i:]
+	}
+
+	resp := &pb.ListResp{}
+	if len(entries) > size {
+		entries = entries[:size]
+		resp.NextPageToken = encodePageToken(entries[size-1])
 	}
-	resp := &pb.StatusResp{}
-	if err := proto.Unmarshal(b, resp); err != nil {
-		return nil, status.Errorf(codes.Internal, "work ID(%s) data was corrupted on disk", req.Id)
+	for _, e := range entries {
+		resp.Workflows = append(
+			resp.Workflows,
+			&pb.WorkflowSummary{
+				Id:        e.ID,
+				Name:      e.Name,
+				Desc:      e.Desc,
+				Submitted: timestamppb.New(e.Submitted),
+				Status:    e.Status,
+				HadErrors: e.HadErrors,
+				Submitter: e.Submitter,
+			},
+		)
 	}
 	return resp, nil
 }
 
+// encodePageToken encodes the position of "e" in a List() as an opaque page token.
+func encodePageToken(e storage.Entry) string {
+	s := strconv.FormatInt(e.Submitted.UnixNano(), 10) + "/" + e.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// decodePageToken decodes a page token from encodePageToken(). Only the Submitted and ID
+// fields of the returned Entry are set.
+func decodePageToken(token string) (storage.Entry, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return storage.Entry{}, err
+	}
+	sp := strings.SplitN(string(b), "/", 2)
+	if len(sp) != 2 {
+		return storage.Entry{}, fmt.Errorf("malformed token")
+	}
+	nano, err := strconv.ParseInt(sp[0], 10, 64)
+	if err != nil {
+		return storage.Entry{}, fmt.Errorf("malformed token")
+	}
+	return storage.Entry{ID: sp[1], Submitted: time.Unix(0, nano)}, nil
+}
+
+var auditRateLimit = make(chan struct{}, 10)
+
+// Audit returns the audit records for a workflow.
+func (w *Workflow) Audit(ctx context.Context, req *pb.AuditReq) (*pb.AuditResp, error) {
+	select {
+	case auditRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-auditRateLimit }()
+
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "id must be set")
+	}
+
+	records, err := w.auditLog.Query(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not read the audit log: %s", err)
+	}
+	resp := &pb.AuditResp{Records: make([]*pb.AuditRecord, 0, len(records))}
+	for _, r := range records {
+		resp.Records = append(resp.Records, r.Proto())
+	}
+	return resp, nil
+}
+
+// getActive returns the active entry for the workflow with "id".
+func (w *Workflow) getActive(id string) (*active, error) {
+	w.mu.Lock()
+	a := w.active[id]
+	w.mu.Unlock()
+
+	if a == nil {
+		return nil, status.Errorf(codes.NotFound, "Workflow(%s) is not running", id)
+	}
+	return a, nil
+}
+
 // statusFromWork takes a WorkReq and generates the corresponding StatusResp.
 func statusFromWork(req *pb.WorkReq) *pb.StatusResp {
 	resp := &pb.StatusResp{Name: req.Name, Desc: req.Desc, Status: pb.Status_StatusNotStarted}
 
 	for _, b := range req.Blocks {
 		sb := &pb.BlockStatus{
+			Id:     b.Id,
 			Desc:   b.Desc,
 			Status: pb.Status_StatusNotStarted,
 		}
@@ -282,21 +947,20 @@ func statusFromWork(req *pb.WorkReq) *pb.StatusResp {
 	return resp
 }
 
-func statusWriter(p string) (in chan *pb.StatusResp) {
+// statusWriter writes each status sent on "in" to storage for "id". "done" is closed
+// once "in" is closed and all writes have finished.
+func (w *Workflow) statusWriter(id string) (in chan *pb.StatusResp, done chan struct{}) {
 	in = make(chan *pb.StatusResp, 1)
+	done = make(chan struct{})
 
 	go func() {
+		defer close(done)
 		for status := range in {
-			b, err := proto.Marshal(status)
-			if err != nil {
-				log.Println("could not marshal a status proto: ", err)
-				continue
-			}
-			if err := os.WriteFile(p, b, 0600); err != nil {
-				log.Println("cannot write a status update to disk, this is bad: ", err)
+			if err := w.store.PutStatus(context.Background(), id, status); err != nil {
+				log.Println("cannot write a status update to storage, this is bad: ", err)
 				continue
 			}
 		}
 	}()
-	return in
+	return in, done
 }
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/audit"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/config"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/dir"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/requireapprovals"
+)
+
+// noopJob is a Job that does nothing.
+type noopJob struct{}
+
+func (noopJob) Validate(job *pb.Job) error    { return nil }
+func (noopJob) Run(ctx context.Context) error { return nil }
+
+// blockSettings are the Settings for blockPolicy.
+type blockSettings struct{}
+
+func (blockSettings) Validate() error { return nil }
+
+// blockPolicy is a Policy that blocks executions until "release" is closed. "entered" receives
+// a value when an execution starts being checked.
+type blockPolicy struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (b *blockPolicy) Run(ctx context.Context, req *pb.WorkReq, settings policy.Settings) error {
+	if auth.OperationFrom(ctx) != auth.OpExec {
+		return nil
+	}
+	b.entered <- struct{}{}
+	select {
+	case <-b.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// testBlock blocks the execution of "blocked" workflows. Tests set its release channel.
+var testBlock = &blockPolicy{entered: make(chan struct{}, 1)}
+
+func init() {
+	jobs.Register("testNoop", func() jobs.Job { return noopJob{} })
+	policy.Register("testBlock", testBlock, blockSettings{})
+}
+
+// testES is the es.json used by tests.
+const testES = `{"Name": "test", "Status": "go"}
+{"Name": "blocked", "Status": "go"}`
+
+// testPolicies is the policies.json used by tests. "test" workflows require one approval and
+// "blocked" workflows are blocked by testBlock.
+const testPolicies = `{"Name": "test", "Policies": [{"Name": "requireApprovals", "Settings": {"Count": 1}}]}
+{"Name": "blocked", "Policies": [{"Name": "testBlock", "Settings": {}}]}`
+
+func TestMain(m *testing.M) {
+	d, err := os.MkdirTemp("", "service")
+	if err != nil {
+		log.Fatal(err)
+	}
+	p := filepath.Join(d, "es.json")
+	if err := os.WriteFile(p, []byte(testES), 0600); err != nil {
+		log.Fatal(err)
+	}
+	es.Init(p)
+	p = filepath.Join(d, "policies.json")
+	if err := os.WriteFile(p, []byte(testPolicies), 0600); err != nil {
+		log.Fatal(err)
+	}
+	config.Init(p)
+
+	code := m.Run()
+	es.Data.Close()
+	config.Policies.Close()
+	os.RemoveAll(d)
+	os.Exit(code)
+}
+
+// newWorkflow returns a Workflow that stores WorkReqs in a dir.Data in "storeDir".
+func newWorkflow(t *testing.T, storeDir string) *Workflow {
+	t.Helper()
+
+	store, err := dir.New(storeDir)
+	if err != nil {
+		t.Fatalf("dir.New() had error: %s", err)
+	}
+	auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatalf("audit.New() had error: %s", err)
+	}
+	t.Cleanup(func() { auditLog.Close() })
+
+	w, err := New(store, auditLog)
+	if err != nil {
+		t.Fatalf("New() had error: %s", err)
+	}
+	return w
+}
+
+// testReq returns a WorkReq that passes validation.
+func testReq() *pb.WorkReq {
+	return &pb.WorkReq{
+		Name:   "test",
+		Desc:   "desc",
+		Blocks: []*pb.Block{{Jobs: []*pb.Job{{Name: "testNoop"}}}},
+	}
+}
+
+// as returns a Context with the caller's identity set to "id".
+func as(id string) context.Context {
+	return auth.WithIdentity(context.Background(), id)
+}
+
+// uuidAt returns a version 1 UUID with the time "t", like one created at that time.
+func uuidAt(t time.Time) string {
+	u := uuid.Must(uuid.NewUUID())
+	// Version 1 UUIDs hold the number of 100ns intervals since 1582-10-15.
+	ts := uint64(t.UnixNano()/100) + 0x01B21DD213814000
+	binary.BigEndian.PutUint32(u[0:], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:], uint16(ts>>48)&0x0fff|0x1000)
+	return u.String()
+}
+
+// waitDone waits until the workflow with "id" is no longer active.
+func waitDone(t *testing.T, w *Workflow, id string) {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		w.mu.Lock()
+		_, ok := w.active[id]
+		w.mu.Unlock()
+		if !ok {
+			return
+		}
+	}
+	t.Fatalf("Workflow(%s) did not finish", id)
+}
+
+// checkCode checks that "err" is a gRPC status with "want", or nil if want is codes.OK.
+func checkCode(t *testing.T, desc string, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("%s: got code %v (err == %v), want %v", desc, got, err, want)
+	}
+}
+
+func TestApprove(t *testing.T) {
+	w := newWorkflow(t, t.TempDir())
+
+	resp, err := w.Submit(as("alice"), testReq())
+	if err != nil {
+		t.Fatalf("TestApprove: Submit() had error: %s", err)
+	}
+	id := resp.Id
+
+	tests := []struct {
+		desc    string
+		caller  string
+		id      string
+		want    codes.Code
+		wantLen int
+	}{
+		{desc: "No identity", caller: "", id: id, want: codes.PermissionDenied},
+		{desc: "Path traversal ID", caller: "bob", id: "../../dev/null", want: codes.InvalidArgument},
+		{desc: "Not a UUID", caller: "bob", id: "id", want: codes.InvalidArgument},
+		{desc: "Unknown ID", caller: "bob", id: uuid.NewString(), want: codes.NotFound},
+		{desc: "Self approval", caller: "alice", id: id, want: codes.PermissionDenied},
+		{desc: "Approval", caller: "bob", id: id, want: codes.OK, wantLen: 1},
+		{desc: "Duplicate approval", caller: "bob", id: id, want: codes.AlreadyExists},
+		{desc: "Second approval", caller: "carol", id: id, want: codes.OK, wantLen: 2},
+	}
+
+	for _, test := range tests {
+		resp, err := w.Approve(as(test.caller), &pb.ApproveReq{Id: test.id, Comment: test.desc})
+		checkCode(t, "TestApprove("+test.desc+")", err, test.want)
+		if err != nil {
+			continue
+		}
+		if len(resp.Approvals) != test.wantLen {
+			t.Errorf("TestApprove(%s): got %d approvals, want %d", test.desc, len(resp.Approvals), test.wantLen)
+		}
+	}
+
+	approvals, err := w.store.GetApprovals(context.Background(), id)
+	if err != nil {
+		t.Fatalf("TestApprove: GetApprovals() had error: %s", err)
+	}
+	var got []string
+	for _, a := range approvals {
+		got = append(got, a.Approver)
+	}
+	if diff := pretty.Compare([]string{"bob", "carol"}, got); diff != "" {
+		t.Errorf("TestApprove: stored approvals: -want/+got:\n%s", diff)
+	}
+
+	// Once a workflow has been executed, approving it does nothing and is an error.
+	if _, err := w.Exec(as("alice"), &pb.ExecReq{Id: id}); err != nil {
+		t.Fatalf("TestApprove: Exec() had error: %s", err)
+	}
+	waitDone(t, w, id)
+	_, err = w.Approve(as("dave"), &pb.ApproveReq{Id: id})
+	checkCode(t, "TestApprove(After execution)", err, codes.FailedPrecondition)
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+	w := newWorkflow(t, t.TempDir())
+
+	// put stores a WorkReq with "id" that was approved by bob if "approved" is set.
+	put := func(id string, approved bool) {
+		t.Helper()
+		req := testReq()
+		req.Submitter = "alice"
+		if err := w.store.PutWork(ctx, id, req); err != nil {
+			t.Fatalf("TestExec: PutWork() had error: %s", err)
+		}
+		if approved {
+			if err := w.store.AddApproval(ctx, id, &pb.Approval{Approver: "bob"}); err != nil {
+				t.Fatalf("TestExec: AddApproval() had error: %s", err)
+			}
+		}
+	}
+
+	approved := uuidAt(time.Now())
+	put(approved, true)
+	unapproved := uuidAt(time.Now())
+	put(unapproved, false)
+	old := uuidAt(time.Now().Add(-2 * time.Hour))
+	put(old, true)
+	nearlyOld := uuidAt(time.Now().Add(-59 * time.Minute))
+	put(nearlyOld, true)
+
+	tests := []struct {
+		desc    string
+		id      string
+		want    codes.Code
+		wantErr string
+	}{
+		{desc: "Path traversal ID", id: "../../dev/null", want: codes.InvalidArgument},
+		{desc: "Not a UUID", id: "id", want: codes.InvalidArgument},
+		{desc: "Unknown ID", id: uuidAt(time.Now()), want: codes.NotFound},
+		{desc: "Older than 1 hour", id: old, want: codes.FailedPrecondition, wantErr: "older than 1 hour"},
+		{desc: "Not approved", id: unapproved, want: codes.FailedPrecondition, wantErr: "requires 1 approvals"},
+		{desc: "Approved", id: approved, want: codes.OK},
+		{desc: "Younger than 1 hour", id: nearlyOld, want: codes.OK},
+	}
+
+	for _, test := range tests {
+		_, err := w.Exec(as("alice"), &pb.ExecReq{Id: test.id})
+		checkCode(t, "TestExec("+test.desc+")", err, test.want)
+		if err != nil && !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("TestExec(%s): got err == %s, want it to contain %q", test.desc, err, test.wantErr)
+		}
+		if err == nil {
+			waitDone(t, w, test.id)
+		}
+	}
+
+	// A workflow can only be executed once.
+	_, err := w.Exec(as("alice"), &pb.ExecReq{Id: approved})
+	checkCode(t, "TestExec(Executed twice)", err, codes.AlreadyExists)
+
+	s, err := w.store.GetStatus(ctx, approved)
+	if err != nil {
+		t.Fatalf("TestExec: GetStatus() had error: %s", err)
+	}
+	if s.Status != pb.Status_StatusCompleted || s.ExecutedBy != "alice" {
+		t.Errorf("TestExec: got status %v executed by %q, want %v executed by alice", s.Status, s.ExecutedBy, pb.Status_StatusCompleted)
+	}
+}
+
+func TestExecDoesNotBlock(t *testing.T) {
+	ctx := context.Background()
+	w := newWorkflow(t, t.TempDir())
+
+	id := uuidAt(time.Now())
+	req := testReq()
+	req.Name = "blocked"
+	req.Submitter = "alice"
+	if err := w.store.PutWork(ctx, id, req); err != nil {
+		t.Fatalf("TestExecDoesNotBlock: PutWork() had error: %s", err)
+	}
+	running, err := w.Submit(as("alice"), testReq())
+	if err != nil {
+		t.Fatalf("TestExecDoesNotBlock: Submit() had error: %s", err)
+	}
+
+	testBlock.release = make(chan struct{})
+	execErr := make(chan error, 1)
+	go func() {
+		_, err := w.Exec(as("alice"), &pb.ExecReq{Id: id})
+		execErr <- err
+	}()
+	select {
+	case <-testBlock.entered:
+	case err := <-execErr:
+		t.Fatalf("TestExecDoesNotBlock: Exec() returned before checking policies: %v", err)
+	}
+
+	// While policies are being checked, other RPCs must not wait for them.
+	calls := make(chan struct{})
+	go func() {
+		defer close(calls)
+
+		_, err := w.Exec(as("alice"), &pb.ExecReq{Id: id})
+		checkCode(t, "TestExecDoesNotBlock(Exec of a reserved ID)", err, codes.AlreadyExists)
+		_, err = w.Approve(as("bob"), &pb.ApproveReq{Id: id})
+		checkCode(t, "TestExecDoesNotBlock(Approve of a reserved ID)", err, codes.FailedPrecondition)
+		_, err = w.Status(ctx, &pb.StatusReq{Id: id})
+		checkCode(t, "TestExecDoesNotBlock(Status of a reserved ID)", err, codes.InvalidArgument)
+		_, err = w.Approve(as("bob"), &pb.ApproveReq{Id: running.Id})
+		checkCode(t, "TestExecDoesNotBlock(Approve)", err, codes.OK)
+		_, err = w.Exec(as("alice"), &pb.ExecReq{Id: running.Id})
+		checkCode(t, "TestExecDoesNotBlock(Exec)", err, codes.OK)
+	}()
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestExecDoesNotBlock: other RPCs waited for Exec() to check policies")
+	}
+
+	close(testBlock.release)
+	if err := <-execErr; err != nil {
+		t.Fatalf("TestExecDoesNotBlock: Exec() had error: %s", err)
+	}
+	waitDone(t, w, id)
+	waitDone(t, w, running.Id)
+}
+
+func TestPlan(t *testing.T) {
+	w := newWorkflow(t, t.TempDir())
+
+	// "test" workflows require approvals, which a plan cannot have yet.
+	resp, err := w.Plan(as("alice"), testReq())
+	if err != nil {
+		t.Fatalf("TestPlan: Plan() had error: %s", err)
+	}
+	if !resp.Ok {
+		t.Errorf("TestPlan(Requires approvals): got Ok == false (errors %v), want Ok == true", resp.Errors)
+	}
+
+	req := testReq()
+	req.Blocks[0].Jobs[0].Name = "unknown"
+	resp, err = w.Plan(as("alice"), req)
+	if err != nil {
+		t.Fatalf("TestPlan: Plan() had error: %s", err)
+	}
+	if resp.Ok {
+		t.Errorf("TestPlan(Unknown Job): got Ok == true, want Ok == false")
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	storeDir := t.TempDir()
+	w := newWorkflow(t, storeDir)
+
+	// The dir storage uses the modification time of a WorkReq's file as its submit time, so we
+	// set them to have WorkReqs submitted at the same time on either side of page boundaries.
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	offsets := []int{0, 1, 1, 1, 2, 3, 3, 4, 5, 5}
+	type sub struct {
+		id   string
+		name string
+		at   time.Time
+	}
+	var subs []sub
+	for i, off := range offsets {
+		s := sub{id: uuid.NewString(), name: "test", at: base.Add(time.Duration(off) * time.Second)}
+		if i%3 == 0 {
+			s.name = "other"
+		}
+		req := testReq()
+		req.Name = s.name
+		if err := w.store.PutWork(ctx, s.id, req); err != nil {
+			t.Fatalf("TestList: PutWork() had error: %s", err)
+		}
+		if err := os.Chtimes(filepath.Join(storeDir, s.id), s.at, s.at); err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, s)
+	}
+	// The order List() must return them in.
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].at.Equal(subs[j].at) {
+			return subs[i].id < subs[j].id
+		}
+		return subs[i].at.Before(subs[j].at)
+	})
+
+	// list returns the IDs from every page of List() for "req" and the number of pages.
+	list := func(req *pb.ListReq) ([]string, int) {
+		t.Helper()
+		var ids []string
+		pages := 0
+		for {
+			resp, err := w.List(ctx, req)
+			if err != nil {
+				t.Fatalf("TestList: List() had error: %s", err)
+			}
+			pages++
+			for _, s := range resp.Workflows {
+				ids = append(ids, s.Id)
+			}
+			if resp.NextPageToken == "" {
+				return ids, pages
+			}
+			if len(resp.Workflows) != int(req.PageSize) {
+				t.Errorf("TestList: got a page with %d WorkReqs and a next page, want %d", len(resp.Workflows), req.PageSize)
+			}
+			req.PageToken = resp.NextPageToken
+			if pages > len(subs) {
+				t.Fatalf("TestList: List() never stopped returning pages")
+			}
+		}
+	}
+
+	var all, tests []string
+	for _, s := range subs {
+		all = append(all, s.id)
+		if s.name == "test" {
+			tests = append(tests, s.id)
+		}
+	}
+
+	for _, size := range []int32{1, 2, 3, 4, 9, 10, 11, 0} {
+		got, pages := list(&pb.ListReq{PageSize: size})
+		if diff := pretty.Compare(all, got); diff != "" {
+			t.Errorf("TestList(page size %d): -want/+got:\n%s", size, diff)
+		}
+		wantPages := 1
+		if size > 0 {
+			wantPages = (len(all) + int(size) - 1) / int(size)
+		}
+		if pages != wantPages {
+			t.Errorf("TestList(page size %d): got %d pages, want %d", size, pages, wantPages)
+		}
+
+		got, _ = list(&pb.ListReq{Name: "test", PageSize: size})
+		if diff := pretty.Compare(tests, got); diff != "" {
+			t.Errorf("TestList(page size %d, by name): -want/+got:\n%s", size, diff)
+		}
+	}
+
+	_, err := w.List(ctx, &pb.ListReq{PageToken: "!!"})
+	checkCode(t, "TestList(Bad page token)", err, codes.InvalidArgument)
+}
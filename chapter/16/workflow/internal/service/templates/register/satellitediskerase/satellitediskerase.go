@@ -0,0 +1,153 @@
+/*
+Package satellitediskerase provides a template that erases the disks of every machine in a
+satellite datacenter that is in the decom state.
+
+The workflow first validates the satellite is still being decommissioned and takes a token
+from the "diskEraseSatellite" token bucket. It then erases the machines in Blocks of
+"machinesPerBlock", all at the same time, waiting "wait" between Blocks.
+*/
+package satellitediskerase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/templates"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This registers our template with the service.
+func init() {
+	templates.Register(
+		templates.Template{
+			Name: "satelliteDiskErase",
+			Desc: "Erase all disks at a satellite datacenter in the decom state",
+			Params: []templates.Param{
+				{
+					Name:     "site",
+					Desc:     "The satellite datacenter to erase",
+					Type:     pb.ParamType_ParamString,
+					Required: true,
+				},
+				{
+					Name:    "machinesPerBlock",
+					Desc:    "How many machines to erase at the same time",
+					Type:    pb.ParamType_ParamInt,
+					Default: "5",
+				},
+				{
+					Name:    "wait",
+					Desc:    "How long to wait between each set of machines",
+					Type:    pb.ParamType_ParamDuration,
+					Default: "1m",
+				},
+			},
+			Build: build,
+		},
+	)
+}
+
+func build(ctx context.Context, params templates.Values) (*pb.WorkReq, error) {
+	sat := params.String("site")
+	perBlock := params.Int("machinesPerBlock")
+	wait := params.Duration("wait")
+
+	if perBlock < 1 {
+		return nil, fmt.Errorf("param(machinesPerBlock) must be at least 1, was %d", perBlock)
+	}
+	if wait != 0 && wait < time.Second {
+		return nil, fmt.Errorf("param(wait) must be 0 or at least 1s, was %v", wait)
+	}
+
+	site, ok := sites.Data.Sites[sat]
+	if !ok {
+		return nil, fmt.Errorf("there is no datacenter called %q", sat)
+	}
+	if site.Type != "satellite" {
+		return nil, fmt.Errorf("%q is not a satellite datacenter, it is a %s", sat, site.Type)
+	}
+	if site.Status != "decom" {
+		return nil, fmt.Errorf("%q is not in the decom state, was in %s", sat, site.Status)
+	}
+
+	wf := &pb.WorkReq{
+		Name: "SatelliteDiskErase",
+		Desc: "Erasing disks in datacenter satellite " + sat,
+	}
+
+	// Get a list of machines for the site, in alphabetical order.
+	machines := make([]sites.Machine, len(site.Machines))
+	copy(machines, site.Machines)
+	sort.Slice(machines, func(i, j int) bool { return machines[i].Name < machines[j].Name })
+
+	// This adds a top level block that validates the site is still in the decom state
+	// and gets a token from the token bucket to do a satellite erasure.
+	wf.Blocks = append(
+		wf.Blocks,
+		&pb.Block{
+			Desc: "Check pre-conditions",
+			Jobs: []*pb.Job{
+				{
+					Name: "validateDecom",
+					Desc: fmt.Sprintf("Validate satellite(%s) is in the decom state", sat),
+					Args: map[string]string{
+						"site": sat,
+						"type": "satellite",
+					},
+				},
+				{
+					Name: "tokenBucket",
+					Desc: "Get disk erase token, which limits how often we erase satellites",
+					Args: map[string]string{
+						"bucket": "diskEraseSatellite",
+						"fatal":  "true",
+					},
+				},
+			},
+		},
+	)
+
+	// Each Block erases "perBlock" machines at the same time and all but the last wait
+	// before the next Block starts.
+	for i := 0; i < len(machines); i += perBlock {
+		end := i + perBlock
+		if end > len(machines) {
+			end = len(machines)
+		}
+		block := &pb.Block{
+			Desc:      fmt.Sprintf("disk erase machines %s-%s", machines[i].Name, machines[end-1].Name),
+			RateLimit: int32(perBlock),
+		}
+		for _, m := range machines[i:end] {
+			block.Jobs = append(
+				block.Jobs,
+				&pb.Job{
+					Name: "diskErase",
+					Desc: fmt.Sprintf("Erase satellite(%s) machine(%s) disk", m.Site, m.Name),
+					Args: map[string]string{
+						"machine": m.Name,
+						"site":    m.Site,
+					},
+				},
+			)
+		}
+		if end < len(machines) && wait > 0 {
+			block.Jobs = append(
+				block.Jobs,
+				&pb.Job{
+					Name: "sleep",
+					Desc: fmt.Sprintf("Wait %v between disk erasures", wait),
+					Args: map[string]string{
+						"seconds": strconv.Itoa(int(wait / time.Second)),
+					},
+				},
+			)
+		}
+		wf.Blocks = append(wf.Blocks, block)
+	}
+	return wf, nil
+}
@@ -0,0 +1,85 @@
+package satellitediskerase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/templates"
+)
+
+func TestBuild(t *testing.T) {
+	sites.Data = sites.SiteData{
+		Sites: map[string]sites.Site{
+			"aap": {Name: "aap", Type: "satellite", Status: "decom"},
+			"aaq": {Name: "aaq", Type: "satellite", Status: "inService"},
+			"aar": {Name: "aar", Type: "cluster", Status: "decom"},
+		},
+	}
+	// Add machines out of order, so that we check they are sorted.
+	aap := sites.Data.Sites["aap"]
+	for _, i := range []int{6, 2, 0, 4, 1, 5, 3} {
+		aap.Machines = append(aap.Machines, sites.Machine{Name: fmt.Sprintf("aa0%d", i), Site: "aap"})
+	}
+	sites.Data.Sites["aap"] = aap
+
+	tests := []struct {
+		desc   string
+		params map[string]string
+		// wantBlocks are the descriptions of the Blocks after the pre-conditions.
+		wantBlocks []string
+		// wantJobs are the number of Jobs in each of those Blocks.
+		wantJobs []int
+		wantErr  bool
+	}{
+		{
+			desc:       "Defaults",
+			params:     map[string]string{"site": "aap"},
+			wantBlocks: []string{"disk erase machines aa00-aa04", "disk erase machines aa05-aa06"},
+			wantJobs:   []int{6, 2}, // The first Block has a sleep.
+		},
+		{
+			desc:       "No wait",
+			params:     map[string]string{"site": "aap", "machinesPerBlock": "3", "wait": "0s"},
+			wantBlocks: []string{"disk erase machines aa00-aa02", "disk erase machines aa03-aa05", "disk erase machines aa06-aa06"},
+			wantJobs:   []int{3, 3, 1},
+		},
+		{desc: "No site", params: map[string]string{}, wantErr: true},
+		{desc: "Unknown site", params: map[string]string{"site": "zzz"}, wantErr: true},
+		{desc: "Not decom", params: map[string]string{"site": "aaq"}, wantErr: true},
+		{desc: "Not satellite", params: map[string]string{"site": "aar"}, wantErr: true},
+		{desc: "Zero machinesPerBlock", params: map[string]string{"site": "aap", "machinesPerBlock": "0"}, wantErr: true},
+		{desc: "Short wait", params: map[string]string{"site": "aap", "wait": "10ms"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		req, err := templates.Expand(context.Background(), "satelliteDiskErase", test.params)
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestBuild(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestBuild(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		if req.Name != "SatelliteDiskErase" {
+			t.Errorf("TestBuild(%s): got Name %q, want SatelliteDiskErase", test.desc, req.Name)
+		}
+		if len(req.Blocks) != len(test.wantBlocks)+1 {
+			t.Errorf("TestBuild(%s): got %d Blocks, want %d", test.desc, len(req.Blocks), len(test.wantBlocks)+1)
+			continue
+		}
+		for i, b := range req.Blocks[1:] {
+			if b.Desc != test.wantBlocks[i] {
+				t.Errorf("TestBuild(%s): Block(%d): got Desc %q, want %q", test.desc, i+1, b.Desc, test.wantBlocks[i])
+			}
+			if len(b.Jobs) != test.wantJobs[i] {
+				t.Errorf("TestBuild(%s): Block(%d): got %d Jobs, want %d", test.desc, i+1, len(b.Jobs), test.wantJobs[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+/*
+Package templates defines workflow templates, which build a WorkReq on the server from typed
+parameters, and a registration system for registering them. This lets clients submit a
+workflow like "erase the disks at satellite aap" without each of them building the Blocks.
+
+Packages that contain templates can register themselves by doing:
+	func init() {
+		templates.Register(templates.Template{Name: "name", ...})
+	}
+If there is a duplicate name, this will panic.
+
+Building a WorkReq from a template is simply:
+	Expand(ctx, name, params)
+which checks the parameters against the template before calling its Build function.
+*/
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// ErrNotFound is returned when a template is not registered.
+var ErrNotFound = errors.New("template not found")
+
+var templates = map[string]Template{}
+
+// Param is a parameter a Template takes.
+type Param struct {
+	// Name is the name of the parameter.
+	Name string
+	// Desc describes the parameter.
+	Desc string
+	// Type is the type of the parameter. Values are checked against it before Build is called.
+	Type pb.ParamType
+	// Required indicates the parameter must be provided.
+	Required bool
+	// Default is the value used if the parameter is not provided. It must be valid for Type.
+	Default string
+}
+
+// check checks that "v" is valid for the Param's Type.
+func (p Param) check(v string) error {
+	var err error
+	switch p.Type {
+	case pb.ParamType_ParamString:
+	case pb.ParamType_ParamInt:
+		_, err = strconv.Atoi(v)
+	case pb.ParamType_ParamBool:
+		_, err = strconv.ParseBool(v)
+	case pb.ParamType_ParamDuration:
+		_, err = time.ParseDuration(v)
+	default:
+		return fmt.Errorf("param(%s) has unknown type(%v)", p.Name, p.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("param(%s) must be of type %s, was %q", p.Name, typeName(p.Type), v)
+	}
+	return nil
+}
+
+func typeName(t pb.ParamType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "Param"))
+}
+
+// Template builds a WorkReq from parameters.
+type Template struct {
+	// Name is the name of the template.
+	Name string
+	// Desc describes the workflow the template builds.
+	Desc string
+	// Params are the parameters the template takes.
+	Params []Param
+	// Build builds the WorkReq. "params" have been checked against Params and include
+	// the Default of any Param that was not provided. An error should say why the
+	// parameters cannot be used, such as a site that does not exist.
+	Build func(ctx context.Context, params Values) (*pb.WorkReq, error)
+}
+
+// Info returns the Template's description in a form that can be sent to clients.
+func (t Template) Info() *pb.TemplateInfo {
+	info := &pb.TemplateInfo{Name: t.Name, Desc: t.Desc}
+	for _, p := range t.Params {
+		info.Params = append(
+			info.Params,
+			&pb.TemplateParam{
+				Name:     p.Name,
+				Desc:     p.Desc,
+				Type:     p.Type,
+				Required: p.Required,
+				Default:  p.Default,
+			},
+		)
+	}
+	return info
+}
+
+// Register registers a Template so that it can be expanded.
+func Register(t Template) {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		panic("cannot Register a Template with an empty Name")
+	}
+	if t.Build == nil {
+		panic(fmt.Sprintf("cannot register Template(%s) with a nil Build", t.Name))
+	}
+	if _, ok := templates[t.Name]; ok {
+		panic(fmt.Sprintf("cannot register Template(%s) twice", t.Name))
+	}
+	seen := map[string]bool{}
+	for _, p := range t.Params {
+		if p.Name == "" {
+			panic(fmt.Sprintf("Template(%s) has a Param with an empty Name", t.Name))
+		}
+		if seen[p.Name] {
+			panic(fmt.Sprintf("Template(%s) has Param(%s) twice", t.Name, p.Name))
+		}
+		seen[p.Name] = true
+		if p.Default != "" {
+			if err := p.check(p.Default); err != nil {
+				panic(fmt.Sprintf("Template(%s) has a bad Default: %s", t.Name, err))
+			}
+		}
+	}
+	log.Println("Registered Template: ", t.Name)
+	templates[t.Name] = t
+}
+
+// Get returns a Template by its name from the registry.
+func Get(name string) (Template, error) {
+	t, ok := templates[name]
+	if !ok {
+		return Template{}, fmt.Errorf("Template(%s): %w", name, ErrNotFound)
+	}
+	return t, nil
+}
+
+// List returns all registered Templates sorted by name.
+func List() []Template {
+	l := make([]Template, 0, len(templates))
+	for _, t := range templates {
+		l = append(l, t)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l
+}
+
+// Expand builds a WorkReq from the Template called "name". It returns ErrNotFound if there is
+// no such Template.
+func Expand(ctx context.Context, name string, params map[string]string) (*pb.WorkReq, error) {
+	t, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	vals := Values{}
+	for _, p := range t.Params {
+		known[p.Name] = true
+		v, ok := params[p.Name]
+		if !ok || v == "" {
+			if p.Required {
+				return nil, fmt.Errorf("param(%s) is required", p.Name)
+			}
+			v = p.Default
+		} else if err := p.check(v); err != nil {
+			return nil, err
+		}
+		vals[p.Name] = v
+	}
+	for k := range params {
+		if !known[k] {
+			return nil, fmt.Errorf("Template(%s) does not have param(%s)", name, k)
+		}
+	}
+
+	req, err := t.Build(ctx, vals)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, fmt.Errorf("Template(%s) built a nil WorkReq", name)
+	}
+	return req, nil
+}
+
+// Values are the parameters passed to Template.Build(). As they have been checked against the
+// Template's Params, the methods that convert them to a type return the zero value instead of
+// an error if a value is not that type.
+type Values map[string]string
+
+// String returns the parameter called "name".
+func (v Values) String(name string) string {
+	return v[name]
+}
+
+// Int returns the parameter called "name" as an int.
+func (v Values) Int(name string) int {
+	i, _ := strconv.Atoi(v[name])
+	return i
+}
+
+// Bool returns the parameter called "name" as a bool.
+func (v Values) Bool(name string) bool {
+	b, _ := strconv.ParseBool(v[name])
+	return b
+}
+
+// Duration returns the parameter called "name" as a time.Duration.
+func (v Values) Duration(name string) time.Duration {
+	d, _ := time.ParseDuration(v[name])
+	return d
+}
@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+func init() {
+	Register(
+		Template{
+			Name: "testTemplate",
+			Params: []Param{
+				{Name: "site", Type: pb.ParamType_ParamString, Required: true},
+				{Name: "count", Type: pb.ParamType_ParamInt, Default: "5"},
+				{Name: "fast", Type: pb.ParamType_ParamBool},
+				{Name: "wait", Type: pb.ParamType_ParamDuration, Default: "1m"},
+			},
+			Build: func(ctx context.Context, params Values) (*pb.WorkReq, error) {
+				if params.Int("count") < 1 {
+					return nil, errors.New("count must be at least 1")
+				}
+				req := &pb.WorkReq{Name: params.String("site")}
+				for i := 0; i < params.Int("count"); i++ {
+					req.Blocks = append(req.Blocks, &pb.Block{})
+				}
+				if params.Bool("fast") {
+					req.Desc = "fast"
+				}
+				if params.Duration("wait") != time.Minute {
+					req.Desc += " wait"
+				}
+				return req, nil
+			},
+		},
+	)
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		desc       string
+		name       string
+		params     map[string]string
+		wantBlocks int
+		wantDesc   string
+		wantErr    bool
+		notFound   bool
+	}{
+		{desc: "Defaults", name: "testTemplate", params: map[string]string{"site": "aap"}, wantBlocks: 5},
+		{
+			desc:       "All params",
+			name:       "testTemplate",
+			params:     map[string]string{"site": "aap", "count": "2", "fast": "true", "wait": "30s"},
+			wantBlocks: 2,
+			wantDesc:   "fast wait",
+		},
+		{desc: "Missing required", name: "testTemplate", params: map[string]string{"count": "2"}, wantErr: true},
+		{desc: "Bad int", name: "testTemplate", params: map[string]string{"site": "aap", "count": "two"}, wantErr: true},
+		{desc: "Bad bool", name: "testTemplate", params: map[string]string{"site": "aap", "fast": "maybe"}, wantErr: true},
+		{desc: "Bad duration", name: "testTemplate", params: map[string]string{"site": "aap", "wait": "1"}, wantErr: true},
+		{desc: "Unknown param", name: "testTemplate", params: map[string]string{"site": "aap", "color": "red"}, wantErr: true},
+		{desc: "Build error", name: "testTemplate", params: map[string]string{"site": "aap", "count": "0"}, wantErr: true},
+		{desc: "No template", name: "nope", wantErr: true, notFound: true},
+	}
+
+	for _, test := range tests {
+		req, err := Expand(context.Background(), test.name, test.params)
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestExpand(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.wantErr:
+			t.Errorf("TestExpand(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			if errors.Is(err, ErrNotFound) != test.notFound {
+				t.Errorf("TestExpand(%s): got errors.Is(err, ErrNotFound) == %v, want %v", test.desc, !test.notFound, test.notFound)
+			}
+			continue
+		}
+
+		if len(req.Blocks) != test.wantBlocks {
+			t.Errorf("TestExpand(%s): got %d Blocks, want %d", test.desc, len(req.Blocks), test.wantBlocks)
+		}
+		if req.Desc != test.wantDesc {
+			t.Errorf("TestExpand(%s): got Desc %q, want %q", test.desc, req.Desc, test.wantDesc)
+		}
+	}
+}
+
+func TestInfo(t *testing.T) {
+	tmpl, err := Get("testTemplate")
+	if err != nil {
+		t.Fatalf("TestInfo: Get() had error: %s", err)
+	}
+	info := tmpl.Info()
+	if len(info.Params) != 4 {
+		t.Fatalf("TestInfo: got %d Params, want 4", len(info.Params))
+	}
+	p := info.Params[1]
+	if p.Name != "count" || p.Type != pb.ParamType_ParamInt || p.Default != "5" || p.Required {
+		t.Errorf("TestInfo: got Params[1] == %v, want count, ParamInt, Default 5, not Required", p)
+	}
+}
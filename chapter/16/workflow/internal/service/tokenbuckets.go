@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token/buckets"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// TokenBuckets implements our gRPC TokenBuckets admin service. It reads and changes buckets.Data.
+type TokenBuckets struct {
+	// admins are the identities allowed to call Set(). If empty, anyone can.
+	admins map[string]bool
+
+	// Required for gRPC to run, makes sure we have all the methods defined.
+	pb.UnimplementedTokenBucketsServer
+}
+
+// NewTokenBuckets creates a new TokenBuckets service. If "admins" is set, only those identities
+// can drain or refill buckets, which requires the server to use mTLS.
+func NewTokenBuckets(admins []string) *TokenBuckets {
+	t := &TokenBuckets{admins: map[string]bool{}}
+	for _, a := range admins {
+		t.admins[a] = true
+	}
+	return t
+}
+
+var tbSetRateLimit = make(chan struct{}, 10)
+
+// Set drains or refills a token bucket. The change is persisted to storage.
+func (t *TokenBuckets) Set(ctx context.Context, req *pb.TBSetReq) (*pb.TBSetResp, error) {
+	select {
+	case tbSetRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-tbSetRateLimit }()
+
+	id := auth.Identity(ctx)
+	if len(t.admins) > 0 && !t.admins[id] {
+		return nil, status.Errorf(codes.PermissionDenied, "identity(%s) cannot change token buckets", id)
+	}
+
+	var (
+		info buckets.Info
+		err  error
+	)
+	switch req.Action {
+	case pb.TBAction_TBDrain:
+		info, err = buckets.Data.Drain(req.Name)
+	case pb.TBAction_TBRefill:
+		info, err = buckets.Data.Refill(req.Name)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "action must be TBDrain or TBRefill, was %v", req.Action)
+	}
+	if err != nil {
+		if errors.Is(err, buckets.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "token bucket(%s) does not exist", req.Name)
+		}
+		return nil, status.Errorf(codes.Internal, "could not change token bucket(%s): %s", req.Name, err)
+	}
+	log.Printf("Token bucket(%s) %v by identity(%s), %d tokens available", req.Name, req.Action, id, info.Available)
+	return &pb.TBSetResp{Info: tbInfoToProto(info)}, nil
+}
+
+var tbListRateLimit = make(chan struct{}, 10)
+
+// List returns all token buckets.
+func (t *TokenBuckets) List(ctx context.Context, req *pb.TBListReq) (*pb.TBListResp, error) {
+	select {
+	case tbListRateLimit <- struct{}{}:
+	default:
+		return nil, status.Errorf(codes.ResourceExhausted, "too many requests")
+	}
+	defer func() { <-tbListRateLimit }()
+
+	resp := &pb.TBListResp{}
+	for _, info := range buckets.Data.List() {
+		resp.Infos = append(resp.Infos, tbInfoToProto(info))
+	}
+	return resp, nil
+}
+
+func tbInfoToProto(info buckets.Info) *pb.TBInfo {
+	return &pb.TBInfo{
+		Name:       info.Name,
+		Size:       int32(info.Size),
+		Incr:       int32(info.Incr),
+		Interval:   durationpb.New(time.Duration(info.Interval)),
+		Available:  int32(info.Available),
+		LastRefill: timestamppb.New(info.LastRefill),
+		NextRefill: timestamppb.New(info.NextRefill()),
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Package boltdb implements storage.Data using the embedded bbolt key/value database.
+
+WorkReqs, statuses, approvals and list entries are stored in separate buckets keyed by ID. The entries
+bucket holds a JSON summary of each WorkReq so that List() does not need to decode every
+WorkReq and status. The state of token buckets is stored in the tokenBuckets bucket keyed
+by name.
+*/
+package boltdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+var (
+	workBucket      = []byte("work")
+	statusBucket    = []byte("status")
+	entriesBucket   = []byte("entries")
+	approvalsBucket = []byte("approvals")
+	tokensBucket    = []byte("tokenBuckets")
+)
+
+// Data implements storage.Data.
+type Data struct {
+	db *bolt.DB
+}
+
+// New opens or creates the bbolt database file at "path".
+func New(path string) (*Data, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open bolt database(%s): %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{workBucket, statusBucket, entriesBucket, approvalsBucket, tokensBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not create buckets in bolt database(%s): %w", path, err)
+	}
+	return &Data{db: db}, nil
+}
+
+// PutWork implements storage.Data.PutWork().
+func (d *Data) PutWork(ctx context.Context, id string, req *pb.WorkReq) error {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not marshal the request: %w", err)
+	}
+	e, err := json.Marshal(storage.NewEntry(id, time.Now(), req, nil))
+	if err != nil {
+		return fmt.Errorf("could not marshal the entry: %w", err)
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		work := tx.Bucket(workBucket)
+		if work.Get([]byte(id)) != nil {
+			return fmt.Errorf("WorkReq(%s) already exists", id)
+		}
+		if err := work.Put([]byte(id), b); err != nil {
+			return err
+		}
+		return tx.Bucket(entriesBucket).Put([]byte(id), e)
+	})
+}
+
+// GetWork implements storage.Data.GetWork().
+func (d *Data) GetWork(ctx context.Context, id string) (*pb.WorkReq, error) {
+	req := &pb.WorkReq{}
+	if err := d.get(workBucket, id, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// PutStatus implements storage.Data.PutStatus().
+func (d *Data) PutStatus(ctx context.Context, id string, status *pb.StatusResp) error {
+	b, err := proto.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("could not marshal the status: %w", err)
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		eb := entries.Get([]byte(id))
+		if eb == nil {
+			return fmt.Errorf("WorkReq(%s): %w", id, storage.ErrNotFound)
+		}
+		e := storage.Entry{}
+		if err := json.Unmarshal(eb, &e); err != nil {
+			return fmt.Errorf("entry(%s) was corrupted in storage: %w", id, err)
+		}
+		e.Status = status.Status
+		e.HadErrors = status.HadErrors
+		eb, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("could not marshal the entry: %w", err)
+		}
+
+		if err := tx.Bucket(statusBucket).Put([]byte(id), b); err != nil {
+			return err
+		}
+		return entries.Put([]byte(id), eb)
+	})
+}
+
+// GetStatus implements storage.Data.GetStatus().
+func (d *Data) GetStatus(ctx context.Context, id string) (*pb.StatusResp, error) {
+	status := &pb.StatusResp{}
+	if err := d.get(statusBucket, id, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// List implements storage.Data.List().
+func (d *Data) List(ctx context.Context, filter storage.Filter) ([]storage.Entry, error) {
+	var entries []storage.Entry
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(k, v []byte) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			e := storage.Entry{}
+			if err := json.Unmarshal(v, &e); err != nil {
+				return fmt.Errorf("entry(%s) was corrupted in storage: %w", k, err)
+			}
+			if filter.Match(e) {
+				entries = append(entries, e)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	storage.Sort(entries)
+	return entries, nil
+}
+
+// AddApproval implements storage.Data.AddApproval().
+func (d *Data) AddApproval(ctx context.Context, id string, approval *pb.Approval) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(workBucket).Get([]byte(id)) == nil {
+			return storage.ErrNotFound
+		}
+		approvals := tx.Bucket(approvalsBucket)
+		// Get() returns memory owned by bolt, so we copy it before appending.
+		b := append([]byte{}, approvals.Get([]byte(id))...)
+		b, err := storage.AppendApproval(b, approval)
+		if err != nil {
+			return fmt.Errorf("could not marshal the approval: %w", err)
+		}
+		return approvals.Put([]byte(id), b)
+	})
+}
+
+// GetApprovals implements storage.Data.GetApprovals().
+func (d *Data) GetApprovals(ctx context.Context, id string) ([]*pb.Approval, error) {
+	var approvals []*pb.Approval
+	err := d.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(workBucket).Get([]byte(id)) == nil {
+			return storage.ErrNotFound
+		}
+		var err error
+		approvals, err = storage.DecodeApprovals(tx.Bucket(approvalsBucket).Get([]byte(id)))
+		if err != nil {
+			return fmt.Errorf("approvals for WorkReq(%s) were corrupted in storage: %w", id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// Delete implements storage.Data.Delete().
+func (d *Data) Delete(ctx context.Context, id string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{workBucket, statusBucket, entriesBucket, approvalsBucket} {
+			if err := tx.Bucket(b).Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutBucket implements storage.Data.PutBucket().
+func (d *Data) PutBucket(ctx context.Context, name string, state *pb.TBState) error {
+	if name == "" {
+		return fmt.Errorf("bucket name cannot be empty")
+	}
+	b, err := proto.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal the bucket state: %w", err)
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokensBucket).Put([]byte(name), b)
+	})
+}
+
+// GetBuckets implements storage.Data.GetBuckets().
+func (d *Data) GetBuckets(ctx context.Context) (map[string]*pb.TBState, error) {
+	states := map[string]*pb.TBState{}
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokensBucket).ForEach(func(k, v []byte) error {
+			state := &pb.TBState{}
+			if err := proto.Unmarshal(v, state); err != nil {
+				return fmt.Errorf("%s(%s) data was corrupted in storage: %w", tokensBucket, k, err)
+			}
+			states[string(k)] = state
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// Close implements storage.Data.Close().
+func (d *Data) Close() error {
+	return d.db.Close()
+}
+
+// get reads the value for "id" in "bucket" into the proto "m".
+func (d *Data) get(bucket []byte, id string, m proto.Message) error {
+	return d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket).Get([]byte(id))
+		if b == nil {
+			return storage.ErrNotFound
+		}
+		if err := proto.Unmarshal(b, m); err != nil {
+			return fmt.Errorf("%s(%s) data was corrupted in storage: %w", bucket, id, err)
+		}
+		return nil
+	})
+}
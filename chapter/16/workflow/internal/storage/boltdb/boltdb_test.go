@@ -0,0 +1,25 @@
+package boltdb
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/storagetest"
+)
+
+// This tests we implement the interface.
+var _ storage.Data = &Data{}
+
+func TestData(t *testing.T) {
+	storagetest.Run(
+		t,
+		func(t *testing.T) storage.Data {
+			d, err := New(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatalf("TestData: New() had error: %s", err)
+			}
+			return d
+		},
+	)
+}
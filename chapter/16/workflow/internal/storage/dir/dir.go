@@ -0,0 +1,315 @@
+/*
+Package dir implements storage.Data by storing WorkReqs and their statuses as files in a directory.
+
+A WorkReq is stored in a file named after its ID and its status is stored in a file named
+"[ID]_status". Each file is the binary encoded proto. Approvals are appended to a file named
+"[ID]_approvals". IDs must be UUIDs; any other files in the directory are ignored. The state
+of token buckets is stored in the "buckets" sub-directory in a file named after the bucket.
+
+This does not have any indexing, so List() must read every file in the directory.
+*/
+package dir
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+const (
+	statusSuffix    = "_status"
+	approvalsSuffix = "_approvals"
+	bucketsDir      = "buckets"
+)
+
+// Data implements storage.Data.
+type Data struct {
+	dir string
+}
+
+// New creates a new Data that stores files in "dir". "dir" must already exist and be writable.
+func New(dir string) (*Data, error) {
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not stat the workflow storage(%s): %w", dir, err)
+	}
+	if !stat.IsDir() {
+		return nil, fmt.Errorf("storage(%s) is not a directory", dir)
+	}
+	u := "ping_" + uuid.NewString()
+	p := filepath.Join(dir, u)
+	f, err := os.OpenFile(p, os.O_CREATE+os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open a file in storage(%s) for RDWR: %w", dir, err)
+	}
+	f.Close()
+	if err := os.Remove(p); err != nil {
+		return nil, fmt.Errorf("could not remove ping file(%s) in storage(%s)", p, dir)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, bucketsDir), 0700); err != nil {
+		return nil, fmt.Errorf("could not create the token bucket directory in storage(%s): %w", dir, err)
+	}
+	return &Data{dir: dir}, nil
+}
+
+// PutWork implements storage.Data.PutWork().
+func (d *Data) PutWork(ctx context.Context, id string, req *pb.WorkReq) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("id(%s) is not a UUID", id)
+	}
+
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not marshal the request: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(d.dir, id), os.O_CREATE+os.O_EXCL+os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open file in storage(%s): %w", d.dir, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(b); err != nil {
+		return fmt.Errorf("problem writing request to storage: %w", err)
+	}
+	return nil
+}
+
+// GetWork implements storage.Data.GetWork().
+func (d *Data) GetWork(ctx context.Context, id string) (*pb.WorkReq, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	req := &pb.WorkReq{}
+	if err := d.read(id, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// PutStatus implements storage.Data.PutStatus().
+func (d *Data) PutStatus(ctx context.Context, id string, status *pb.StatusResp) error {
+	if err := d.exists(id); err != nil {
+		return err
+	}
+
+	b, err := proto.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("could not marshal the status: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, id+statusSuffix), b, 0600); err != nil {
+		return fmt.Errorf("problem writing status to storage: %w", err)
+	}
+	return nil
+}
+
+// GetStatus implements storage.Data.GetStatus().
+func (d *Data) GetStatus(ctx context.Context, id string) (*pb.StatusResp, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+
+	status := &pb.StatusResp{}
+	if err := d.read(id+statusSuffix, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// List implements storage.Data.List().
+func (d *Data) List(ctx context.Context, filter storage.Filter) ([]storage.Entry, error) {
+	files, err := os.ReadDir(d.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read storage(%s): %w", d.dir, err)
+	}
+
+	var entries []storage.Entry
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if file.IsDir() || strings.HasSuffix(file.Name(), statusSuffix) || strings.HasSuffix(file.Name(), approvalsSuffix) {
+			continue
+		}
+		id := file.Name()
+		if _, err := uuid.Parse(id); err != nil {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			// The file was removed after we read the directory.
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("could not stat WorkReq(%s): %w", id, err)
+		}
+		req, err := d.GetWork(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("WorkReq(%s): %w", id, err)
+		}
+		status, err := d.GetStatus(ctx, id)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("WorkReq(%s) status: %w", id, err)
+		}
+
+		e := storage.NewEntry(id, info.ModTime(), req, status)
+		if filter.Match(e) {
+			entries = append(entries, e)
+		}
+	}
+	storage.Sort(entries)
+	return entries, nil
+}
+
+// AddApproval implements storage.Data.AddApproval().
+func (d *Data) AddApproval(ctx context.Context, id string, approval *pb.Approval) error {
+	if err := d.exists(id); err != nil {
+		return err
+	}
+
+	b, err := storage.AppendApproval(nil, approval)
+	if err != nil {
+		return fmt.Errorf("could not marshal the approval: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(d.dir, id+approvalsSuffix), os.O_CREATE+os.O_APPEND+os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open file in storage(%s): %w", d.dir, err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(b); err != nil {
+		return fmt.Errorf("problem writing approval to storage: %w", err)
+	}
+	return nil
+}
+
+// GetApprovals implements storage.Data.GetApprovals().
+func (d *Data) GetApprovals(ctx context.Context, id string) ([]*pb.Approval, error) {
+	if err := d.exists(id); err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(filepath.Join(d.dir, id+approvalsSuffix))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read approvals from storage: %w", err)
+	}
+	approvals, err := storage.DecodeApprovals(b)
+	if err != nil {
+		return nil, fmt.Errorf("approvals for WorkReq(%s) were corrupted in storage: %w", id, err)
+	}
+	return approvals, nil
+}
+
+// Delete implements storage.Data.Delete().
+func (d *Data) Delete(ctx context.Context, id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+
+	for _, p := range []string{id + approvalsSuffix, id + statusSuffix, id} {
+		if err := os.Remove(filepath.Join(d.dir, p)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("could not delete %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// PutBucket implements storage.Data.PutBucket().
+func (d *Data) PutBucket(ctx context.Context, name string, state *pb.TBState) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("bucket name(%s) cannot be used as a file name", name)
+	}
+	b, err := proto.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal the bucket state: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(d.dir, bucketsDir, name), b, 0600); err != nil {
+		return fmt.Errorf("problem writing bucket state to storage: %w", err)
+	}
+	return nil
+}
+
+// GetBuckets implements storage.Data.GetBuckets().
+func (d *Data) GetBuckets(ctx context.Context) (map[string]*pb.TBState, error) {
+	files, err := os.ReadDir(filepath.Join(d.dir, bucketsDir))
+	if err != nil {
+		return nil, fmt.Errorf("could not read the token bucket directory in storage(%s): %w", d.dir, err)
+	}
+
+	states := map[string]*pb.TBState{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		state := &pb.TBState{}
+		if err := d.read(filepath.Join(bucketsDir, file.Name()), state); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		states[file.Name()] = state
+	}
+	return states, nil
+}
+
+// Close implements storage.Data.Close().
+func (d *Data) Close() error {
+	return nil
+}
+
+// checkID returns storage.ErrNotFound if "id" is not a UUID. Every method that takes an ID
+// calls this before using it in a path, so an ID like "../x" cannot access other files.
+func checkID(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("id(%s) is not a UUID: %w", id, storage.ErrNotFound)
+	}
+	return nil
+}
+
+// exists returns storage.ErrNotFound if the WorkReq with "id" does not exist.
+func (d *Data) exists(id string) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(d.dir, id)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return storage.ErrNotFound
+		}
+		return fmt.Errorf("could not stat WorkReq(%s): %w", id, err)
+	}
+	return nil
+}
+
+// read reads the file with "name" into the proto "m".
+func (d *Data) read(name string, m proto.Message) error {
+	b, err := os.ReadFile(filepath.Join(d.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return storage.ErrNotFound
+		}
+		return fmt.Errorf("could not read %s from storage: %w", name, err)
+	}
+	if err := proto.Unmarshal(b, m); err != nil {
+		return fmt.Errorf("%s data was corrupted in storage: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dir
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/storagetest"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// This tests we implement the interface.
+var _ storage.Data = &Data{}
+
+func TestData(t *testing.T) {
+	storagetest.Run(
+		t,
+		func(t *testing.T) storage.Data {
+			d, err := New(t.TempDir())
+			if err != nil {
+				t.Fatalf("TestData: New() had error: %s", err)
+			}
+			return d
+		},
+	)
+}
+
+func TestBadID(t *testing.T) {
+	ctx := context.Background()
+
+	root := t.TempDir()
+	storeDir := filepath.Join(root, "store")
+	if err := os.Mkdir(storeDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(storeDir)
+	if err != nil {
+		t.Fatalf("TestBadID: New() had error: %s", err)
+	}
+	defer d.Close()
+
+	// A WorkReq outside of the storage directory that a path traversal could reach.
+	outside := &pb.WorkReq{Name: "outside"}
+	b, err := proto.Marshal(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "outside"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", "../outside", "../../dev/null", "not-a-uuid"} {
+		if _, err := d.GetWork(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): GetWork(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+		if _, err := d.GetStatus(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): GetStatus(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+		if err := d.PutStatus(ctx, id, &pb.StatusResp{}); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): PutStatus(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+		if err := d.AddApproval(ctx, id, &pb.Approval{Approver: "alice"}); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): AddApproval(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+		if _, err := d.GetApprovals(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): GetApprovals(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+		if err := d.Delete(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("TestBadID(%s): Delete(): got err == %v, want storage.ErrNotFound", id, err)
+		}
+	}
+
+	files, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	if diff := pretty.Compare([]string{"outside", "store"}, names); diff != "" {
+		t.Errorf("TestBadID: files outside of storage: -want/+got:\n%s", diff)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Package storage defines the Data interface that our service uses to store WorkReqs and their
+statuses. Implementations are in sub-directories:
+	dir/ stores each WorkReq, status and approvals as files in a directory
+	boltdb/ stores everything in an embedded bbolt key/value database
+*/
+package storage
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// ErrNotFound indicates that the requested entry was not found in storage.
+var ErrNotFound = errors.New("not found")
+
+// Data represents our data storage.
+type Data interface {
+	// PutWork stores a WorkReq under the ID. The time it was stored is recorded
+	// as the submit time.
+	PutWork(ctx context.Context, id string, req *pb.WorkReq) error
+	// GetWork retrieves a WorkReq by its ID. If it does not exist, ErrNotFound is returned.
+	GetWork(ctx context.Context, id string) (*pb.WorkReq, error)
+	// PutStatus stores the status of a WorkReq, replacing any status that already exists.
+	// If the WorkReq does not exist, ErrNotFound is returned and nothing is stored.
+	PutStatus(ctx context.Context, id string, status *pb.StatusResp) error
+	// GetStatus retrieves the status of a WorkReq. If the WorkReq has never been executed,
+	// ErrNotFound is returned.
+	GetStatus(ctx context.Context, id string) (*pb.StatusResp, error)
+	// List returns all entries in storage that match the filter, oldest first. Entries
+	// submitted at the same time are sorted by ID.
+	List(ctx context.Context, filter Filter) ([]Entry, error)
+	// AddApproval records an approval for a WorkReq. If the WorkReq does not exist,
+	// ErrNotFound is returned.
+	AddApproval(ctx context.Context, id string, approval *pb.Approval) error
+	// GetApprovals returns the approvals for a WorkReq in the order they were added. If the
+	// WorkReq does not exist, ErrNotFound is returned.
+	GetApprovals(ctx context.Context, id string) ([]*pb.Approval, error)
+	// Delete deletes a WorkReq, its status and approvals. It does not error if the ID does not exist.
+	Delete(ctx context.Context, id string) error
+	// PutBucket stores the state of the token bucket "name", replacing any state that already exists.
+	PutBucket(ctx context.Context, name string, state *pb.TBState) error
+	// GetBuckets returns the state of every token bucket stored with PutBucket() by name.
+	GetBuckets(ctx context.Context) (map[string]*pb.TBState, error)
+	// Close closes the storage.
+	Close() error
+}
+
+// Entry is a summary of a WorkReq in storage.
+type Entry struct {
+	// ID is the unique ID of the WorkReq.
+	ID string
+	// Name is the WorkReq's name.
+	Name string
+	// Desc is the WorkReq's description.
+	Desc string
+	// Submitted is when the WorkReq was stored.
+	Submitted time.Time
+	// Status is the status of the WorkReq. If it has never been executed,
+	// this is StatusNotStarted.
+	Status pb.Status
+	// HadErrors indicates if the WorkReq had errors when executed.
+	HadErrors bool
+	// Submitter is the identity of the caller that submitted the WorkReq.
+	Submitter string
+}
+
+// Filter is used to filter the entries returned by List(). Zero values match everything.
+type Filter struct {
+	// Name matches WorkReqs with this name.
+	Name string
+	// Statuses matches WorkReqs that have any of these statuses.
+	Statuses []pb.Status
+	// SubmittedAfter matches WorkReqs submitted at or after this time.
+	SubmittedAfter time.Time
+	// SubmittedBefore matches WorkReqs submitted before this time.
+	SubmittedBefore time.Time
+}
+
+// Match returns true if the Entry matches the filter.
+func (f Filter) Match(e Entry) bool {
+	if f.Name != "" && f.Name != e.Name {
+		return false
+	}
+	if len(f.Statuses) > 0 {
+		found := false
+		for _, s := range f.Statuses {
+			if s == e.Status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.SubmittedAfter.IsZero() && e.Submitted.Before(f.SubmittedAfter) {
+		return false
+	}
+	if !f.SubmittedBefore.IsZero() && !e.Submitted.Before(f.SubmittedBefore) {
+		return false
+	}
+	return true
+}
+
+// NewEntry creates an Entry from a WorkReq and its status. status can be nil if
+// the WorkReq has not been executed.
+func NewEntry(id string, submitted time.Time, req *pb.WorkReq, status *pb.StatusResp) Entry {
+	e := Entry{
+		ID:        id,
+		Name:      req.Name,
+		Desc:      req.Desc,
+		Submitted: submitted,
+		Status:    pb.Status_StatusNotStarted,
+		Submitter: req.Submitter,
+	}
+	if status != nil {
+		e.Status = status.Status
+		e.HadErrors = status.HadErrors
+	}
+	return e
+}
+
+// Sort sorts entries by submit time, oldest first, and then by ID, which is the order List()
+// returns them in.
+func Sort(entries []Entry) {
+	sort.Slice(
+		entries,
+		func(i, j int) bool {
+			if entries[i].Submitted.Equal(entries[j].Submitted) {
+				return entries[i].ID < entries[j].ID
+			}
+			return entries[i].Submitted.Before(entries[j].Submitted)
+		},
+	)
+}
+
+// AppendApproval appends the encoded approval to "b". The result can be decoded with DecodeApprovals().
+// This allows implementations to store approvals by appending to existing data.
+func AppendApproval(b []byte, approval *pb.Approval) ([]byte, error) {
+	ab, err := proto.Marshal(approval)
+	if err != nil {
+		return nil, err
+	}
+	return protowire.AppendBytes(b, ab), nil
+}
+
+// DecodeApprovals decodes approvals encoded with AppendApproval().
+func DecodeApprovals(b []byte) ([]*pb.Approval, error) {
+	var approvals []*pb.Approval
+	for len(b) > 0 {
+		ab, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		a := &pb.Approval{}
+		if err := proto.Unmarshal(ab, a); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, nil
+}
@@ -0,0 +1,329 @@
+/*
+Package storagetest provides a conformance test for implementations of storage.Data.
+
+Each implementation should call Run() from its own tests:
+	func TestData(t *testing.T) {
+		storagetest.Run(t, func(t *testing.T) storage.Data { ... })
+	}
+*/
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// Run runs the conformance tests against the storage.Data returned by "newData". "newData" is
+// called for each test and must return an empty storage.Data. Run closes it when the test is done.
+func Run(t *testing.T, newData func(t *testing.T) storage.Data) {
+	tests := []struct {
+		desc string
+		test func(t *testing.T, d storage.Data)
+	}{
+		{desc: "Work", test: testWork},
+		{desc: "Status", test: testStatus},
+		{desc: "List", test: testList},
+		{desc: "Approvals", test: testApprovals},
+		{desc: "Delete", test: testDelete},
+		{desc: "Buckets", test: testBuckets},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			d := newData(t)
+			defer d.Close()
+			test.test(t, d)
+		})
+	}
+}
+
+func testWork(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	id := uuid.NewString()
+	req := &pb.WorkReq{Name: "first", Desc: "desc"}
+	if _, err := d.GetWork(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetWork() before PutWork(): got err == %v, want storage.ErrNotFound", err)
+	}
+	if err := d.PutWork(ctx, id, req); err != nil {
+		t.Fatalf("PutWork() had error: %s", err)
+	}
+	if err := d.PutWork(ctx, id, req); err == nil {
+		t.Errorf("PutWork() of an existing ID: got err == nil, want err != nil")
+	}
+
+	got, err := d.GetWork(ctx, id)
+	if err != nil {
+		t.Fatalf("GetWork() had error: %s", err)
+	}
+	if !proto.Equal(got, req) {
+		t.Errorf("GetWork(): got %v, want %v", got, req)
+	}
+}
+
+func testStatus(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	id := uuid.NewString()
+	status := &pb.StatusResp{Name: "first", Status: pb.Status_StatusFailed, HadErrors: true}
+
+	if err := d.PutStatus(ctx, id, status); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("PutStatus() of an unknown ID: got err == %v, want storage.ErrNotFound", err)
+	}
+	if _, err := d.GetStatus(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetStatus() after PutStatus() of an unknown ID: got err == %v, want storage.ErrNotFound", err)
+	}
+
+	if err := d.PutWork(ctx, id, &pb.WorkReq{Name: "first"}); err != nil {
+		t.Fatalf("PutWork() had error: %s", err)
+	}
+	if _, err := d.GetStatus(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetStatus() before PutStatus(): got err == %v, want storage.ErrNotFound", err)
+	}
+	if err := d.PutStatus(ctx, id, &pb.StatusResp{Name: "first", Status: pb.Status_StatusRunning}); err != nil {
+		t.Fatalf("PutStatus() had error: %s", err)
+	}
+	// Replaces the existing status.
+	if err := d.PutStatus(ctx, id, status); err != nil {
+		t.Fatalf("PutStatus() had error: %s", err)
+	}
+	got, err := d.GetStatus(ctx, id)
+	if err != nil {
+		t.Fatalf("GetStatus() had error: %s", err)
+	}
+	if !proto.Equal(got, status) {
+		t.Errorf("GetStatus(): got %v, want %v", got, status)
+	}
+}
+
+func testList(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	entries, err := d.List(ctx, storage.Filter{})
+	if err != nil {
+		t.Fatalf("List() of empty storage had error: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("List() of empty storage: got %d entries, want 0", len(entries))
+	}
+
+	// IDs are random, so submitting at distinct times is the only way to know the order
+	// List() must return them in.
+	reqs := []*pb.WorkReq{
+		{Name: "first", Desc: "desc", Submitter: "alice"},
+		{Name: "second", Desc: "desc", Submitter: "bob"},
+		{Name: "first", Desc: "again", Submitter: "alice"},
+	}
+	ids := make([]string, len(reqs))
+	times := make([]time.Time, len(reqs))
+	for i, req := range reqs {
+		if i > 0 {
+			time.Sleep(50 * time.Millisecond)
+		}
+		ids[i] = uuid.NewString()
+		times[i] = time.Now()
+		if err := d.PutWork(ctx, ids[i], req); err != nil {
+			t.Fatalf("PutWork(%s) had error: %s", ids[i], err)
+		}
+	}
+	if err := d.PutStatus(ctx, ids[1], &pb.StatusResp{Status: pb.Status_StatusFailed, HadErrors: true}); err != nil {
+		t.Fatalf("PutStatus() had error: %s", err)
+	}
+
+	entries, err = d.List(ctx, storage.Filter{})
+	if err != nil {
+		t.Fatalf("List() had error: %s", err)
+	}
+	if len(entries) != len(ids) {
+		t.Fatalf("List(): got %d entries, want %d", len(entries), len(ids))
+	}
+	for i, e := range entries {
+		want := storage.Entry{
+			ID:        ids[i],
+			Name:      reqs[i].Name,
+			Desc:      reqs[i].Desc,
+			Status:    pb.Status_StatusNotStarted,
+			Submitter: reqs[i].Submitter,
+		}
+		if i == 1 {
+			want.Status = pb.Status_StatusFailed
+			want.HadErrors = true
+		}
+		// Submit times are recorded by the storage, so only check they are close to ours.
+		if diff := e.Submitted.Sub(times[i]); diff < -time.Second || diff > time.Second {
+			t.Errorf("List(): entry %d: got Submitted %v, want about %v", i, e.Submitted, times[i])
+		}
+		e.Submitted = time.Time{}
+		if diff := pretty.Compare(want, e); diff != "" {
+			t.Errorf("List(): entry %d: -want/+got:\n%s", i, diff)
+		}
+	}
+
+	tests := []struct {
+		desc   string
+		filter storage.Filter
+		want   []string
+	}{
+		{desc: "No filter", filter: storage.Filter{}, want: ids},
+		{desc: "By name", filter: storage.Filter{Name: "first"}, want: []string{ids[0], ids[2]}},
+		{desc: "By status", filter: storage.Filter{Statuses: []pb.Status{pb.Status_StatusFailed}}, want: ids[1:2]},
+		{
+			desc:   "By not started",
+			filter: storage.Filter{Statuses: []pb.Status{pb.Status_StatusNotStarted}},
+			want:   []string{ids[0], ids[2]},
+		},
+		{desc: "Submitted after", filter: storage.Filter{SubmittedAfter: entries[1].Submitted}, want: ids[1:]},
+		{desc: "Submitted before", filter: storage.Filter{SubmittedBefore: entries[1].Submitted}, want: ids[:1]},
+		{desc: "No match", filter: storage.Filter{Name: "none"}, want: nil},
+	}
+
+	for _, test := range tests {
+		entries, err := d.List(ctx, test.filter)
+		if err != nil {
+			t.Errorf("List(%s) had error: %s", test.desc, err)
+			continue
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.ID)
+		}
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("List(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func testApprovals(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	id := uuid.NewString()
+	if err := d.AddApproval(ctx, id, &pb.Approval{Approver: "alice"}); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("AddApproval() of an unknown ID: got err == %v, want storage.ErrNotFound", err)
+	}
+	if _, err := d.GetApprovals(ctx, id); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetApprovals() of an unknown ID: got err == %v, want storage.ErrNotFound", err)
+	}
+
+	if err := d.PutWork(ctx, id, &pb.WorkReq{Name: "first"}); err != nil {
+		t.Fatalf("PutWork() had error: %s", err)
+	}
+	got, err := d.GetApprovals(ctx, id)
+	if err != nil {
+		t.Fatalf("GetApprovals() before AddApproval() had error: %s", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("GetApprovals() before AddApproval(): got %v, want no approvals", got)
+	}
+
+	approvals := []*pb.Approval{
+		{Approver: "alice", Time: timestamppb.New(time.Unix(100, 0)), Comment: "lgtm"},
+		{Approver: "bob", Time: timestamppb.New(time.Unix(200, 0))},
+	}
+	for _, a := range approvals {
+		if err := d.AddApproval(ctx, id, a); err != nil {
+			t.Fatalf("AddApproval() had error: %s", err)
+		}
+	}
+	got, err = d.GetApprovals(ctx, id)
+	if err != nil {
+		t.Fatalf("GetApprovals() had error: %s", err)
+	}
+	if !proto.Equal(&pb.ApproveResp{Approvals: got}, &pb.ApproveResp{Approvals: approvals}) {
+		t.Errorf("GetApprovals(): got %v, want %v", got, approvals)
+	}
+}
+
+func testDelete(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	if err := d.Delete(ctx, uuid.NewString()); err != nil {
+		t.Errorf("Delete() of an unknown ID had error: %s", err)
+	}
+
+	ids := []string{uuid.NewString(), uuid.NewString()}
+	for _, id := range ids {
+		if err := d.PutWork(ctx, id, &pb.WorkReq{Name: "first"}); err != nil {
+			t.Fatalf("PutWork() had error: %s", err)
+		}
+		if err := d.PutStatus(ctx, id, &pb.StatusResp{Status: pb.Status_StatusCompleted}); err != nil {
+			t.Fatalf("PutStatus() had error: %s", err)
+		}
+		if err := d.AddApproval(ctx, id, &pb.Approval{Approver: "alice"}); err != nil {
+			t.Fatalf("AddApproval() had error: %s", err)
+		}
+	}
+
+	if err := d.Delete(ctx, ids[0]); err != nil {
+		t.Fatalf("Delete() had error: %s", err)
+	}
+	if _, err := d.GetWork(ctx, ids[0]); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetWork() after Delete(): got err == %v, want storage.ErrNotFound", err)
+	}
+	if _, err := d.GetStatus(ctx, ids[0]); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetStatus() after Delete(): got err == %v, want storage.ErrNotFound", err)
+	}
+	if _, err := d.GetApprovals(ctx, ids[0]); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetApprovals() after Delete(): got err == %v, want storage.ErrNotFound", err)
+	}
+
+	entries, err := d.List(ctx, storage.Filter{})
+	if err != nil {
+		t.Fatalf("List() had error: %s", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.ID)
+	}
+	if diff := pretty.Compare(ids[1:], got); diff != "" {
+		t.Errorf("List() after Delete(): -want/+got:\n%s", diff)
+	}
+	if _, err := d.GetWork(ctx, ids[1]); err != nil {
+		t.Errorf("GetWork() of the WorkReq that was not deleted had error: %s", err)
+	}
+}
+
+func testBuckets(t *testing.T, d storage.Data) {
+	ctx := context.Background()
+
+	buckets, err := d.GetBuckets(ctx)
+	if err != nil || len(buckets) != 0 {
+		t.Errorf("GetBuckets() before PutBucket(): got %v, %v, want no buckets", buckets, err)
+	}
+	want := map[string]*pb.TBState{
+		"first":  {Available: 1, LastRefill: timestamppb.New(time.Unix(100, 0))},
+		"second": {Available: 0, LastRefill: timestamppb.New(time.Unix(200, 0))},
+	}
+	for name, state := range want {
+		if err := d.PutBucket(ctx, name, state); err != nil {
+			t.Fatalf("PutBucket(%s) had error: %s", name, err)
+		}
+	}
+	// Replaces the existing state.
+	want["first"] = &pb.TBState{Available: 0, LastRefill: timestamppb.New(time.Unix(300, 0))}
+	if err := d.PutBucket(ctx, "first", want["first"]); err != nil {
+		t.Fatalf("PutBucket(first) had error: %s", err)
+	}
+	buckets, err = d.GetBuckets(ctx)
+	if err != nil {
+		t.Fatalf("GetBuckets() had error: %s", err)
+	}
+	if len(buckets) != len(want) {
+		t.Errorf("GetBuckets(): got %d buckets, want %d", len(buckets), len(want))
+	}
+	for name, state := range want {
+		if !proto.Equal(buckets[name], state) {
+			t.Errorf("GetBuckets()[%s]: got %v, want %v", name, buckets[name], state)
+		}
+	}
+}
@@ -0,0 +1,387 @@
+/*
+Package buckets provides the named token buckets used by the tokenBucket Job. Buckets are
+defined in a config file, a global variable called Data is used to get tokens from them, and
+the state of each bucket is persisted to storage so that a restart does not refill it.
+
+The config file holds a JSON object for each bucket:
+
+	{
+		"Name": "diskEraseSatellite",
+		"Size": 1,
+		"Incr": 1,
+		"Interval": "30m"
+	}
+
+Size is the most tokens the bucket holds, Incr is how many tokens are added every Interval.
+Interval is a Go duration string and must be at least 1s.
+
+The file is reloaded as soon as it changes, with a check for changes every 10 seconds in case
+change notifications are not available. A bucket whose settings changed keeps its tokens (up to
+its new Size), new buckets start with the tokens they had in storage or full, and removed
+buckets fail any Jobs waiting on them. If the new file is not valid, the last good config is kept.
+*/
+package buckets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/watch"
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// pollInterval is how often we check the bucket file for changes in case we miss a change notification.
+const pollInterval = 10 * time.Second
+
+// ErrNotFound is returned when a bucket does not exist.
+var ErrNotFound = errors.New("bucket does not exist")
+
+// Data provides access to our token buckets.
+var Data *Reader
+
+// Init is called in main to read the bucket config file at "path" and restore the state of
+// buckets from "store". It is called manually instead of init() so it can be given storage.
+func Init(path string, store Store) {
+	r, err := NewReader(path, store)
+	if err != nil {
+		panic(err)
+	}
+	Data = r
+}
+
+// Store is where the state of buckets is persisted. storage.Data implements this.
+type Store interface {
+	PutBucket(ctx context.Context, name string, state *pb.TBState) error
+	GetBuckets(ctx context.Context) (map[string]*pb.TBState, error)
+}
+
+// Duration is a time.Duration that is a string like "30m" in JSON.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("must be a duration string like \"30m\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Config is the config of a single bucket.
+type Config struct {
+	// Name is the name of the bucket, used in the tokenBucket Job's "bucket" arg.
+	Name string
+	// Size is the most tokens the bucket can hold.
+	Size int
+	// Incr is how many tokens are added every Interval.
+	Incr int
+	// Interval is how often tokens are added.
+	Interval Duration
+}
+
+// validName is what a bucket name can be. Names are used as file names by some storage.
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+func (c Config) validate() error {
+	if !validName.MatchString(c.Name) {
+		return fmt.Errorf("bucket Name(%s) must be letters, numbers, '_', '.' or '-' and start with a letter or number", c.Name)
+	}
+	if c.Size < 1 {
+		return fmt.Errorf("bucket(%s) Size must be > 0", c.Name)
+	}
+	if c.Incr < 1 {
+		return fmt.Errorf("bucket(%s) Incr must be > 0", c.Name)
+	}
+	if time.Duration(c.Interval) < time.Second {
+		return fmt.Errorf("bucket(%s) Interval must be at least 1s", c.Name)
+	}
+	return nil
+}
+
+// Info is information about a bucket.
+type Info struct {
+	Config
+	// Available is the number of tokens available now.
+	Available int
+	// LastRefill is when tokens were last added.
+	LastRefill time.Time
+}
+
+// NextRefill is when tokens will next be added.
+func (i Info) NextRefill() time.Time {
+	return i.LastRefill.Add(time.Duration(i.Interval))
+}
+
+type entry struct {
+	conf   Config
+	bucket *token.Bucket
+}
+
+func (e entry) info() Info {
+	s := e.bucket.State()
+	return Info{Config: e.conf, Available: s.Available, LastRefill: s.LastRefill}
+}
+
+// Reader reads the bucket config file and provides the buckets in it.
+type Reader struct {
+	path    string
+	store   Store
+	watcher *watch.Watcher
+
+	// mu protects buckets and serializes writes to store so that the latest state is written last.
+	mu      sync.Mutex
+	buckets map[string]entry
+}
+
+// NewReader reads the bucket config file at "path" and restores the state of buckets from "store".
+func NewReader(path string, store Store) (*Reader, error) {
+	if store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	r := &Reader{path: path, store: store, buckets: map[string]entry{}}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	r.watcher, err = watch.New(path, pollInterval, r.reload)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload loads the bucket file. If it is not valid, the last good config is kept.
+func (r *Reader) reload() {
+	if err := r.load(); err != nil {
+		log.Printf("buckets: rejected reload of %s, keeping the last good config: %s", r.path, err)
+		metrics.ConfigReloads.WithLabelValues("buckets", metrics.ResultRejected).Inc()
+		return
+	}
+	r.mu.Lock()
+	n := len(r.buckets)
+	r.mu.Unlock()
+	log.Printf("buckets: reloaded %s with %d buckets", r.path, n)
+	metrics.ConfigReloads.WithLabelValues("buckets", metrics.ResultSuccess).Inc()
+}
+
+func (r *Reader) load() error {
+	confs, err := readConfig(r.path)
+	if err != nil {
+		return err
+	}
+
+	stored, err := r.store.GetBuckets(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not read bucket state from storage: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buckets := map[string]entry{}
+	for name, conf := range confs {
+		old, ok := r.buckets[name]
+		if ok && old.conf == conf {
+			buckets[name] = old
+			continue
+		}
+
+		s := token.State{Available: conf.Size, LastRefill: time.Now()}
+		switch {
+		case ok:
+			s = old.bucket.State()
+		case stored[name] != nil:
+			s = token.State{Available: int(stored[name].Available), LastRefill: stored[name].LastRefill.AsTime()}
+		}
+		b, err := token.Restore(conf.Size, conf.Incr, time.Duration(conf.Interval), s)
+		if err != nil {
+			// Close the buckets we created, the old ones are still in use.
+			for n, e := range buckets {
+				if r.buckets[n].bucket != e.bucket {
+					e.bucket.Close()
+				}
+			}
+			return fmt.Errorf("bucket(%s): %w", name, err)
+		}
+		buckets[name] = entry{conf: conf, bucket: b}
+	}
+
+	// Anyone waiting on a bucket that was changed gets a token from the new bucket, anyone
+	// waiting on a bucket that was removed fails.
+	for name, e := range r.buckets {
+		if buckets[name].bucket != e.bucket {
+			e.bucket.Close()
+		}
+	}
+	r.buckets = buckets
+
+	for name, e := range buckets {
+		r.persist(name, e.bucket)
+	}
+	return nil
+}
+
+// readConfig reads the bucket config file at "path".
+func readConfig(path string) (map[string]Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access bucket config(%s): %w", path, err)
+	}
+	defer f.Close()
+
+	confs := map[string]Config{}
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	for dec.More() {
+		c := Config{}
+		if err := dec.Decode(&c); err != nil {
+			return nil, fmt.Errorf("bucket config(%s) could not be JSON decoded: %w", path, err)
+		}
+		c.Name = strings.TrimSpace(c.Name)
+		if err := c.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := confs[c.Name]; ok {
+			return nil, fmt.Errorf("bucket config(%s) has two buckets named %q", path, c.Name)
+		}
+		confs[c.Name] = c
+	}
+	return confs, nil
+}
+
+// persist writes the state of "b" to storage if it is still the bucket called "name".
+// r.mu must be held.
+func (r *Reader) persist(name string, b *token.Bucket) {
+	if r.buckets[name].bucket != b {
+		return
+	}
+	s := b.State()
+	state := &pb.TBState{Available: int32(s.Available), LastRefill: timestamppb.New(s.LastRefill)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.store.PutBucket(ctx, name, state); err != nil {
+		log.Printf("buckets: could not write state of bucket(%s) to storage: %s", name, err)
+	}
+}
+
+// Token blocks until a token is available from the bucket called "name" or "ctx" is cancelled.
+// If the bucket is changed by a reload while waiting, we wait on the new bucket. ErrNotFound is
+// returned if the bucket does not exist or is removed while waiting.
+func (r *Reader) Token(ctx context.Context, name string) error {
+	for {
+		r.mu.Lock()
+		e, ok := r.buckets[name]
+		r.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("bucket(%s): %w", name, ErrNotFound)
+		}
+
+		err := e.bucket.Token(ctx)
+		if errors.Is(err, token.ErrClosed) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		r.persist(name, e.bucket)
+		r.mu.Unlock()
+		return nil
+	}
+}
+
+// Info returns information about the bucket called "name".
+func (r *Reader) Info(name string) (Info, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.buckets[name]
+	if !ok {
+		return Info{}, false
+	}
+	return e.info(), true
+}
+
+// List returns information about all buckets sorted by name.
+func (r *Reader) List() []Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]Info, 0, len(r.buckets))
+	for _, e := range r.buckets {
+		infos = append(infos, e.info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// Drain removes all available tokens from the bucket called "name".
+func (r *Reader) Drain(name string) (Info, error) {
+	return r.change(name, (*token.Bucket).Drain)
+}
+
+// Refill makes all tokens in the bucket called "name" available.
+func (r *Reader) Refill(name string) (Info, error) {
+	return r.change(name, (*token.Bucket).Refill)
+}
+
+func (r *Reader) change(name string, f func(b *token.Bucket)) (Info, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.buckets[name]
+	if !ok {
+		return Info{}, fmt.Errorf("bucket(%s): %w", name, ErrNotFound)
+	}
+	f(e.bucket)
+	r.persist(name, e.bucket)
+	return e.info(), nil
+}
+
+// Close stops watching the bucket file and closes all buckets. It can be called more than once.
+func (r *Reader) Close() error {
+	r.mu.Lock()
+	w := r.watcher
+	r.watcher = nil
+	r.mu.Unlock()
+
+	var err error
+	if w != nil {
+		err = w.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.buckets {
+		e.bucket.Close()
+	}
+	r.buckets = map[string]entry{}
+	return err
+}
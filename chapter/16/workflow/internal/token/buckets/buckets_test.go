@@ -0,0 +1,194 @@
+package buckets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+const testBuckets = `{"Name": "first", "Size": 2, "Incr": 1, "Interval": "1h"}
+{"Name": "second", "Size": 1, "Incr": 1, "Interval": "30m"}
+`
+
+// fakeStore implements Store.
+type fakeStore struct {
+	mu     sync.Mutex
+	states map[string]*pb.TBState
+}
+
+func (f *fakeStore) PutBucket(ctx context.Context, name string, state *pb.TBState) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.states == nil {
+		f.states = map[string]*pb.TBState{}
+	}
+	f.states[name] = proto.Clone(state).(*pb.TBState)
+	return nil
+}
+
+func (f *fakeStore) GetBuckets(ctx context.Context) (map[string]*pb.TBState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m := map[string]*pb.TBState{}
+	for k, v := range f.states {
+		m[k] = proto.Clone(v).(*pb.TBState)
+	}
+	return m, nil
+}
+
+func (f *fakeStore) available(name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.states[name] == nil {
+		return -1
+	}
+	return int(f.states[name].Available)
+}
+
+func newTestReader(t *testing.T, store Store) *Reader {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "buckets.json")
+	if err := os.WriteFile(path, []byte(testBuckets), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReader(path, store)
+	if err != nil {
+		t.Fatalf("NewReader() had error: %s", err)
+	}
+	t.Cleanup(func() { r.Close() })
+	return r
+}
+
+func TestToken(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeStore{}
+	r := newTestReader(t, store)
+
+	var names []string
+	for _, info := range r.List() {
+		names = append(names, info.Name)
+		if info.Available != info.Size {
+			t.Errorf("TestToken: new bucket(%s): got %d tokens, want %d", info.Name, info.Available, info.Size)
+		}
+	}
+	if len(names) != 2 || names[0] != "first" || names[1] != "second" {
+		t.Errorf("TestToken: List(): got %v, want [first second]", names)
+	}
+
+	if err := r.Token(ctx, "first"); err != nil {
+		t.Fatalf("TestToken: Token(first) had error: %s", err)
+	}
+	if got := store.available("first"); got != 1 {
+		t.Errorf("TestToken: stored tokens after Token(): got %d, want 1", got)
+	}
+	if err := r.Token(ctx, "none"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestToken: Token(none): got err == %v, want ErrNotFound", err)
+	}
+
+	if info, err := r.Drain("second"); err != nil || info.Available != 0 {
+		t.Errorf("TestToken: Drain(second): got %d tokens, err == %v, want 0 tokens", info.Available, err)
+	}
+	if got := store.available("second"); got != 0 {
+		t.Errorf("TestToken: stored tokens after Drain(): got %d, want 0", got)
+	}
+	if _, err := r.Refill("none"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestToken: Refill(none): got err == %v, want ErrNotFound", err)
+	}
+
+	// A new Reader, like after a restart, must start with the stored tokens.
+	r.Close()
+	r2, err := NewReader(r.path, store)
+	if err != nil {
+		t.Fatalf("TestToken: NewReader() after restart had error: %s", err)
+	}
+	defer r2.Close()
+	for name, want := range map[string]int{"first": 1, "second": 0} {
+		info, ok := r2.Info(name)
+		if !ok || info.Available != want {
+			t.Errorf("TestToken: Info(%s) after restart: got %d tokens, ok == %v, want %d", name, info.Available, ok, want)
+		}
+	}
+}
+
+func TestReload(t *testing.T) {
+	ctx := context.Background()
+	r := newTestReader(t, &fakeStore{})
+
+	if _, err := r.Drain("first"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Drain("second"); err != nil {
+		t.Fatal(err)
+	}
+	waitCh := make(chan error, 1)
+	go func() { waitCh <- r.Token(ctx, "first") }()
+	removedCh := make(chan error, 1)
+	go func() { removedCh <- r.Token(ctx, "second") }()
+	time.Sleep(100 * time.Millisecond)
+
+	// "first" grows and keeps its state, "second" is removed.
+	conf := `{"Name": "first", "Size": 5, "Incr": 1, "Interval": "1h"}`
+	if err := os.WriteFile(r.path, []byte(conf), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r.reload()
+
+	info, ok := r.Info("first")
+	if !ok || info.Size != 5 || info.Available != 0 {
+		t.Errorf("TestReload: Info(first): got %+v, ok == %v, want Size 5 with 0 tokens", info, ok)
+	}
+	if _, ok := r.Info("second"); ok {
+		t.Errorf("TestReload: Info(second): got ok == true, want false")
+	}
+	select {
+	case err := <-removedCh:
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("TestReload: Token() waiting on removed bucket: got err == %v, want ErrNotFound", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestReload: Token() waiting on removed bucket did not return")
+	}
+
+	// Anyone waiting on "first" must now get a token from the new bucket.
+	if _, err := r.Refill("first"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-waitCh:
+		if err != nil {
+			t.Errorf("TestReload: Token() waiting on changed bucket had error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestReload: Token() waiting on changed bucket did not return after Refill()")
+	}
+
+	// A bad file keeps the last good config.
+	bad := []string{
+		`{"Name": `,
+		`{"Name": "first", "Size": 0, "Incr": 1, "Interval": "1h"}`,
+		`{"Name": "first", "Size": 1, "Incr": 1, "Interval": "1ms"}`,
+		`{"Name": "first/..", "Size": 1, "Incr": 1, "Interval": "1h"}`,
+		`{"Name": "first", "Size": 1, "Incr": 1, "Interval": "1h", "Other": 1}`,
+		`{"Name": "first", "Size": 1, "Incr": 1, "Interval": "1h"}{"Name": "first", "Size": 1, "Incr": 1, "Interval": "1h"}`,
+	}
+	for _, b := range bad {
+		if err := os.WriteFile(r.path, []byte(b), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.load(); err == nil {
+			t.Errorf("TestReload(%s): got err == nil, want err != nil", b)
+		}
+		if info, ok := r.Info("first"); !ok || info.Size != 5 {
+			t.Errorf("TestReload(%s): Info(first): got %+v, ok == %v, want the last good config", b, info, ok)
+		}
+	}
+}
@@ -3,23 +3,54 @@ package token
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
+// ErrClosed is returned by Token() if the Bucket was closed.
+var ErrClosed = errors.New("token bucket was closed")
+
+// State is the state of a Bucket. It can be stored and used with Restore() to recreate
+// the Bucket, such as after a restart.
+type State struct {
+	// Available is the number of tokens available at LastRefill.
+	Available int
+	// LastRefill is when tokens were last added to the Bucket.
+	LastRefill time.Time
+}
+
 // Bucket is an implementation of a standard token Bucket. The Bucket is refilled at some interval
 // to a maximum value.
 type Bucket struct {
 	// tokens represents an full token Bucket at some size. Every entry into the Bucket
 	// remove capacity.
 	tokens chan struct{}
-	// ticker is a ticket that we use to refresh our tokens at some interval.
-	ticker *time.Ticker
+	// incr is the number of tokens added every interval.
+	incr int
+	// interval is how often we add tokens.
+	interval time.Duration
+
+	// mu protects lastRefill and makes adding tokens and reading the State atomic.
+	mu         sync.Mutex
+	lastRefill time.Time
+
+	// done is closed by Close() to stop our goroutine and any waiting Token() calls.
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // New creates a Bucket instance. size is how many tokens we can hold. incr is the amount of tokens
-// to add at a time. interval is how often to add tokens.
+// to add at a time. interval is how often to add tokens. The Bucket starts full.
 func New(size, incr int, interval time.Duration) (*Bucket, error) {
+	return Restore(size, incr, interval, State{Available: size, LastRefill: time.Now()})
+}
+
+// Restore creates a Bucket like New() that starts with State "s". Tokens that would have been
+// added since s.LastRefill are added, so a Bucket restored after a restart is the same as if
+// it had kept running. If "s" has more tokens than "size", only "size" tokens are available.
+func Restore(size, incr int, interval time.Duration, s State) (*Bucket, error) {
 	if size < 1 {
 		return nil, fmt.Errorf("size must be > 1")
 	}
@@ -30,40 +61,145 @@ func New(size, incr int, interval time.Duration) (*Bucket, error) {
 		return nil, fmt.Errorf("incr must be > 0")
 	}
 
-	b := Bucket{tokens: make(chan struct{}, size), ticker: time.NewTicker(interval)}
-	// This goroutine adds tokens by removing items from our channel. This seems like the
-	// opposite logic of what you'd expect, but this is actually an efficient way of implementing
-	// a token Bucket usign channels.
-	go func() {
-		for _ = range b.ticker.C {
-			for i := 0; i < incr; i++ {
-				select {
-				case <-b.tokens:
-					continue
-				default:
-				}
-				break
-			}
+	now := time.Now()
+	if s.LastRefill.IsZero() || s.LastRefill.After(now) {
+		s.LastRefill = now
+	}
+	if s.Available < 0 {
+		s.Available = 0
+	}
+	if n := int(now.Sub(s.LastRefill) / interval); n > 0 {
+		s.Available += n * incr
+		s.LastRefill = s.LastRefill.Add(time.Duration(n) * interval)
+	}
+	if s.Available > size {
+		s.Available = size
+	}
+
+	b := &Bucket{
+		tokens:     make(chan struct{}, size),
+		incr:       incr,
+		interval:   interval,
+		lastRefill: s.LastRefill,
+		done:       make(chan struct{}),
+	}
+	// A token is taken by putting an item in our channel, so a Bucket with no tokens
+	// available has a full channel.
+	for i := s.Available; i < size; i++ {
+		b.tokens <- struct{}{}
+	}
+	go b.refill(time.Until(s.LastRefill.Add(interval)))
+	return b, nil
+}
+
+// refill adds tokens every interval, starting after "wait".
+func (b *Bucket) refill(wait time.Duration) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-timer.C:
+		}
+
+		b.mu.Lock()
+		b.add(b.incr)
+		b.lastRefill = b.lastRefill.Add(b.interval)
+		next := b.lastRefill.Add(b.interval)
+		b.mu.Unlock()
+
+		timer.Reset(time.Until(next))
+	}
+}
+
+// add adds up to "n" tokens. This seems like the opposite logic of what you'd expect, but
+// removing items from our channel is actually an efficient way of implementing a token Bucket
+// using channels.
+func (b *Bucket) add(n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-b.tokens:
+		default:
+			return
 		}
-	}()
-	return &b, nil
+	}
 }
 
-// close stops the token Bucket's goroutine. This should be called before throwing away the Bucket.
-// If you use the token Bucket after this is called, this can cause major problems like causing the
-// token() call to block forever, as there are no more tokens being added.
+// Close stops the token Bucket's goroutine. This should be called before throwing away the Bucket.
+// Token() calls that are waiting, or made after this, return ErrClosed.
 func (b *Bucket) Close() {
-	b.ticker.Stop()
-	close(b.tokens)
+	b.closeOnce.Do(func() { close(b.done) })
 }
 
 // token blocks until a token is available or the context is cancelled. An error is only returned
-// if the context is cancelled.
+// if the context is cancelled or the Bucket is closed.
 func (b *Bucket) Token(ctx context.Context) error {
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-b.done:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-b.done:
+		return ErrClosed
 	case b.tokens <- struct{}{}:
 	}
 	return nil
 }
+
+// Available returns the number of tokens that are available now.
+func (b *Bucket) Available() int {
+	return cap(b.tokens) - len(b.tokens)
+}
+
+// Drain removes all available tokens. Tokens are added again at the next interval.
+func (b *Bucket) Drain() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		select {
+		case b.tokens <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// Refill makes all tokens available. This does not change when tokens are next added.
+func (b *Bucket) Refill() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.add(cap(b.tokens))
+}
+
+// State returns the current State of the Bucket.
+func (b *Bucket) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return State{Available: b.Available(), LastRefill: b.lastRefill}
+}
+
+// Size returns the most tokens the Bucket can hold.
+func (b *Bucket) Size() int {
+	return cap(b.tokens)
+}
+
+// Incr returns the number of tokens added every Interval().
+func (b *Bucket) Incr() int {
+	return b.incr
+}
+
+// Interval returns how often tokens are added.
+func (b *Bucket) Interval() time.Duration {
+	return b.interval
+}
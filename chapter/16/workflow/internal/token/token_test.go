@@ -0,0 +1,108 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRestore(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		desc  string
+		state State
+		want  int
+	}{
+		{desc: "Empty and just refilled", state: State{Available: 0, LastRefill: now}, want: 0},
+		{desc: "Catches up missed refills", state: State{Available: 0, LastRefill: now.Add(-25 * time.Minute)}, want: 4},
+		{desc: "Capped at size", state: State{Available: 4, LastRefill: now.Add(-time.Hour)}, want: 5},
+		{desc: "More than size", state: State{Available: 10, LastRefill: now}, want: 5},
+		{desc: "Negative Available", state: State{Available: -1, LastRefill: now}, want: 0},
+		{desc: "LastRefill in the future", state: State{Available: 1, LastRefill: now.Add(time.Hour)}, want: 1},
+	}
+
+	for _, test := range tests {
+		b, err := Restore(5, 2, 10*time.Minute, test.state)
+		if err != nil {
+			t.Errorf("TestRestore(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		s := b.State()
+		b.Close()
+		after := time.Now()
+
+		if s.Available != test.want {
+			t.Errorf("TestRestore(%s): Available: got %d, want %d", test.desc, s.Available, test.want)
+		}
+		// LastRefill must stay on the interval so that refills happen when they would have.
+		if s.LastRefill.After(after) || after.Sub(s.LastRefill) >= 10*time.Minute {
+			t.Errorf("TestRestore(%s): LastRefill: got %v, want within 10m before %v", test.desc, s.LastRefill, after)
+		}
+	}
+
+	if _, err := Restore(0, 1, time.Minute, State{}); err == nil {
+		t.Errorf("TestRestore(size 0): got err == nil, want err != nil")
+	}
+}
+
+func TestDrainRefill(t *testing.T) {
+	b, err := New(3, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if b.Available() != 3 {
+		t.Errorf("TestDrainRefill: New(): got %d tokens, want 3", b.Available())
+	}
+	if err := b.Token(context.Background()); err != nil {
+		t.Fatalf("TestDrainRefill: Token() had error: %s", err)
+	}
+	if b.Available() != 2 {
+		t.Errorf("TestDrainRefill: after Token(): got %d tokens, want 2", b.Available())
+	}
+
+	b.Drain()
+	if b.Available() != 0 {
+		t.Errorf("TestDrainRefill: after Drain(): got %d tokens, want 0", b.Available())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := b.Token(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TestDrainRefill: Token() after Drain(): got err == %v, want context.DeadlineExceeded", err)
+	}
+
+	b.Refill()
+	if b.Available() != 3 {
+		t.Errorf("TestDrainRefill: after Refill(): got %d tokens, want 3", b.Available())
+	}
+}
+
+func TestClose(t *testing.T) {
+	b, err := New(1, 1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Drain()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- b.Token(context.Background()) }()
+
+	b.Close()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("TestClose: waiting Token(): got err == %v, want ErrClosed", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestClose: waiting Token() did not return after Close()")
+	}
+
+	// Close() can be called more than once.
+	b.Close()
+	if err := b.Token(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("TestClose: Token() after Close(): got err == %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Package tracing sets up OpenTelemetry tracing for the workflow service.
+
+Each workflow execution is a trace with a span for every Block and Job. The trace is linked to
+the spans of the Submit and Exec RPCs that created it. Spans are exported with OTLP over gRPC
+to an OpenTelemetry collector:
+
+	shutdown, err := tracing.Init(ctx, "127.0.0.1:4317")
+	if err != nil {
+		// Do something
+	}
+	defer shutdown(context.Background())
+
+If the address is empty, spans are not exported, but trace context is still propagated.
+*/
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the name our spans are exported with.
+const ServiceName = "workflow"
+
+// Init sets the global OpenTelemetry TracerProvider to export spans to the OTLP collector at
+// "addr". The returned function flushes any spans that have not been exported and must be
+// called before the program exits.
+func Init(ctx context.Context, addr string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	if addr == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exp, err := otlptracegrpc.New(
+		ctx,
+		otlptracegrpc.WithInsecure(),
+		otlptracegrpc.WithEndpoint(addr),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTLP exporter for %s: %w", addr, err)
+	}
+
+	res, err := resource.New(
+		ctx,
+		resource.WithFromEnv(),
+		resource.WithProcess(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(semconv.ServiceNameKey.String(ServiceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create OTEL resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		// We honor the caller's decision to sample, otherwise we always sample, as there
+		// are few workflows and each one is worth seeing.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+		sdktrace.WithResource(res),
+		sdktrace.WithBatcher(exp),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Traceparent returns the W3C traceparent header for the span in "ctx", or "" if there is none.
+// This is used to store a span so it can be linked to later with Link().
+func Traceparent(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ""
+	}
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// Link returns a trace.Link to the span described by a W3C traceparent header from
+// Traceparent(). If "traceparent" is not valid, ok is false.
+func Link(traceparent string) (link trace.Link, ok bool) {
+	carrier := propagation.MapCarrier{"traceparent": traceparent}
+	sc := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(), carrier))
+	if !sc.IsValid() {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: sc}, true
+}
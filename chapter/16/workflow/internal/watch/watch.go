@@ -0,0 +1,165 @@
+/*
+Package watch calls a function when a file changes. It is used to reload our configuration
+files as soon as they are edited.
+
+Changes are detected with fsnotify. Editors and tools often replace a file instead of writing
+to it, so we watch the directory the file is in and look for events on the file's name.
+Events usually come in bursts, so we wait until there have been no events for a short time
+before calling the function. Because fsnotify does not work on every filesystem (such as some
+network filesystems), we also check the file's size and modification time at an interval:
+
+	w, err := watch.New("configs/es.json", 10*time.Second, func() {
+		// Reload the file.
+	})
+	if err != nil {
+		// Do something
+	}
+	defer w.Close()
+*/
+package watch
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Debounce is how long we wait after the last event on a file before calling the function.
+const Debounce = 250 * time.Millisecond
+
+// Watcher watches a file for changes.
+type Watcher struct {
+	path string
+	poll time.Duration
+	f    func()
+
+	fsw  *fsnotify.Watcher
+	last fileState
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New watches the file at "path" and calls "f" after it changes. The file is also checked for
+// changes every "poll" interval. If fsnotify cannot be used, this logs why and only polls.
+// "f" is never called concurrently.
+func New(path string, poll time.Duration, f func()) (*Watcher, error) {
+	if poll <= 0 {
+		return nil, fmt.Errorf("poll must be > 0")
+	}
+
+	path = filepath.Clean(path)
+	w := &Watcher{
+		path: path,
+		poll: poll,
+		f:    f,
+		last: stat(path),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = fsw.Add(filepath.Dir(path))
+		if err != nil {
+			fsw.Close()
+		}
+	}
+	if err != nil {
+		log.Printf("cannot use fsnotify to watch %s, only checking for changes every %v: %s", path, poll, err)
+	} else {
+		w.fsw = fsw
+	}
+
+	go w.loop()
+	return w, nil
+}
+
+// Close stops watching the file. "f" will not be called after this returns.
+func (w *Watcher) Close() error {
+	close(w.stop)
+	<-w.done
+	if w.fsw != nil {
+		return w.fsw.Close()
+	}
+	return nil
+}
+
+func (w *Watcher) loop() {
+	defer close(w.done)
+
+	var events chan fsnotify.Event
+	var errCh chan error
+	if w.fsw != nil {
+		events = w.fsw.Events
+		errCh = w.fsw.Errors
+	}
+
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	// debounce is created stopped and is started by events.
+	debounce := time.NewTimer(Debounce)
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if filepath.Clean(event.Name) != w.path {
+				continue
+			}
+			if !debounce.Stop() {
+				select {
+				case <-debounce.C:
+				default:
+				}
+			}
+			debounce.Reset(Debounce)
+		case err, ok := <-errCh:
+			if !ok {
+				errCh = nil
+				continue
+			}
+			log.Printf("fsnotify error watching %s: %s", w.path, err)
+		case <-debounce.C:
+			w.changed()
+		case <-ticker.C:
+			if stat(w.path) != w.last {
+				w.changed()
+			}
+		}
+	}
+}
+
+// changed records the current state of the file and calls f.
+func (w *Watcher) changed() {
+	w.last = stat(w.path)
+	w.f()
+}
+
+// fileState is the state of a file that we use to detect changes when polling.
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func stat(path string) fileState {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: fi.Size(), modTime: fi.ModTime()}
+}
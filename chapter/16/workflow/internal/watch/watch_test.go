@@ -0,0 +1,110 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte("first"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	called := make(chan struct{}, 10)
+	// The poll is long so that only fsnotify can detect the changes within the test.
+	w, err := New(path, time.Hour, func() { called <- struct{}{} })
+	if err != nil {
+		t.Fatalf("TestWatcher: New() had error: %s", err)
+	}
+	defer w.Close()
+	if w.fsw == nil {
+		t.Skip("fsnotify is not available")
+	}
+
+	wait := func(desc string) {
+		t.Helper()
+		select {
+		case <-called:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("TestWatcher(%s): function was not called", desc)
+		}
+	}
+
+	// Several writes close together should only cause one call.
+	for i := 0; i < 3; i++ {
+		if err := os.WriteFile(path, []byte("second"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wait("Write")
+	select {
+	case <-called:
+		t.Errorf("TestWatcher(Write): function was called more than once")
+	case <-time.After(2 * Debounce):
+	}
+
+	// Replacing the file is how many editors and our own writers save.
+	tmp := filepath.Join(dir, "config.json.tmp")
+	if err := os.WriteFile(tmp, []byte("third"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	wait("Rename")
+
+	// Changes to other files in the directory are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "other"), []byte("other"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-called:
+		t.Errorf("TestWatcher(Other file): function was called")
+	case <-time.After(2 * Debounce):
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("first"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	called := make(chan struct{}, 10)
+	w, err := New(path, 50*time.Millisecond, func() { called <- struct{}{} })
+	if err != nil {
+		t.Fatalf("TestWatcherPoll: New() had error: %s", err)
+	}
+	// Simulate a filesystem without fsnotify support.
+	w.Close()
+	w = &Watcher{
+		path: path,
+		poll: 50 * time.Millisecond,
+		f:    func() { called <- struct{}{} },
+		last: stat(path),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go w.loop()
+	defer w.Close()
+
+	// Nothing changed, so polling should not call the function.
+	select {
+	case <-called:
+		t.Fatalf("TestWatcherPoll: function was called without a change")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(path, []byte("second, which is longer"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestWatcherPoll: function was not called after a change")
+	}
+}
@@ -9,6 +9,15 @@ import (
 	"github.com/rodaine/table"
 )
 
+// Done returns true if the Status is a final Status that will not change.
+func (x Status) Done() bool {
+	switch x {
+	case Status_StatusCompleted, Status_StatusFailed, Status_StatusCancelled, Status_StatusSkipped:
+		return true
+	}
+	return false
+}
+
 // CLISummary() provides the StatusResp in a summary format that is useful for
 // viewing in a CLI application. It summarizes all blocks into single lines except
 // for the block that is currently running.
@@ -26,8 +35,25 @@ func (x *StatusResp) CLISummary(id string) string {
 	buff.WriteString(fmt.Sprintf("Workflow: %s\n", id))
 	name.Fprintln(&buff, "Name: "+x.Name)
 	desc.Fprintln(&buff, "Description: "+x.Desc)
+	if x.ExecutedBy != "" {
+		buff.WriteString(fmt.Sprintf("Executed by: %s\n", x.ExecutedBy))
+	}
+	for _, a := range x.Approvals {
+		buff.WriteString(fmt.Sprintf("Approved by: %s at %s\n", a.Approver, a.Time.AsTime().Format(time.RFC1123)))
+	}
+	if x.TraceId != "" {
+		buff.WriteString(fmt.Sprintf("Trace ID: %s\n", x.TraceId))
+	}
+	if x.Error != "" {
+		color.New(color.FgRed).Fprintln(&buff, "Error: "+x.Error)
+	}
+	if x.PausedBy != "" {
+		color.New(color.FgYellow).Fprintln(&buff, "Paused by: "+x.PausedBy)
+	}
 
-	if i, block := x.findRunning(x.Blocks); i != -1 {
+	// Blocks with dependencies can run concurrently, so there may be more than one running.
+	for _, i := range x.findRunning(x.Blocks) {
+		block := x.Blocks[i]
 		blockTitle.Fprintln(&buff, fmt.Sprintf("\nRunning Block(%d): %s", i, block.Desc))
 		x.writeRunning(&buff, block)
 	}
@@ -42,11 +68,11 @@ func (x *StatusResp) writeOthers(buff *strings.Builder, blocks []*BlockStatus) {
 	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
 	columnFmt := color.New(color.FgYellow).SprintfFunc()
 
-	tbl := table.New("Block Number", "Desc", "Status").WithWriter(buff)
+	tbl := table.New("Block Number", "ID", "Desc", "Status").WithWriter(buff)
 	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
 
 	for i, block := range blocks {
-		tbl.AddRow(i, block.Desc, block.Status)
+		tbl.AddRow(i, block.Id, block.Desc, block.Status)
 		if block.Status == Status_StatusRunning {
 			continue
 		}
@@ -54,25 +80,76 @@ func (x *StatusResp) writeOthers(buff *strings.Builder, blocks []*BlockStatus) {
 	tbl.Print()
 }
 
-func (x *StatusResp) findRunning(blocks []*BlockStatus) (int, *BlockStatus) {
+func (x *StatusResp) findRunning(blocks []*BlockStatus) []int {
+	var running []int
 	for i, b := range blocks {
 		if b.Status == Status_StatusRunning {
-			return i, b
+			running = append(running, i)
 		}
 	}
-	return -1, nil
+	return running
 }
 
 func (x *StatusResp) writeRunning(buff *strings.Builder, block *BlockStatus) {
 	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
 	columnFmt := color.New(color.FgYellow).SprintfFunc()
 
-	tbl := table.New("Job Number", "Desc", "Status").WithWriter(buff)
+	tbl := table.New("Job Number", "Desc", "Status", "Attempts", "Last Output").WithWriter(buff)
 	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
 
 	for i, job := range block.Jobs {
-		tbl.AddRow(i, job.Desc, job.Status)
+		last := lastLine(job.Output)
+		if job.WaitingOn != "" {
+			last = "waiting: " + job.WaitingOn
+		}
+		tbl.AddRow(i, job.Desc, job.Status, job.Attempts, last)
 	}
 	tbl.Print()
 	return
 }
+
+// lastLine returns the last line of a Job's output.
+func lastLine(output string) string {
+	output = strings.TrimRight(output, "\n")
+	return output[strings.LastIndex(output, "\n")+1:]
+}
+
+// CLISummary provides the PlanResp in a format that is useful for viewing in a CLI application.
+func (x *PlanResp) CLISummary() string {
+	blockTitle := color.New(color.FgCyan).Add(color.Underline)
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+	errColor := color.New(color.FgRed)
+
+	buff := strings.Builder{}
+	if x.Ok {
+		color.New(color.FgGreen).Fprintln(&buff, "Plan OK: the workflow would be accepted and could execute now")
+	} else {
+		errColor.Fprintln(&buff, "Plan had errors: the workflow would not be accepted or could not execute now")
+		for _, e := range x.Errors {
+			errColor.Fprintln(&buff, "\t"+e)
+		}
+	}
+
+	for i, block := range x.Blocks {
+		title := fmt.Sprintf("\nBlock(%d): %s", i, block.Desc)
+		if block.Id != "" {
+			title += fmt.Sprintf(" [id: %s]", block.Id)
+		}
+		blockTitle.Fprintln(&buff, title)
+		if len(block.DependsOn) > 0 {
+			buff.WriteString(fmt.Sprintf("Depends on: %s\n", strings.Join(block.DependsOn, ", ")))
+		}
+		if block.RunOn != RunOn_RunOnSuccess {
+			buff.WriteString(fmt.Sprintf("Runs: %s\n", block.RunOn))
+		}
+
+		tbl := table.New("Job Number", "Name", "Action", "Sites", "Machines", "Error").WithWriter(&buff)
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+		for j, job := range block.Jobs {
+			tbl.AddRow(j, job.Name, job.Action, strings.Join(job.Sites, ","), strings.Join(job.Machines, ","), job.Error)
+		}
+		tbl.Print()
+	}
+	return buff.String()
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0-devel
+// 	protoc-gen-go v1.28.0
 // 	protoc        v3.18.0
 // source: diskerase.proto
 
@@ -12,6 +12,8 @@ import (
 
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -21,6 +23,118 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// RunOn details when a Block is executed. This is decided when the Blocks
+// before it (or that it depends on) have finished. A Block that is not executed
+// because of this is set to StatusSkipped.
+type RunOn int32
+
+const (
+	// Execute the Block unless the WorkReq has been stopped by a fatal Job error,
+	// a cancellation or an emergency stop. Jobs that fail with non-fatal errors do
+	// not stop the WorkReq.
+	RunOn_RunOnSuccess RunOn = 0
+	// Execute the Block only if the WorkReq has been stopped or a Block has failed.
+	RunOn_RunOnFailure RunOn = 1
+	// Always execute the Block. This is useful for cleanup, such as returning a
+	// token or sending a notification. The Block executes even after a
+	// cancellation or an emergency stop.
+	RunOn_RunOnAlways RunOn = 2
+)
+
+// Enum value maps for RunOn.
+var (
+	RunOn_name = map[int32]string{
+		0: "RunOnSuccess",
+		1: "RunOnFailure",
+		2: "RunOnAlways",
+	}
+	RunOn_value = map[string]int32{
+		"RunOnSuccess": 0,
+		"RunOnFailure": 1,
+		"RunOnAlways":  2,
+	}
+)
+
+func (x RunOn) Enum() *RunOn {
+	p := new(RunOn)
+	*p = x
+	return p
+}
+
+func (x RunOn) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunOn) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[0].Descriptor()
+}
+
+func (RunOn) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[0]
+}
+
+func (x RunOn) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunOn.Descriptor instead.
+func (RunOn) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{0}
+}
+
+// OnLocked details what a Job does if a resource it changes is leased by another
+// WorkReq. Jobs lease the resources they change before each attempt and release
+// them when the attempt ends.
+type OnLocked int32
+
+const (
+	// Wait until the other WorkReq releases the resource. The Job's status
+	// says what it is waiting on.
+	OnLocked_OnLockedWait OnLocked = 0
+	// Fail the attempt with an error that contains "(retryable)", so a
+	// RetryPolicy can try again later.
+	OnLocked_OnLockedFail OnLocked = 1
+)
+
+// Enum value maps for OnLocked.
+var (
+	OnLocked_name = map[int32]string{
+		0: "OnLockedWait",
+		1: "OnLockedFail",
+	}
+	OnLocked_value = map[string]int32{
+		"OnLockedWait": 0,
+		"OnLockedFail": 1,
+	}
+)
+
+func (x OnLocked) Enum() *OnLocked {
+	p := new(OnLocked)
+	*p = x
+	return p
+}
+
+func (x OnLocked) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OnLocked) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[1].Descriptor()
+}
+
+func (OnLocked) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[1]
+}
+
+func (x OnLocked) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OnLocked.Descriptor instead.
+func (OnLocked) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{1}
+}
+
 // Status details the status of a Block or Job.
 type Status int32
 
@@ -36,6 +150,12 @@ const (
 	Status_StatusFailed Status = 3
 	// The WorkReq, Block or Job has completed.
 	Status_StatusCompleted Status = 4
+	// The WorkReq is paused. No new Blocks or Jobs will start until it is resumed.
+	Status_StatusPaused Status = 5
+	// The WorkReq, Block or Job was cancelled before it could complete.
+	Status_StatusCancelled Status = 6
+	// The Block was not executed because of its run_on setting.
+	Status_StatusSkipped Status = 7
 )
 
 // Enum value maps for Status.
@@ -46,6 +166,9 @@ var (
 		2: "StatusRunning",
 		3: "StatusFailed",
 		4: "StatusCompleted",
+		5: "StatusPaused",
+		6: "StatusCancelled",
+		7: "StatusSkipped",
 	}
 	Status_value = map[string]int32{
 		"StatusUnknown":    0,
@@ -53,6 +176,9 @@ var (
 		"StatusRunning":    2,
 		"StatusFailed":     3,
 		"StatusCompleted":  4,
+		"StatusPaused":     5,
+		"StatusCancelled":  6,
+		"StatusSkipped":    7,
 	}
 )
 
@@ -67,11 +193,11 @@ func (x Status) String() string {
 }
 
 func (Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_diskerase_proto_enumTypes[0].Descriptor()
+	return file_diskerase_proto_enumTypes[2].Descriptor()
 }
 
 func (Status) Type() protoreflect.EnumType {
-	return &file_diskerase_proto_enumTypes[0]
+	return &file_diskerase_proto_enumTypes[2]
 }
 
 func (x Status) Number() protoreflect.EnumNumber {
@@ -80,7 +206,240 @@ func (x Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Status.Descriptor instead.
 func (Status) EnumDescriptor() ([]byte, []int) {
-	return file_diskerase_proto_rawDescGZIP(), []int{0}
+	return file_diskerase_proto_rawDescGZIP(), []int{2}
+}
+
+// ParamType is the type of a template parameter.
+type ParamType int32
+
+const (
+	// A string. Any value is valid.
+	ParamType_ParamString ParamType = 0
+	// An integer, like "5".
+	ParamType_ParamInt ParamType = 1
+	// A boolean, like "true" or "false".
+	ParamType_ParamBool ParamType = 2
+	// A duration, like "30s" or "1m".
+	ParamType_ParamDuration ParamType = 3
+)
+
+// Enum value maps for ParamType.
+var (
+	ParamType_name = map[int32]string{
+		0: "ParamString",
+		1: "ParamInt",
+		2: "ParamBool",
+		3: "ParamDuration",
+	}
+	ParamType_value = map[string]int32{
+		"ParamString":   0,
+		"ParamInt":      1,
+		"ParamBool":     2,
+		"ParamDuration": 3,
+	}
+)
+
+func (x ParamType) Enum() *ParamType {
+	p := new(ParamType)
+	*p = x
+	return p
+}
+
+func (x ParamType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ParamType) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[3].Descriptor()
+}
+
+func (ParamType) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[3]
+}
+
+func (x ParamType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ParamType.Descriptor instead.
+func (ParamType) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{3}
+}
+
+// ArgType is the type of a Job arg or a policy setting.
+type ArgType int32
+
+const (
+	// A string. Any value is valid.
+	ArgType_ArgString ArgType = 0
+	// An integer, like "5".
+	ArgType_ArgInt ArgType = 1
+	// A boolean, like "true" or "false".
+	ArgType_ArgBool ArgType = 2
+	// A duration, like "30s" or "1m".
+	ArgType_ArgDuration ArgType = 3
+	// A list of strings. Only used by policy settings.
+	ArgType_ArgStringList ArgType = 4
+	// A map of strings to strings. Only used by policy settings.
+	ArgType_ArgStringMap ArgType = 5
+	// A JSON object or a list of them, which the description details. Only used
+	// by policy settings.
+	ArgType_ArgObject ArgType = 6
+)
+
+// Enum value maps for ArgType.
+var (
+	ArgType_name = map[int32]string{
+		0: "ArgString",
+		1: "ArgInt",
+		2: "ArgBool",
+		3: "ArgDuration",
+		4: "ArgStringList",
+		5: "ArgStringMap",
+		6: "ArgObject",
+	}
+	ArgType_value = map[string]int32{
+		"ArgString":     0,
+		"ArgInt":        1,
+		"ArgBool":       2,
+		"ArgDuration":   3,
+		"ArgStringList": 4,
+		"ArgStringMap":  5,
+		"ArgObject":     6,
+	}
+)
+
+func (x ArgType) Enum() *ArgType {
+	p := new(ArgType)
+	*p = x
+	return p
+}
+
+func (x ArgType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArgType) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[4].Descriptor()
+}
+
+func (ArgType) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[4]
+}
+
+func (x ArgType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArgType.Descriptor instead.
+func (ArgType) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{4}
+}
+
+// ESStatus is the emergency stop status of a workflow type.
+type ESStatus int32
+
+const (
+	// Indicates the status was not set.
+	ESStatus_ESUnknown ESStatus = 0
+	// Workflows of this type can be submitted and executed.
+	ESStatus_ESGo ESStatus = 1
+	// Workflows of this type cannot be submitted or executed and running ones
+	// are stopped.
+	ESStatus_ESStop ESStatus = 2
+)
+
+// Enum value maps for ESStatus.
+var (
+	ESStatus_name = map[int32]string{
+		0: "ESUnknown",
+		1: "ESGo",
+		2: "ESStop",
+	}
+	ESStatus_value = map[string]int32{
+		"ESUnknown": 0,
+		"ESGo":      1,
+		"ESStop":    2,
+	}
+)
+
+func (x ESStatus) Enum() *ESStatus {
+	p := new(ESStatus)
+	*p = x
+	return p
+}
+
+func (x ESStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ESStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[5].Descriptor()
+}
+
+func (ESStatus) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[5]
+}
+
+func (x ESStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ESStatus.Descriptor instead.
+func (ESStatus) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{5}
+}
+
+// TBAction is a change to make to a token bucket.
+type TBAction int32
+
+const (
+	TBAction_TBUnknown TBAction = 0
+	// Remove all tokens from the bucket. Jobs will wait for the next refill.
+	TBAction_TBDrain TBAction = 1
+	// Fill the bucket to its size.
+	TBAction_TBRefill TBAction = 2
+)
+
+// Enum value maps for TBAction.
+var (
+	TBAction_name = map[int32]string{
+		0: "TBUnknown",
+		1: "TBDrain",
+		2: "TBRefill",
+	}
+	TBAction_value = map[string]int32{
+		"TBUnknown": 0,
+		"TBDrain":   1,
+		"TBRefill":  2,
+	}
+)
+
+func (x TBAction) Enum() *TBAction {
+	p := new(TBAction)
+	*p = x
+	return p
+}
+
+func (x TBAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TBAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_diskerase_proto_enumTypes[6].Descriptor()
+}
+
+func (TBAction) Type() protoreflect.EnumType {
+	return &file_diskerase_proto_enumTypes[6]
+}
+
+func (x TBAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TBAction.Descriptor instead.
+func (TBAction) EnumDescriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{6}
 }
 
 // WorkReq is the definition of some work to be done by the system.
@@ -95,9 +454,21 @@ type WorkReq struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// A description of what this is doing.
 	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
-	// These are groupings of Jobs. Each block is executed one at
-	// a time.
+	// These are groupings of Jobs. If no Block sets depends_on, each
+	// block is executed one at a time in order. Otherwise Blocks are
+	// executed as soon as the Blocks they depend on have finished, which
+	// allows independent Blocks to execute concurrently.
 	Blocks []*Block `protobuf:"bytes,3,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// The identity of the caller that submitted the WorkReq. This is set
+	// by the server, any value sent by the client is ignored.
+	Submitter string `protobuf:"bytes,4,opt,name=submitter,proto3" json:"submitter,omitempty"`
+	// The approvals the WorkReq has received. This is set by the server when
+	// the WorkReq is executed, any value sent by the client is ignored.
+	Approvals []*Approval `protobuf:"bytes,5,rep,name=approvals,proto3" json:"approvals,omitempty"`
+	// The W3C traceparent of the Submit RPC's span, so the execution trace
+	// can link to it. This is set by the server, any value sent by the client
+	// is ignored.
+	SubmitTrace string `protobuf:"bytes,6,opt,name=submit_trace,json=submitTrace,proto3" json:"submit_trace,omitempty"`
 }
 
 func (x *WorkReq) Reset() {
@@ -153,6 +524,27 @@ func (x *WorkReq) GetBlocks() []*Block {
 	return nil
 }
 
+func (x *WorkReq) GetSubmitter() string {
+	if x != nil {
+		return x.Submitter
+	}
+	return ""
+}
+
+func (x *WorkReq) GetApprovals() []*Approval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+func (x *WorkReq) GetSubmitTrace() string {
+	if x != nil {
+		return x.SubmitTrace
+	}
+	return ""
+}
+
 // WorkResp details the ID that will be used to refer to a submitted WorkReq.
 type WorkResp struct {
 	state         protoimpl.MessageState
@@ -215,6 +607,15 @@ type Block struct {
 	RateLimit int32 `protobuf:"varint,2,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
 	// The Jobs to to execute in this Block.
 	Jobs []*Job `protobuf:"bytes,3,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// An optional ID for the Block that other Blocks can use in depends_on.
+	// It must be unique within the WorkReq.
+	Id string `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	// The IDs of Blocks that must finish before this Block is executed.
+	// Dependencies cannot form a cycle. If any Block in the WorkReq sets this,
+	// Blocks without it have no dependencies and are executed immediately.
+	DependsOn []string `protobuf:"bytes,5,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// When the Block should be executed. Defaults to RunOnSuccess.
+	RunOn RunOn `protobuf:"varint,6,opt,name=run_on,json=runOn,proto3,enum=diskerase.RunOn" json:"run_on,omitempty"`
 }
 
 func (x *Block) Reset() {
@@ -270,6 +671,27 @@ func (x *Block) GetJobs() []*Job {
 	return nil
 }
 
+func (x *Block) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Block) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *Block) GetRunOn() RunOn {
+	if x != nil {
+		return x.RunOn
+	}
+	return RunOn_RunOnSuccess
+}
+
 // Job refers to a Job action that is defined on the server.
 type Job struct {
 	state         protoimpl.MessageState
@@ -286,6 +708,16 @@ type Job struct {
 	// Job on the server. See the Job definition for a list of arguments
 	// that are mandatory and optional.
 	Args map[string]string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// How to retry the Job if it fails. If not set, the Job is not retried.
+	RetryPolicy *RetryPolicy `protobuf:"bytes,4,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// The maximum time a single attempt of the Job can take. If not set,
+	// there is no timeout. The attempt fails when the timeout is reached, but
+	// the Job only stops if it honors the cancellation of its Context. A Job
+	// that does not keeps running, and holding its leases, until it returns.
+	Timeout *durationpb.Duration `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// What to do if a resource the Job changes, such as a machine, is leased by
+	// another WorkReq. Defaults to OnLockedWait.
+	OnLocked OnLocked `protobuf:"varint,6,opt,name=on_locked,json=onLocked,proto3,enum=diskerase.OnLocked" json:"on_locked,omitempty"`
 }
 
 func (x *Job) Reset() {
@@ -341,20 +773,51 @@ func (x *Job) GetArgs() map[string]string {
 	return nil
 }
 
-// ExecReq is used to tell the server to execute a WorkReq
-// that was previously submitted.
-type ExecReq struct {
+func (x *Job) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *Job) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *Job) GetOnLocked() OnLocked {
+	if x != nil {
+		return x.OnLocked
+	}
+	return OnLocked_OnLockedWait
+}
+
+// RetryPolicy details how a Job is retried when it fails. A Job that returns a
+// fatal error is never retried.
+type RetryPolicy struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// This is the unique ID of the WorkReq given back
-	// by WorkResp.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The maximum number of attempts, including the first. < 2 means
+	// the Job will not be retried.
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// How long to wait before the first retry. The wait doubles after each
+	// attempt up to max_backoff. Defaults to 1 second.
+	InitialBackoff *durationpb.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	// The maximum time to wait between attempts. Defaults to 1 minute.
+	MaxBackoff *durationpb.Duration `protobuf:"bytes,3,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"`
+	// If set, only errors containing one of these strings are retried.
+	RetryableErrors []string `protobuf:"bytes,4,rep,name=retryable_errors,json=retryableErrors,proto3" json:"retryable_errors,omitempty"`
+	// Errors containing one of these strings are not retried and are treated
+	// as a fatal error. This takes precedence over retryable_errors.
+	FatalErrors []string `protobuf:"bytes,5,rep,name=fatal_errors,json=fatalErrors,proto3" json:"fatal_errors,omitempty"`
 }
 
-func (x *ExecReq) Reset() {
-	*x = ExecReq{}
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_diskerase_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -362,13 +825,13 @@ func (x *ExecReq) Reset() {
 	}
 }
 
-func (x *ExecReq) String() string {
+func (x *RetryPolicy) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecReq) ProtoMessage() {}
+func (*RetryPolicy) ProtoMessage() {}
 
-func (x *ExecReq) ProtoReflect() protoreflect.Message {
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
 	mi := &file_diskerase_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -380,27 +843,60 @@ func (x *ExecReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecReq.ProtoReflect.Descriptor instead.
-func (*ExecReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
 	return file_diskerase_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ExecReq) GetId() string {
+func (x *RetryPolicy) GetMaxAttempts() int32 {
 	if x != nil {
-		return x.Id
+		return x.MaxAttempts
 	}
-	return ""
+	return 0
 }
 
-// ExecResp is the response from an ExecReq.
-type ExecResp struct {
+func (x *RetryPolicy) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetMaxBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetRetryableErrors() []string {
+	if x != nil {
+		return x.RetryableErrors
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetFatalErrors() []string {
+	if x != nil {
+		return x.FatalErrors
+	}
+	return nil
+}
+
+// ExecReq is used to tell the server to execute a WorkReq
+// that was previously submitted.
+type ExecReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// This is the unique ID of the WorkReq given back
+	// by WorkResp.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *ExecResp) Reset() {
-	*x = ExecResp{}
+func (x *ExecReq) Reset() {
+	*x = ExecReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_diskerase_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,13 +904,13 @@ func (x *ExecResp) Reset() {
 	}
 }
 
-func (x *ExecResp) String() string {
+func (x *ExecReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExecResp) ProtoMessage() {}
+func (*ExecReq) ProtoMessage() {}
 
-func (x *ExecResp) ProtoReflect() protoreflect.Message {
+func (x *ExecReq) ProtoReflect() protoreflect.Message {
 	mi := &file_diskerase_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -426,23 +922,27 @@ func (x *ExecResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExecResp.ProtoReflect.Descriptor instead.
-func (*ExecResp) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExecReq.ProtoReflect.Descriptor instead.
+func (*ExecReq) Descriptor() ([]byte, []int) {
 	return file_diskerase_proto_rawDescGZIP(), []int{5}
 }
 
-// StatusReq requests a status update from the server.
-type StatusReq struct {
+func (x *ExecReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ExecResp is the response from an ExecReq.
+type ExecResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The unique ID of the WorkReq.
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *StatusReq) Reset() {
-	*x = StatusReq{}
+func (x *ExecResp) Reset() {
+	*x = ExecResp{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_diskerase_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -450,13 +950,13 @@ func (x *StatusReq) Reset() {
 	}
 }
 
-func (x *StatusReq) String() string {
+func (x *ExecResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatusReq) ProtoMessage() {}
+func (*ExecResp) ProtoMessage() {}
 
-func (x *StatusReq) ProtoReflect() protoreflect.Message {
+func (x *ExecResp) ProtoReflect() protoreflect.Message {
 	mi := &file_diskerase_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -468,41 +968,23 @@ func (x *StatusReq) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatusReq.ProtoReflect.Descriptor instead.
-func (*StatusReq) Descriptor() ([]byte, []int) {
+// Deprecated: Use ExecResp.ProtoReflect.Descriptor instead.
+func (*ExecResp) Descriptor() ([]byte, []int) {
 	return file_diskerase_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *StatusReq) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-// StatusResp is the status of WorkReq.
-type StatusResp struct {
+// CancelReq is used to tell the server to cancel a running WorkReq.
+type CancelReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The name of the WorkReq.
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// The description of the WorkReq.
-	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
-	// The overall status of the WorkReq.
-	Status Status `protobuf:"varint,3,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
-	// The status information on the Blocks.
-	Blocks []*BlockStatus `protobuf:"bytes,4,rep,name=blocks,proto3" json:"blocks,omitempty"`
-	// If we are SatusFailed or StatusCompleted, if
-	// there were any errors when run.
-	HadErrors bool `protobuf:"varint,5,opt,name=had_errors,json=hadErrors,proto3" json:"had_errors,omitempty"`
-	// If the WorkReq was stopped with emergency stop.
-	WasEsStopped bool `protobuf:"varint,6,opt,name=was_es_stopped,json=wasEsStopped,proto3" json:"was_es_stopped,omitempty"`
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *StatusResp) Reset() {
-	*x = StatusResp{}
+func (x *CancelReq) Reset() {
+	*x = CancelReq{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_diskerase_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -510,13 +992,13 @@ func (x *StatusResp) Reset() {
 	}
 }
 
-func (x *StatusResp) String() string {
+func (x *CancelReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatusResp) ProtoMessage() {}
+func (*CancelReq) ProtoMessage() {}
 
-func (x *StatusResp) ProtoReflect() protoreflect.Message {
+func (x *CancelReq) ProtoReflect() protoreflect.Message {
 	mi := &file_diskerase_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -528,86 +1010,85 @@ func (x *StatusResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatusResp.ProtoReflect.Descriptor instead.
-func (*StatusResp) Descriptor() ([]byte, []int) {
+// Deprecated: Use CancelReq.ProtoReflect.Descriptor instead.
+func (*CancelReq) Descriptor() ([]byte, []int) {
 	return file_diskerase_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *StatusResp) GetName() string {
+func (x *CancelReq) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-func (x *StatusResp) GetDesc() string {
-	if x != nil {
-		return x.Desc
-	}
-	return ""
+// CancelResp is the response from a CancelReq.
+type CancelResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *StatusResp) GetStatus() Status {
-	if x != nil {
-		return x.Status
+func (x *CancelResp) Reset() {
+	*x = CancelResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return Status_StatusUnknown
 }
 
-func (x *StatusResp) GetBlocks() []*BlockStatus {
-	if x != nil {
-		return x.Blocks
-	}
-	return nil
+func (x *CancelResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *StatusResp) GetHadErrors() bool {
-	if x != nil {
-		return x.HadErrors
+func (*CancelResp) ProtoMessage() {}
+
+func (x *CancelResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *StatusResp) GetWasEsStopped() bool {
-	if x != nil {
-		return x.WasEsStopped
-	}
-	return false
+// Deprecated: Use CancelResp.ProtoReflect.Descriptor instead.
+func (*CancelResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{8}
 }
 
-// BlockStatus holds the status of block execution.
-type BlockStatus struct {
+// PauseReq is used to tell the server to pause a running WorkReq. Jobs
+// that are already executing will finish, but no new Blocks or Jobs will start.
+type PauseReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The description of the block.
-	Desc string `protobuf:"bytes,1,opt,name=desc,proto3" json:"desc,omitempty"`
-	// The status of the block.
-	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
-	// If there any errors in Jobs in the Block.
-	HasError bool `protobuf:"varint,3,opt,name=has_error,json=hasError,proto3" json:"has_error,omitempty"`
-	// The status of Jobs in the Block.
-	Jobs []*JobStatus `protobuf:"bytes,4,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *BlockStatus) Reset() {
-	*x = BlockStatus{}
+func (x *PauseReq) Reset() {
+	*x = PauseReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_diskerase_proto_msgTypes[8]
+		mi := &file_diskerase_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *BlockStatus) String() string {
+func (x *PauseReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlockStatus) ProtoMessage() {}
+func (*PauseReq) ProtoMessage() {}
 
-func (x *BlockStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_diskerase_proto_msgTypes[8]
+func (x *PauseReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -618,74 +1099,84 @@ func (x *BlockStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlockStatus.ProtoReflect.Descriptor instead.
-func (*BlockStatus) Descriptor() ([]byte, []int) {
-	return file_diskerase_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use PauseReq.ProtoReflect.Descriptor instead.
+func (*PauseReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *BlockStatus) GetDesc() string {
+func (x *PauseReq) GetId() string {
 	if x != nil {
-		return x.Desc
+		return x.Id
 	}
 	return ""
 }
 
-func (x *BlockStatus) GetStatus() Status {
-	if x != nil {
-		return x.Status
-	}
-	return Status_StatusUnknown
+// PauseResp is the response from a PauseReq.
+type PauseResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *BlockStatus) GetHasError() bool {
-	if x != nil {
-		return x.HasError
+func (x *PauseResp) Reset() {
+	*x = PauseResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return false
 }
 
-func (x *BlockStatus) GetJobs() []*JobStatus {
-	if x != nil {
-		return x.Jobs
+func (x *PauseResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseResp) ProtoMessage() {}
+
+func (x *PauseResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-// JobStatus holds the status of the Jobs.
-type JobStatus struct {
+// Deprecated: Use PauseResp.ProtoReflect.Descriptor instead.
+func (*PauseResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{10}
+}
+
+// ResumeReq is used to tell the server to resume a paused WorkReq.
+type ResumeReq struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The name of the Job called.
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	// The description of the Job.
-	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
-	// The args for the Job.
-	Args map[string]string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// The status of the Job.
-	Status Status `protobuf:"varint,4,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
-	// The error, if there was one.
-	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *JobStatus) Reset() {
-	*x = JobStatus{}
+func (x *ResumeReq) Reset() {
+	*x = ResumeReq{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_diskerase_proto_msgTypes[9]
+		mi := &file_diskerase_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *JobStatus) String() string {
+func (x *ResumeReq) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobStatus) ProtoMessage() {}
+func (*ResumeReq) ProtoMessage() {}
 
-func (x *JobStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_diskerase_proto_msgTypes[9]
+func (x *ResumeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -696,92 +1187,3007 @@ func (x *JobStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
-func (*JobStatus) Descriptor() ([]byte, []int) {
-	return file_diskerase_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ResumeReq.ProtoReflect.Descriptor instead.
+func (*ResumeReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *JobStatus) GetName() string {
+func (x *ResumeReq) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-func (x *JobStatus) GetDesc() string {
-	if x != nil {
-		return x.Desc
-	}
-	return ""
+// ResumeResp is the response from a ResumeReq.
+type ResumeResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 }
 
-func (x *JobStatus) GetArgs() map[string]string {
-	if x != nil {
-		return x.Args
+func (x *ResumeResp) Reset() {
+	*x = ResumeResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *JobStatus) GetStatus() Status {
-	if x != nil {
-		return x.Status
+func (x *ResumeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeResp) ProtoMessage() {}
+
+func (x *ResumeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return Status_StatusUnknown
+	return mi.MessageOf(x)
 }
 
-func (x *JobStatus) GetError() string {
-	if x != nil {
-		return x.Error
+// Deprecated: Use ResumeResp.ProtoReflect.Descriptor instead.
+func (*ResumeResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{12}
+}
+
+// ApproveReq is used to approve a submitted WorkReq so that it can be executed.
+// Workflows that require approvals must be approved by identities other than
+// the submitter.
+type ApproveReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// An optional comment recorded with the approval.
+	Comment string `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *ApproveReq) Reset() {
+	*x = ApproveReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return ""
 }
 
-var File_diskerase_proto protoreflect.FileDescriptor
+func (x *ApproveReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-var file_diskerase_proto_rawDesc = []byte{
-	0x0a, 0x0f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x12, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x22, 0x5b, 0x0a, 0x07,
-	0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12,
-	0x28, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x22, 0x1a, 0x0a, 0x08, 0x57, 0x6f, 0x72,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x5e, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12,
-	0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65,
-	0x73, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
-	0x74, 0x12, 0x22, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4a, 0x6f, 0x62, 0x52,
-	0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0x94, 0x01, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x2c, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e,
-	0x4a, 0x6f, 0x62, 0x2e, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x61,
-	0x72, 0x67, 0x73, 0x1a, 0x37, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x19, 0x0a, 0x07,
-	0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x0a, 0x0a, 0x08, 0x45, 0x78, 0x65, 0x63, 0x52,
-	0x65, 0x73, 0x70, 0x22, 0x1b, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x22, 0xd4, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12,
-	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
-	0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x2e, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63,
-	0x6b, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x64, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72,
-	0x73, 0x12, 0x24, 0x0a, 0x0e, 0x77, 0x61, 0x73, 0x5f, 0x65, 0x73, 0x5f, 0x73, 0x74, 0x6f, 0x70,
-	0x70, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x77, 0x61, 0x73, 0x45, 0x73,
-	0x53, 0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x22, 0x93, 0x01, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63,
+func (*ApproveReq) ProtoMessage() {}
+
+func (x *ApproveReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReq.ProtoReflect.Descriptor instead.
+func (*ApproveReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ApproveReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ApproveReq) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+// ApproveResp is the response from an ApproveReq.
+type ApproveResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// All approvals the WorkReq has received, including this one.
+	Approvals []*Approval `protobuf:"bytes,1,rep,name=approvals,proto3" json:"approvals,omitempty"`
+}
+
+func (x *ApproveResp) Reset() {
+	*x = ApproveResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApproveResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveResp) ProtoMessage() {}
+
+func (x *ApproveResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveResp.ProtoReflect.Descriptor instead.
+func (*ApproveResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ApproveResp) GetApprovals() []*Approval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+// Approval records that an identity approved a WorkReq.
+type Approval struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The identity that approved the WorkReq.
+	Approver string `protobuf:"bytes,1,opt,name=approver,proto3" json:"approver,omitempty"`
+	// When the WorkReq was approved.
+	Time *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// The comment given with the approval.
+	Comment string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *Approval) Reset() {
+	*x = Approval{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Approval) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Approval) ProtoMessage() {}
+
+func (x *Approval) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Approval.ProtoReflect.Descriptor instead.
+func (*Approval) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Approval) GetApprover() string {
+	if x != nil {
+		return x.Approver
+	}
+	return ""
+}
+
+func (x *Approval) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Approval) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+// AuditReq requests the audit records for a WorkReq.
+type AuditReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *AuditReq) Reset() {
+	*x = AuditReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditReq) ProtoMessage() {}
+
+func (x *AuditReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditReq.ProtoReflect.Descriptor instead.
+func (*AuditReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AuditReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// AuditResp is the response from an AuditReq.
+type AuditResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The audit records for the WorkReq, oldest first.
+	Records []*AuditRecord `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (x *AuditResp) Reset() {
+	*x = AuditResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditResp) ProtoMessage() {}
+
+func (x *AuditResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditResp.ProtoReflect.Descriptor instead.
+func (*AuditResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AuditResp) GetRecords() []*AuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// AuditRecord records an RPC call about a WorkReq or a change in the status of a
+// WorkReq, Block or Job.
+type AuditRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// When this happened.
+	Time *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	// The kind of record, one of "rpc", "work", "block" or "job".
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// For "rpc" records, the full gRPC method name that was called.
+	Method string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	// For "rpc" records, the identity of the caller. This is empty if the server
+	// is not using mTLS.
+	Caller string `protobuf:"bytes,5,opt,name=caller,proto3" json:"caller,omitempty"`
+	// For "rpc" records, the gRPC status code that was returned.
+	Code string `protobuf:"bytes,6,opt,name=code,proto3" json:"code,omitempty"`
+	// The error returned by the RPC or recorded with the status.
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// For "block" and "job" records, the index of the Block.
+	Block int32 `protobuf:"varint,8,opt,name=block,proto3" json:"block,omitempty"`
+	// For "job" records, the index of the Job in the Block.
+	Job int32 `protobuf:"varint,9,opt,name=job,proto3" json:"job,omitempty"`
+	// For "work", "block" and "job" records, the status before the change.
+	From Status `protobuf:"varint,10,opt,name=from,proto3,enum=diskerase.Status" json:"from,omitempty"`
+	// For "work", "block" and "job" records, the status after the change.
+	To Status `protobuf:"varint,11,opt,name=to,proto3,enum=diskerase.Status" json:"to,omitempty"`
+	// For "work" records, if the WorkReq was stopped with emergency stop.
+	WasEsStopped bool `protobuf:"varint,12,opt,name=was_es_stopped,json=wasEsStopped,proto3" json:"was_es_stopped,omitempty"`
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AuditRecord) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetBlock() int32 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetJob() int32 {
+	if x != nil {
+		return x.Job
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetFrom() Status {
+	if x != nil {
+		return x.From
+	}
+	return Status_StatusUnknown
+}
+
+func (x *AuditRecord) GetTo() Status {
+	if x != nil {
+		return x.To
+	}
+	return Status_StatusUnknown
+}
+
+func (x *AuditRecord) GetWasEsStopped() bool {
+	if x != nil {
+		return x.WasEsStopped
+	}
+	return false
+}
+
+// StatusReq requests a status update from the server.
+type StatusReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *StatusReq) Reset() {
+	*x = StatusReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusReq) ProtoMessage() {}
+
+func (x *StatusReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusReq.ProtoReflect.Descriptor instead.
+func (*StatusReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StatusReq) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// StatusResp is the status of WorkReq.
+type StatusResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the WorkReq.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The description of the WorkReq.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The overall status of the WorkReq.
+	Status Status `protobuf:"varint,3,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
+	// The status information on the Blocks.
+	Blocks []*BlockStatus `protobuf:"bytes,4,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	// If we are SatusFailed or StatusCompleted, if
+	// there were any errors when run.
+	HadErrors bool `protobuf:"varint,5,opt,name=had_errors,json=hadErrors,proto3" json:"had_errors,omitempty"`
+	// If the WorkReq was stopped with emergency stop, or one of its Jobs was
+	// stopped by a scoped emergency stop.
+	WasEsStopped bool `protobuf:"varint,6,opt,name=was_es_stopped,json=wasEsStopped,proto3" json:"was_es_stopped,omitempty"`
+	// An error that caused the WorkReq to fail that did not come from a Job,
+	// such as the server restarting while the WorkReq was running.
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	// The identity of the caller that executed the WorkReq.
+	ExecutedBy string `protobuf:"bytes,8,opt,name=executed_by,json=executedBy,proto3" json:"executed_by,omitempty"`
+	// The approvals the WorkReq had when it was executed.
+	Approvals []*Approval `protobuf:"bytes,9,rep,name=approvals,proto3" json:"approvals,omitempty"`
+	// The OpenTelemetry trace ID of the execution of the WorkReq. If the
+	// WorkReq was resumed after a server restart, this is the trace of the
+	// latest execution.
+	TraceId string `protobuf:"bytes,10,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	// If the WorkReq was paused by a policy, such as a maintenance window
+	// closing, why it was paused. It is resumed when the policy allows it.
+	// This is empty if the WorkReq was paused with the Pause RPC.
+	PausedBy string `protobuf:"bytes,11,opt,name=paused_by,json=pausedBy,proto3" json:"paused_by,omitempty"`
+}
+
+func (x *StatusResp) Reset() {
+	*x = StatusResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StatusResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResp) ProtoMessage() {}
+
+func (x *StatusResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResp.ProtoReflect.Descriptor instead.
+func (*StatusResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StatusResp) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StatusResp) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *StatusResp) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_StatusUnknown
+}
+
+func (x *StatusResp) GetBlocks() []*BlockStatus {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *StatusResp) GetHadErrors() bool {
+	if x != nil {
+		return x.HadErrors
+	}
+	return false
+}
+
+func (x *StatusResp) GetWasEsStopped() bool {
+	if x != nil {
+		return x.WasEsStopped
+	}
+	return false
+}
+
+func (x *StatusResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *StatusResp) GetExecutedBy() string {
+	if x != nil {
+		return x.ExecutedBy
+	}
+	return ""
+}
+
+func (x *StatusResp) GetApprovals() []*Approval {
+	if x != nil {
+		return x.Approvals
+	}
+	return nil
+}
+
+func (x *StatusResp) GetTraceId() string {
+	if x != nil {
+		return x.TraceId
+	}
+	return ""
+}
+
+func (x *StatusResp) GetPausedBy() string {
+	if x != nil {
+		return x.PausedBy
+	}
+	return ""
+}
+
+// BlockStatus holds the status of block execution.
+type BlockStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The description of the block.
+	Desc string `protobuf:"bytes,1,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The status of the block.
+	Status Status `protobuf:"varint,2,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
+	// If there any errors in Jobs in the Block.
+	HasError bool `protobuf:"varint,3,opt,name=has_error,json=hasError,proto3" json:"has_error,omitempty"`
+	// The status of Jobs in the Block.
+	Jobs []*JobStatus `protobuf:"bytes,4,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// The ID of the Block, if it had one.
+	Id string `protobuf:"bytes,5,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *BlockStatus) Reset() {
+	*x = BlockStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockStatus) ProtoMessage() {}
+
+func (x *BlockStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockStatus.ProtoReflect.Descriptor instead.
+func (*BlockStatus) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *BlockStatus) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *BlockStatus) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_StatusUnknown
+}
+
+func (x *BlockStatus) GetHasError() bool {
+	if x != nil {
+		return x.HasError
+	}
+	return false
+}
+
+func (x *BlockStatus) GetJobs() []*JobStatus {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *BlockStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// JobStatus holds the status of the Jobs.
+type JobStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the Job called.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The description of the Job.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The args for the Job.
+	Args map[string]string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The status of the Job.
+	Status Status `protobuf:"varint,4,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
+	// The error, if there was one.
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// The number of times the Job has been attempted.
+	Attempts int32 `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// The errors from each attempt that failed, in order.
+	AttemptErrors []string `protobuf:"bytes,7,rep,name=attempt_errors,json=attemptErrors,proto3" json:"attempt_errors,omitempty"`
+	// The output the Job recorded, such as from a command it ran. Only
+	// the end of large outputs is kept.
+	Output string `protobuf:"bytes,8,opt,name=output,proto3" json:"output,omitempty"`
+	// If the Job is waiting for another WorkReq to release a resource, what
+	// it is waiting on.
+	WaitingOn string `protobuf:"bytes,9,opt,name=waiting_on,json=waitingOn,proto3" json:"waiting_on,omitempty"`
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *JobStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobStatus) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *JobStatus) GetArgs() map[string]string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *JobStatus) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_StatusUnknown
+}
+
+func (x *JobStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobStatus) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *JobStatus) GetAttemptErrors() []string {
+	if x != nil {
+		return x.AttemptErrors
+	}
+	return nil
+}
+
+func (x *JobStatus) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *JobStatus) GetWaitingOn() string {
+	if x != nil {
+		return x.WaitingOn
+	}
+	return ""
+}
+
+// ListReq is used to list WorkReqs that have been submitted to the server. All
+// filters are optional and WorkReqs must match every filter that is set.
+type ListReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Only list WorkReqs with this name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Only list WorkReqs with one of these statuses. WorkReqs that have been
+	// submitted but not executed are StatusNotStarted.
+	Statuses []Status `protobuf:"varint,2,rep,packed,name=statuses,proto3,enum=diskerase.Status" json:"statuses,omitempty"`
+	// Only list WorkReqs submitted at or after this time.
+	SubmittedAfter *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=submitted_after,json=submittedAfter,proto3" json:"submitted_after,omitempty"`
+	// Only list WorkReqs submitted before this time.
+	SubmittedBefore *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=submitted_before,json=submittedBefore,proto3" json:"submitted_before,omitempty"`
+	// The maximum number of WorkReqs to return. < 1 defaults to 100 and the
+	// maximum is 1000.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token from a previous ListResp. The filters must be the same
+	// as in the previous ListReq.
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListReq) Reset() {
+	*x = ListReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReq) ProtoMessage() {}
+
+func (x *ListReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReq.ProtoReflect.Descriptor instead.
+func (*ListReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListReq) GetStatuses() []Status {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ListReq) GetSubmittedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAfter
+	}
+	return nil
+}
+
+func (x *ListReq) GetSubmittedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedBefore
+	}
+	return nil
+}
+
+func (x *ListReq) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListReq) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// ListResp is the response from a ListReq.
+type ListResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The WorkReqs that matched, oldest first.
+	Workflows []*WorkflowSummary `protobuf:"bytes,1,rep,name=workflows,proto3" json:"workflows,omitempty"`
+	// If set, pass this in ListReq.page_token to get the next page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ListResp) Reset() {
+	*x = ListResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResp) ProtoMessage() {}
+
+func (x *ListResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResp.ProtoReflect.Descriptor instead.
+func (*ListResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListResp) GetWorkflows() []*WorkflowSummary {
+	if x != nil {
+		return x.Workflows
+	}
+	return nil
+}
+
+func (x *ListResp) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// WorkflowSummary is a summary of a WorkReq stored on the server.
+type WorkflowSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The unique ID of the WorkReq.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The name of the WorkReq.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The description of the WorkReq.
+	Desc string `protobuf:"bytes,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	// When the WorkReq was submitted.
+	Submitted *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=submitted,proto3" json:"submitted,omitempty"`
+	// The overall status of the WorkReq.
+	Status Status `protobuf:"varint,5,opt,name=status,proto3,enum=diskerase.Status" json:"status,omitempty"`
+	// If there were any errors when the WorkReq was run.
+	HadErrors bool `protobuf:"varint,6,opt,name=had_errors,json=hadErrors,proto3" json:"had_errors,omitempty"`
+	// The identity of the caller that submitted the WorkReq.
+	Submitter string `protobuf:"bytes,7,opt,name=submitter,proto3" json:"submitter,omitempty"`
+}
+
+func (x *WorkflowSummary) Reset() {
+	*x = WorkflowSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WorkflowSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkflowSummary) ProtoMessage() {}
+
+func (x *WorkflowSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkflowSummary.ProtoReflect.Descriptor instead.
+func (*WorkflowSummary) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *WorkflowSummary) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WorkflowSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *WorkflowSummary) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *WorkflowSummary) GetSubmitted() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Submitted
+	}
+	return nil
+}
+
+func (x *WorkflowSummary) GetStatus() Status {
+	if x != nil {
+		return x.Status
+	}
+	return Status_StatusUnknown
+}
+
+func (x *WorkflowSummary) GetHadErrors() bool {
+	if x != nil {
+		return x.HadErrors
+	}
+	return false
+}
+
+func (x *WorkflowSummary) GetSubmitter() string {
+	if x != nil {
+		return x.Submitter
+	}
+	return ""
+}
+
+// PlanResp details what would happen if a WorkReq was submitted and executed now.
+type PlanResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// If the WorkReq would be accepted by Submit() and could be executed now.
+	Ok bool `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	// The problems that would stop the WorkReq from being accepted or executed,
+	// such as an invalid Job, a policy violation or an emergency stop.
+	Errors []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	// The plan for each Block, in the same order as the WorkReq.
+	Blocks []*BlockPlan `protobuf:"bytes,3,rep,name=blocks,proto3" json:"blocks,omitempty"`
+}
+
+func (x *PlanResp) Reset() {
+	*x = PlanResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlanResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanResp) ProtoMessage() {}
+
+func (x *PlanResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanResp.ProtoReflect.Descriptor instead.
+func (*PlanResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PlanResp) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *PlanResp) GetErrors() []string {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *PlanResp) GetBlocks() []*BlockPlan {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+// BlockPlan details what a Block would do.
+type BlockPlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The ID of the Block, if it had one.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The description of the Block.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The IDs of the Blocks that must finish before this Block.
+	DependsOn []string `protobuf:"bytes,3,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	// When the Block would be executed.
+	RunOn RunOn `protobuf:"varint,4,opt,name=run_on,json=runOn,proto3,enum=diskerase.RunOn" json:"run_on,omitempty"`
+	// The plan for each Job in the Block.
+	Jobs []*JobPlan `protobuf:"bytes,5,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *BlockPlan) Reset() {
+	*x = BlockPlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlockPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockPlan) ProtoMessage() {}
+
+func (x *BlockPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockPlan.ProtoReflect.Descriptor instead.
+func (*BlockPlan) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BlockPlan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BlockPlan) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *BlockPlan) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *BlockPlan) GetRunOn() RunOn {
+	if x != nil {
+		return x.RunOn
+	}
+	return RunOn_RunOnSuccess
+}
+
+func (x *BlockPlan) GetJobs() []*JobPlan {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// JobPlan details what a Job would do.
+type JobPlan struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the Job.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The description of the Job.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// A description of the action the Job would take.
+	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// The sites the Job would act on.
+	Sites []string `protobuf:"bytes,4,rep,name=sites,proto3" json:"sites,omitempty"`
+	// The machines the Job would act on.
+	Machines []string `protobuf:"bytes,5,rep,name=machines,proto3" json:"machines,omitempty"`
+	// If set, the Job would fail or is not valid.
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *JobPlan) Reset() {
+	*x = JobPlan{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobPlan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobPlan) ProtoMessage() {}
+
+func (x *JobPlan) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobPlan.ProtoReflect.Descriptor instead.
+func (*JobPlan) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *JobPlan) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobPlan) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *JobPlan) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *JobPlan) GetSites() []string {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+func (x *JobPlan) GetMachines() []string {
+	if x != nil {
+		return x.Machines
+	}
+	return nil
+}
+
+func (x *JobPlan) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// TemplateParam describes a parameter that a template takes.
+type TemplateParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the parameter.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A description of the parameter.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The type of the parameter.
+	Type ParamType `protobuf:"varint,3,opt,name=type,proto3,enum=diskerase.ParamType" json:"type,omitempty"`
+	// If the parameter must be provided.
+	Required bool `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+	// The value used if the parameter is not provided.
+	Default string `protobuf:"bytes,5,opt,name=default,proto3" json:"default,omitempty"`
+}
+
+func (x *TemplateParam) Reset() {
+	*x = TemplateParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateParam) ProtoMessage() {}
+
+func (x *TemplateParam) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateParam.ProtoReflect.Descriptor instead.
+func (*TemplateParam) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *TemplateParam) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateParam) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *TemplateParam) GetType() ParamType {
+	if x != nil {
+		return x.Type
+	}
+	return ParamType_ParamString
+}
+
+func (x *TemplateParam) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *TemplateParam) GetDefault() string {
+	if x != nil {
+		return x.Default
+	}
+	return ""
+}
+
+// TemplateInfo describes a workflow template registered on the server.
+type TemplateInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the template.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A description of the workflow the template builds.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The parameters the template takes.
+	Params []*TemplateParam `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty"`
+}
+
+func (x *TemplateInfo) Reset() {
+	*x = TemplateInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TemplateInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TemplateInfo) ProtoMessage() {}
+
+func (x *TemplateInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TemplateInfo.ProtoReflect.Descriptor instead.
+func (*TemplateInfo) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TemplateInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TemplateInfo) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *TemplateInfo) GetParams() []*TemplateParam {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+// SubmitTemplateReq is a request to build a WorkReq from a template.
+type SubmitTemplateReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the template.
+	Template string `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
+	// The parameters for the template, by name. Values are in the format of
+	// the parameter's type.
+	Params map[string]string `protobuf:"bytes,2,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SubmitTemplateReq) Reset() {
+	*x = SubmitTemplateReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubmitTemplateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitTemplateReq) ProtoMessage() {}
+
+func (x *SubmitTemplateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitTemplateReq.ProtoReflect.Descriptor instead.
+func (*SubmitTemplateReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SubmitTemplateReq) GetTemplate() string {
+	if x != nil {
+		return x.Template
+	}
+	return ""
+}
+
+func (x *SubmitTemplateReq) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+// ListTemplatesReq is a request to list the templates on the server.
+type ListTemplatesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListTemplatesReq) Reset() {
+	*x = ListTemplatesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTemplatesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesReq) ProtoMessage() {}
+
+func (x *ListTemplatesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesReq.ProtoReflect.Descriptor instead.
+func (*ListTemplatesReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{32}
+}
+
+// ListTemplatesResp is the list of templates on the server.
+type ListTemplatesResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The templates, sorted by name.
+	Templates []*TemplateInfo `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
+}
+
+func (x *ListTemplatesResp) Reset() {
+	*x = ListTemplatesResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListTemplatesResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTemplatesResp) ProtoMessage() {}
+
+func (x *ListTemplatesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTemplatesResp.ProtoReflect.Descriptor instead.
+func (*ListTemplatesResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListTemplatesResp) GetTemplates() []*TemplateInfo {
+	if x != nil {
+		return x.Templates
+	}
+	return nil
+}
+
+// ArgSchema describes an arg a Job takes or a setting a policy takes.
+type ArgSchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the arg. If prefix is set, this is a prefix, like "header.".
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A description of the arg.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The type of the arg.
+	Type ArgType `protobuf:"varint,3,opt,name=type,proto3,enum=diskerase.ArgType" json:"type,omitempty"`
+	// If the arg must be set.
+	Required bool `protobuf:"varint,4,opt,name=required,proto3" json:"required,omitempty"`
+	// If set, the only values the arg can have.
+	Allowed []string `protobuf:"bytes,5,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	// The value used if the arg is not set, if there is one.
+	Default string `protobuf:"bytes,6,opt,name=default,proto3" json:"default,omitempty"`
+	// If set, the arg is any arg whose name starts with name, like "header.Content-Type".
+	Prefix bool `protobuf:"varint,7,opt,name=prefix,proto3" json:"prefix,omitempty"`
+}
+
+func (x *ArgSchema) Reset() {
+	*x = ArgSchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ArgSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArgSchema) ProtoMessage() {}
+
+func (x *ArgSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArgSchema.ProtoReflect.Descriptor instead.
+func (*ArgSchema) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ArgSchema) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ArgSchema) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *ArgSchema) GetType() ArgType {
+	if x != nil {
+		return x.Type
+	}
+	return ArgType_ArgString
+}
+
+func (x *ArgSchema) GetRequired() bool {
+	if x != nil {
+		return x.Required
+	}
+	return false
+}
+
+func (x *ArgSchema) GetAllowed() []string {
+	if x != nil {
+		return x.Allowed
+	}
+	return nil
+}
+
+func (x *ArgSchema) GetDefault() string {
+	if x != nil {
+		return x.Default
+	}
+	return ""
+}
+
+func (x *ArgSchema) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
+// JobSchema describes a Job that is registered on the server.
+type JobSchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the Job, which is used in Job.name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A description of what the Job does. Empty if the Job does not describe itself.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The args the Job takes. Empty if the Job does not describe itself.
+	Args []*ArgSchema `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+}
+
+func (x *JobSchema) Reset() {
+	*x = JobSchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *JobSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobSchema) ProtoMessage() {}
+
+func (x *JobSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobSchema.ProtoReflect.Descriptor instead.
+func (*JobSchema) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *JobSchema) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobSchema) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *JobSchema) GetArgs() []*ArgSchema {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+// PolicySchema describes a policy that is registered on the server.
+type PolicySchema struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the policy, which is used in the policies config.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// A description of what the policy checks. Empty if the policy does not describe itself.
+	Desc string `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	// The settings the policy takes. Empty if the policy does not describe itself.
+	Settings []*ArgSchema `protobuf:"bytes,3,rep,name=settings,proto3" json:"settings,omitempty"`
+}
+
+func (x *PolicySchema) Reset() {
+	*x = PolicySchema{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PolicySchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PolicySchema) ProtoMessage() {}
+
+func (x *PolicySchema) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PolicySchema.ProtoReflect.Descriptor instead.
+func (*PolicySchema) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *PolicySchema) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PolicySchema) GetDesc() string {
+	if x != nil {
+		return x.Desc
+	}
+	return ""
+}
+
+func (x *PolicySchema) GetSettings() []*ArgSchema {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+// ListJobsReq is a request to list the Jobs on the server.
+type ListJobsReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListJobsReq) Reset() {
+	*x = ListJobsReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsReq) ProtoMessage() {}
+
+func (x *ListJobsReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsReq.ProtoReflect.Descriptor instead.
+func (*ListJobsReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{37}
+}
+
+// ListJobsResp is the list of Jobs on the server.
+type ListJobsResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The Jobs, sorted by name.
+	Jobs []*JobSchema `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *ListJobsResp) Reset() {
+	*x = ListJobsResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListJobsResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResp) ProtoMessage() {}
+
+func (x *ListJobsResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResp.ProtoReflect.Descriptor instead.
+func (*ListJobsResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListJobsResp) GetJobs() []*JobSchema {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+// ListPoliciesReq is a request to list the policies on the server.
+type ListPoliciesReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListPoliciesReq) Reset() {
+	*x = ListPoliciesReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPoliciesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesReq) ProtoMessage() {}
+
+func (x *ListPoliciesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesReq.ProtoReflect.Descriptor instead.
+func (*ListPoliciesReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{39}
+}
+
+// ListPoliciesResp is the list of policies on the server.
+type ListPoliciesResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The policies, sorted by name.
+	Policies []*PolicySchema `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+}
+
+func (x *ListPoliciesResp) Reset() {
+	*x = ListPoliciesResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPoliciesResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoliciesResp) ProtoMessage() {}
+
+func (x *ListPoliciesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoliciesResp.ProtoReflect.Descriptor instead.
+func (*ListPoliciesResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListPoliciesResp) GetPolicies() []*PolicySchema {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+// ESInfo is the emergency stop information for a workflow type. If any of site,
+// machine or job are set, this is a scoped entry that only applies to Jobs that match
+// all the set fields, instead of the whole workflow.
+type ESInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the workflow type, which is WorkReq.name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The emergency stop status.
+	Status ESStatus `protobuf:"varint,2,opt,name=status,proto3,enum=diskerase.ESStatus" json:"status,omitempty"`
+	// The site the entry applies to.
+	Site string `protobuf:"bytes,3,opt,name=site,proto3" json:"site,omitempty"`
+	// A path.Match() pattern for the machines the entry applies to, like "aba-00*".
+	Machine string `protobuf:"bytes,4,opt,name=machine,proto3" json:"machine,omitempty"`
+	// The type of Job the entry applies to, like "diskErase".
+	Job string `protobuf:"bytes,5,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *ESInfo) Reset() {
+	*x = ESInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESInfo) ProtoMessage() {}
+
+func (x *ESInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESInfo.ProtoReflect.Descriptor instead.
+func (*ESInfo) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ESInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ESInfo) GetStatus() ESStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ESStatus_ESUnknown
+}
+
+func (x *ESInfo) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *ESInfo) GetMachine() string {
+	if x != nil {
+		return x.Machine
+	}
+	return ""
+}
+
+func (x *ESInfo) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+// ESSetStatusReq sets the emergency stop status of a workflow type.
+type ESSetStatusReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the workflow type, which is WorkReq.name. If there is no entry
+	// with this name, one is added.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The emergency stop status to set, either ESGo or ESStop.
+	Status ESStatus `protobuf:"varint,2,opt,name=status,proto3,enum=diskerase.ESStatus" json:"status,omitempty"`
+	// If set, scopes the entry to a site. See ESInfo.
+	Site string `protobuf:"bytes,3,opt,name=site,proto3" json:"site,omitempty"`
+	// If set, scopes the entry to machines matching this pattern. See ESInfo.
+	Machine string `protobuf:"bytes,4,opt,name=machine,proto3" json:"machine,omitempty"`
+	// If set, scopes the entry to a type of Job. See ESInfo.
+	Job string `protobuf:"bytes,5,opt,name=job,proto3" json:"job,omitempty"`
+}
+
+func (x *ESSetStatusReq) Reset() {
+	*x = ESSetStatusReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESSetStatusReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESSetStatusReq) ProtoMessage() {}
+
+func (x *ESSetStatusReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESSetStatusReq.ProtoReflect.Descriptor instead.
+func (*ESSetStatusReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ESSetStatusReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ESSetStatusReq) GetStatus() ESStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ESStatus_ESUnknown
+}
+
+func (x *ESSetStatusReq) GetSite() string {
+	if x != nil {
+		return x.Site
+	}
+	return ""
+}
+
+func (x *ESSetStatusReq) GetMachine() string {
+	if x != nil {
+		return x.Machine
+	}
+	return ""
+}
+
+func (x *ESSetStatusReq) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+// ESSetStatusResp is the response from an ESSetStatusReq.
+type ESSetStatusResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ESSetStatusResp) Reset() {
+	*x = ESSetStatusResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESSetStatusResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESSetStatusResp) ProtoMessage() {}
+
+func (x *ESSetStatusResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESSetStatusResp.ProtoReflect.Descriptor instead.
+func (*ESSetStatusResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{43}
+}
+
+// ESGetStatusReq requests the emergency stop status of a workflow type.
+type ESGetStatusReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the workflow type, which is WorkReq.name.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *ESGetStatusReq) Reset() {
+	*x = ESGetStatusReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESGetStatusReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESGetStatusReq) ProtoMessage() {}
+
+func (x *ESGetStatusReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESGetStatusReq.ProtoReflect.Descriptor instead.
+func (*ESGetStatusReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ESGetStatusReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// ESGetStatusResp is the response from an ESGetStatusReq.
+type ESGetStatusResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The emergency stop information.
+	Info *ESInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *ESGetStatusResp) Reset() {
+	*x = ESGetStatusResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESGetStatusResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESGetStatusResp) ProtoMessage() {}
+
+func (x *ESGetStatusResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESGetStatusResp.ProtoReflect.Descriptor instead.
+func (*ESGetStatusResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ESGetStatusResp) GetInfo() *ESInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+// ESListReq requests all emergency stop entries.
+type ESListReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ESListReq) Reset() {
+	*x = ESListReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESListReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESListReq) ProtoMessage() {}
+
+func (x *ESListReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESListReq.ProtoReflect.Descriptor instead.
+func (*ESListReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{46}
+}
+
+// ESListResp is the response from an ESListReq.
+type ESListResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// All emergency stop entries, sorted by name with the unscoped entry first.
+	Infos []*ESInfo `protobuf:"bytes,1,rep,name=infos,proto3" json:"infos,omitempty"`
+}
+
+func (x *ESListResp) Reset() {
+	*x = ESListResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ESListResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ESListResp) ProtoMessage() {}
+
+func (x *ESListResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ESListResp.ProtoReflect.Descriptor instead.
+func (*ESListResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ESListResp) GetInfos() []*ESInfo {
+	if x != nil {
+		return x.Infos
+	}
+	return nil
+}
+
+// TBState is the state of a token bucket that is persisted in storage, so that a restart
+// does not refill the bucket.
+type TBState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The number of tokens available at last_refill.
+	Available int32 `protobuf:"varint,1,opt,name=available,proto3" json:"available,omitempty"`
+	// When tokens were last added to the bucket. Tokens are added every interval
+	// after this.
+	LastRefill *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_refill,json=lastRefill,proto3" json:"last_refill,omitempty"`
+}
+
+func (x *TBState) Reset() {
+	*x = TBState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBState) ProtoMessage() {}
+
+func (x *TBState) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBState.ProtoReflect.Descriptor instead.
+func (*TBState) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *TBState) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *TBState) GetLastRefill() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRefill
+	}
+	return nil
+}
+
+// TBInfo is information about a token bucket.
+type TBInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the bucket, which is used in the tokenBucket Job's "bucket" arg.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The most tokens the bucket can hold.
+	Size int32 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// How many tokens are added every interval.
+	Incr int32 `protobuf:"varint,3,opt,name=incr,proto3" json:"incr,omitempty"`
+	// How often tokens are added.
+	Interval *durationpb.Duration `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	// The number of tokens that are available now.
+	Available int32 `protobuf:"varint,5,opt,name=available,proto3" json:"available,omitempty"`
+	// When tokens were last added to the bucket.
+	LastRefill *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_refill,json=lastRefill,proto3" json:"last_refill,omitempty"`
+	// When tokens will next be added to the bucket.
+	NextRefill *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_refill,json=nextRefill,proto3" json:"next_refill,omitempty"`
+}
+
+func (x *TBInfo) Reset() {
+	*x = TBInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBInfo) ProtoMessage() {}
+
+func (x *TBInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBInfo.ProtoReflect.Descriptor instead.
+func (*TBInfo) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TBInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TBInfo) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TBInfo) GetIncr() int32 {
+	if x != nil {
+		return x.Incr
+	}
+	return 0
+}
+
+func (x *TBInfo) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *TBInfo) GetAvailable() int32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *TBInfo) GetLastRefill() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRefill
+	}
+	return nil
+}
+
+func (x *TBInfo) GetNextRefill() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRefill
+	}
+	return nil
+}
+
+// TBSetReq changes the tokens in a token bucket.
+type TBSetReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The name of the bucket.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The change to make.
+	Action TBAction `protobuf:"varint,2,opt,name=action,proto3,enum=diskerase.TBAction" json:"action,omitempty"`
+}
+
+func (x *TBSetReq) Reset() {
+	*x = TBSetReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBSetReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBSetReq) ProtoMessage() {}
+
+func (x *TBSetReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBSetReq.ProtoReflect.Descriptor instead.
+func (*TBSetReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *TBSetReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TBSetReq) GetAction() TBAction {
+	if x != nil {
+		return x.Action
+	}
+	return TBAction_TBUnknown
+}
+
+// TBSetResp is the response from a TBSetReq.
+type TBSetResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The bucket after the change.
+	Info *TBInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *TBSetResp) Reset() {
+	*x = TBSetResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBSetResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBSetResp) ProtoMessage() {}
+
+func (x *TBSetResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBSetResp.ProtoReflect.Descriptor instead.
+func (*TBSetResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *TBSetResp) GetInfo() *TBInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+// TBListReq requests all token buckets.
+type TBListReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *TBListReq) Reset() {
+	*x = TBListReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBListReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBListReq) ProtoMessage() {}
+
+func (x *TBListReq) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBListReq.ProtoReflect.Descriptor instead.
+func (*TBListReq) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{52}
+}
+
+// TBListResp is the response from a TBListReq.
+type TBListResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// All token buckets, sorted by name.
+	Infos []*TBInfo `protobuf:"bytes,1,rep,name=infos,proto3" json:"infos,omitempty"`
+}
+
+func (x *TBListResp) Reset() {
+	*x = TBListResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_diskerase_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TBListResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TBListResp) ProtoMessage() {}
+
+func (x *TBListResp) ProtoReflect() protoreflect.Message {
+	mi := &file_diskerase_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TBListResp.ProtoReflect.Descriptor instead.
+func (*TBListResp) Descriptor() ([]byte, []int) {
+	return file_diskerase_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *TBListResp) GetInfos() []*TBInfo {
+	if x != nil {
+		return x.Infos
+	}
+	return nil
+}
+
+var File_diskerase_proto protoreflect.FileDescriptor
+
+var file_diskerase_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x09, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x1a, 0x1e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcf, 0x01,
+	0x0a, 0x07, 0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73,
+	0x63, 0x12, 0x28, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x09, 0x61, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61,
+	0x6c, 0x52, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x63, 0x65, 0x22,
+	0x1a, 0x0a, 0x08, 0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xb6, 0x01, 0x0a, 0x05,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x61, 0x74,
+	0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x22, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61,
+	0x73, 0x65, 0x2e, 0x4a, 0x6f, 0x62, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a,
+	0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x09, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x4f, 0x6e, 0x12, 0x27, 0x0a, 0x06, 0x72,
+	0x75, 0x6e, 0x5f, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x52, 0x75, 0x6e, 0x4f, 0x6e, 0x52, 0x05, 0x72,
+	0x75, 0x6e, 0x4f, 0x6e, 0x22, 0xb6, 0x02, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x64, 0x65, 0x73, 0x63, 0x12, 0x2c, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4a,
+	0x6f, 0x62, 0x2e, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x04, 0x61, 0x72,
+	0x67, 0x73, 0x12, 0x39, 0x0a, 0x0c, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x33, 0x0a,
+	0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x12, 0x30, 0x0a, 0x09, 0x6f, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x4f, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x52, 0x08, 0x6f, 0x6e, 0x4c, 0x6f,
+	0x63, 0x6b, 0x65, 0x64, 0x1a, 0x37, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xfe, 0x01,
+	0x0a, 0x0b, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x21, 0x0a,
+	0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x12, 0x42, 0x0a, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x63, 0x6b,
+	0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x42, 0x61, 0x63,
+	0x6b, 0x6f, 0x66, 0x66, 0x12, 0x3a, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61, 0x63, 0x6b,
+	0x6f, 0x66, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66,
+	0x12, 0x29, 0x0a, 0x10, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x74, 0x72,
+	0x79, 0x61, 0x62, 0x6c, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x66,
+	0x61, 0x74, 0x61, 0x6c, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x19,
+	0x0a, 0x07, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x0a, 0x0a, 0x08, 0x45, 0x78, 0x65,
+	0x63, 0x52, 0x65, 0x73, 0x70, 0x22, 0x1b, 0x0a, 0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52,
+	0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x0c, 0x0a, 0x0a, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70,
+	0x22, 0x1a, 0x0a, 0x08, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x0b, 0x0a, 0x09,
+	0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22, 0x1b, 0x0a, 0x09, 0x52, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x0c, 0x0a, 0x0a, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x22, 0x36, 0x0a, 0x0a, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x52,
+	0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x40, 0x0a, 0x0b,
+	0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x12, 0x31, 0x0a, 0x09, 0x61,
+	0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x61, 0x6c, 0x52, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x22, 0x70,
+	0x0a, 0x08, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61, 0x70,
+	0x70, 0x72, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x22, 0x1a, 0x0a, 0x08, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x71, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3d, 0x0a, 0x09,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x30, 0x0a, 0x07, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x52, 0x07, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x22, 0xd3, 0x02, 0x0a, 0x0b,
+	0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x6c, 0x6c, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x6c, 0x6f,
+	0x63, 0x6b, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x12,
+	0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x6a, 0x6f,
+	0x62, 0x12, 0x25, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x21, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x24, 0x0a, 0x0e, 0x77,
+	0x61, 0x73, 0x5f, 0x65, 0x73, 0x5f, 0x73, 0x74, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x77, 0x61, 0x73, 0x45, 0x73, 0x53, 0x74, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x22, 0x1b, 0x0a, 0x09, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xf6,
+	0x02, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x2e, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x64, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12,
+	0x24, 0x0a, 0x0e, 0x77, 0x61, 0x73, 0x5f, 0x65, 0x73, 0x5f, 0x73, 0x74, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x77, 0x61, 0x73, 0x45, 0x73, 0x53, 0x74,
+	0x6f, 0x70, 0x70, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x31, 0x0a, 0x09,
+	0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x61, 0x6c, 0x52, 0x09, 0x61, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x12,
+	0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61,
+	0x75, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x42, 0x79, 0x22, 0xa3, 0x01, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63,
 	0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x29, 0x0a, 0x06, 0x73,
 	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69,
@@ -790,7 +4196,8 @@ var file_diskerase_proto_rawDesc = []byte{
 	0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x68, 0x61, 0x73, 0x45, 0x72,
 	0x72, 0x6f, 0x72, 0x12, 0x28, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28,
 	0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4a, 0x6f,
-	0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x22, 0xe1, 0x01,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xdb, 0x02,
 	0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e,
 	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
 	0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64,
@@ -801,34 +4208,336 @@ var file_diskerase_proto_rawDesc = []byte{
 	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
 	0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
 	0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x1a, 0x37, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x73,
-	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
-	0x01, 0x2a, 0x6b, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11, 0x0a, 0x0d, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x14,
-	0x0a, 0x10, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4e, 0x6f, 0x74, 0x53, 0x74, 0x61, 0x72, 0x74,
-	0x65, 0x64, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x75,
-	0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10, 0x04, 0x32, 0xab,
-	0x01, 0x0a, 0x08, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x33, 0x0a, 0x06, 0x53,
-	0x75, 0x62, 0x6d, 0x69, 0x74, 0x12, 0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
-	0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b,
-	0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00,
-	0x12, 0x31, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63, 0x12, 0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
-	0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64,
-	0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x73,
-	0x70, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x2e,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x5f,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6f,
+	0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x61, 0x69, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x6f,
+	0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x77, 0x61, 0x69, 0x74, 0x69, 0x6e, 0x67,
+	0x4f, 0x6e, 0x1a, 0x37, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x94, 0x02, 0x0a, 0x07,
+	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x08, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x65, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
+	0x0e, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12,
+	0x45, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f, 0x62, 0x65, 0x66,
+	0x6f, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0f, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64,
+	0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x6c, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x38,
+	0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74,
+	0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0xeb, 0x01, 0x0a, 0x0f, 0x57, 0x6f, 0x72, 0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x53, 0x75, 0x6d,
+	0x6d, 0x61, 0x72, 0x79, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x38, 0x0a, 0x09,
+	0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x75, 0x62,
+	0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61,
+	0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x64, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x72, 0x22, 0x60,
+	0x0a, 0x08, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x73, 0x12, 0x2c, 0x0a, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x42,
+	0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x06, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x22, 0x9f, 0x01, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65,
+	0x73, 0x63, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x5f, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x4f,
+	0x6e, 0x12, 0x27, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x10, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x52, 0x75,
+	0x6e, 0x4f, 0x6e, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x4f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x6a, 0x6f,
+	0x62, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x04, 0x6a, 0x6f,
+	0x62, 0x73, 0x22, 0x91, 0x01, 0x0a, 0x07, 0x4a, 0x6f, 0x62, 0x50, 0x6c, 0x61, 0x6e, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x69, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x97, 0x01, 0x0a, 0x0d, 0x54, 0x65, 0x6d, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63,
+	0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74,
+	0x22, 0x68, 0x0a, 0x0c, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x30, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0xac, 0x01, 0x0a, 0x11, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
+	0x12, 0x1a, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x40, 0x0a, 0x06,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x39,
+	0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x12, 0x0a, 0x10, 0x4c, 0x69, 0x73,
+	0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x22, 0x4a, 0x0a,
+	0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x35, 0x0a, 0x09, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x09,
+	0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x22, 0xc3, 0x01, 0x0a, 0x09, 0x41, 0x72,
+	0x67, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12,
+	0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x72, 0x67, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22,
+	0x5d, 0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x64, 0x65, 0x73, 0x63, 0x12, 0x28, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41,
+	0x72, 0x67, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x22, 0x68,
+	0x0a, 0x0c, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x30, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x72, 0x67, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x08,
+	0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x0d, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74,
+	0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x71, 0x22, 0x38, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x4a,
+	0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x28, 0x0a, 0x04, 0x6a, 0x6f, 0x62, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x04, 0x6a, 0x6f, 0x62,
+	0x73, 0x22, 0x11, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x22, 0x47, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x33, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69,
+	0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x53, 0x63, 0x68,
+	0x65, 0x6d, 0x61, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x89, 0x01,
+	0x0a, 0x06, 0x45, 0x53, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22, 0x91, 0x01, 0x0a, 0x0e, 0x45, 0x53,
+	0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x2b, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69, 0x74,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6a,
+	0x6f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x22, 0x11, 0x0a,
+	0x0f, 0x45, 0x53, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x22, 0x24, 0x0a, 0x0e, 0x45, 0x53, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x71, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x38, 0x0a, 0x0f, 0x45, 0x53, 0x47, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x25, 0x0a, 0x04, 0x69, 0x6e, 0x66,
+	0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
+	0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f,
+	0x22, 0x0b, 0x0a, 0x09, 0x45, 0x53, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x22, 0x35, 0x0a,
+	0x0a, 0x45, 0x53, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x27, 0x0a, 0x05, 0x69,
+	0x6e, 0x66, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x69,
+	0x6e, 0x66, 0x6f, 0x73, 0x22, 0x64, 0x0a, 0x07, 0x54, 0x42, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x09, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x3b, 0x0a,
+	0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72, 0x65, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x66, 0x69, 0x6c, 0x6c, 0x22, 0x93, 0x02, 0x0a, 0x06, 0x54,
+	0x42, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x69, 0x6e, 0x63, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x69, 0x6e, 0x63,
+	0x72, 0x12, 0x35, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x76, 0x61, 0x69,
+	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x61, 0x76, 0x61,
+	0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x72,
+	0x65, 0x66, 0x69, 0x6c, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6c, 0x61, 0x73, 0x74, 0x52, 0x65, 0x66,
+	0x69, 0x6c, 0x6c, 0x12, 0x3b, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x72, 0x65, 0x66, 0x69,
+	0x6c, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x52, 0x65, 0x66, 0x69, 0x6c, 0x6c,
+	0x22, 0x4b, 0x0a, 0x08, 0x54, 0x42, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x2b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x32, 0x0a,
+	0x09, 0x54, 0x42, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x25, 0x0a, 0x04, 0x69, 0x6e,
+	0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66,
+	0x6f, 0x22, 0x0b, 0x0a, 0x09, 0x54, 0x42, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x22, 0x35,
+	0x0a, 0x0a, 0x54, 0x42, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x12, 0x27, 0x0a, 0x05,
+	0x69, 0x6e, 0x66, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05,
+	0x69, 0x6e, 0x66, 0x6f, 0x73, 0x2a, 0x3c, 0x0a, 0x05, 0x52, 0x75, 0x6e, 0x4f, 0x6e, 0x12, 0x10,
+	0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x4f, 0x6e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x10, 0x00,
+	0x12, 0x10, 0x0a, 0x0c, 0x52, 0x75, 0x6e, 0x4f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x75, 0x6e, 0x4f, 0x6e, 0x41, 0x6c, 0x77, 0x61, 0x79,
+	0x73, 0x10, 0x02, 0x2a, 0x2e, 0x0a, 0x08, 0x4f, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x12,
+	0x10, 0x0a, 0x0c, 0x4f, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x57, 0x61, 0x69, 0x74, 0x10,
+	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x65, 0x64, 0x46, 0x61, 0x69,
+	0x6c, 0x10, 0x01, 0x2a, 0xa5, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11,
+	0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10,
+	0x00, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4e, 0x6f, 0x74, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x65, 0x64, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x75, 0x6e, 0x6e, 0x69, 0x6e, 0x67, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x10,
+	0x04, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x50, 0x61, 0x75, 0x73, 0x65,
+	0x64, 0x10, 0x05, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x10, 0x06, 0x12, 0x11, 0x0a, 0x0d, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x53, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x10, 0x07, 0x2a, 0x4c, 0x0a, 0x09, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x49, 0x6e, 0x74, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x42, 0x6f, 0x6f, 0x6c, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x03, 0x2a, 0x76, 0x0a, 0x07, 0x41, 0x72, 0x67,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e,
+	0x67, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x72, 0x67, 0x49, 0x6e, 0x74, 0x10, 0x01, 0x12,
+	0x0b, 0x0a, 0x07, 0x41, 0x72, 0x67, 0x42, 0x6f, 0x6f, 0x6c, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b,
+	0x41, 0x72, 0x67, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x10, 0x03, 0x12, 0x11, 0x0a,
+	0x0d, 0x41, 0x72, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4c, 0x69, 0x73, 0x74, 0x10, 0x04,
+	0x12, 0x10, 0x0a, 0x0c, 0x41, 0x72, 0x67, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x61, 0x70,
+	0x10, 0x05, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x72, 0x67, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x10,
+	0x06, 0x2a, 0x2f, 0x0a, 0x08, 0x45, 0x53, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0d, 0x0a,
+	0x09, 0x45, 0x53, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04,
+	0x45, 0x53, 0x47, 0x6f, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x53, 0x53, 0x74, 0x6f, 0x70,
+	0x10, 0x02, 0x2a, 0x34, 0x0a, 0x08, 0x54, 0x42, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0d,
+	0x0a, 0x09, 0x54, 0x42, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x10, 0x00, 0x12, 0x0b, 0x0a,
+	0x07, 0x54, 0x42, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x54, 0x42,
+	0x52, 0x65, 0x66, 0x69, 0x6c, 0x6c, 0x10, 0x02, 0x32, 0x84, 0x07, 0x0a, 0x08, 0x57, 0x6f, 0x72,
+	0x6b, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x33, 0x0a, 0x06, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x12,
+	0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b,
+	0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x0e, 0x53, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x64,
+	0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x22,
+	0x00, 0x12, 0x4c, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x73, 0x12, 0x1b, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x1c, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12,
+	0x3d, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x12, 0x16, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73,
+	0x52, 0x65, 0x71, 0x1a, 0x17, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x4a, 0x6f, 0x62, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x49,
+	0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x12, 0x1a,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1b, 0x2e, 0x64, 0x69, 0x73,
+	0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x04, 0x50, 0x6c, 0x61,
+	0x6e, 0x12, 0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x57, 0x6f,
+	0x72, 0x6b, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x50, 0x6c, 0x61, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x07,
+	0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x12, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
+	0x61, 0x73, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x16,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x04, 0x45, 0x78, 0x65, 0x63,
+	0x12, 0x12, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x78, 0x65,
+	0x63, 0x52, 0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65,
+	0x2e, 0x45, 0x78, 0x65, 0x63, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x00, 0x12, 0x38, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x2e,
 	0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
 	0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x42, 0x4f, 0x5a, 0x4d,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x50, 0x61, 0x63, 0x6b, 0x74,
-	0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x2f, 0x47, 0x6f, 0x2d, 0x66, 0x6f,
-	0x72, 0x2d, 0x44, 0x65, 0x76, 0x4f, 0x70, 0x73, 0x2f, 0x63, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72,
-	0x2f, 0x31, 0x38, 0x2f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x30, 0x01, 0x12, 0x37,
+	0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65,
+	0x72, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x1a, 0x15,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x05, 0x50, 0x61, 0x75, 0x73, 0x65,
+	0x12, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x50, 0x61, 0x75,
+	0x73, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x50, 0x61, 0x75, 0x73, 0x65, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x37, 0x0a,
+	0x06, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
+	0x61, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e,
+	0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x12,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x1a, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x05, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x12, 0x13, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x41,
+	0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72,
+	0x61, 0x73, 0x65, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x32,
+	0xd2, 0x01, 0x0a, 0x0d, 0x45, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x6e, 0x63, 0x79, 0x53, 0x74, 0x6f,
+	0x70, 0x12, 0x44, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x19,
+	0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x53, 0x65, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x1a, 0x1a, 0x2e, 0x64, 0x69, 0x73, 0x6b,
+	0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x53, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x44, 0x0a, 0x09, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x19, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65,
+	0x2e, 0x45, 0x53, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x1a,
+	0x1a, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x35, 0x0a,
+	0x04, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73,
+	0x65, 0x2e, 0x45, 0x53, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x53, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x22, 0x00, 0x32, 0x79, 0x0a, 0x0c, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x03, 0x53, 0x65, 0x74, 0x12, 0x13, 0x2e, 0x64, 0x69,
+	0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x1a, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x53,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x12, 0x35, 0x0a, 0x04, 0x4c, 0x69, 0x73, 0x74,
+	0x12, 0x14, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65, 0x2e, 0x54, 0x42, 0x4c,
+	0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x1a, 0x15, 0x2e, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61,
+	0x73, 0x65, 0x2e, 0x54, 0x42, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x22, 0x00, 0x42,
+	0x4f, 0x5a, 0x4d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x50, 0x61,
+	0x63, 0x6b, 0x74, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x69, 0x6e, 0x67, 0x2f, 0x47, 0x6f,
+	0x2d, 0x66, 0x6f, 0x72, 0x2d, 0x44, 0x65, 0x76, 0x4f, 0x70, 0x73, 0x2f, 0x63, 0x68, 0x61, 0x70,
+	0x74, 0x65, 0x72, 0x2f, 0x31, 0x38, 0x2f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x64, 0x69, 0x73, 0x6b, 0x65, 0x72, 0x61, 0x73, 0x65,
+	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -843,44 +4552,174 @@ func file_diskerase_proto_rawDescGZIP() []byte {
 	return file_diskerase_proto_rawDescData
 }
 
-var file_diskerase_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_diskerase_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_diskerase_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_diskerase_proto_msgTypes = make([]protoimpl.MessageInfo, 57)
 var file_diskerase_proto_goTypes = []interface{}{
-	(Status)(0),         // 0: diskerase.Status
-	(*WorkReq)(nil),     // 1: diskerase.WorkReq
-	(*WorkResp)(nil),    // 2: diskerase.WorkResp
-	(*Block)(nil),       // 3: diskerase.Block
-	(*Job)(nil),         // 4: diskerase.Job
-	(*ExecReq)(nil),     // 5: diskerase.ExecReq
-	(*ExecResp)(nil),    // 6: diskerase.ExecResp
-	(*StatusReq)(nil),   // 7: diskerase.StatusReq
-	(*StatusResp)(nil),  // 8: diskerase.StatusResp
-	(*BlockStatus)(nil), // 9: diskerase.BlockStatus
-	(*JobStatus)(nil),   // 10: diskerase.JobStatus
-	nil,                 // 11: diskerase.Job.ArgsEntry
-	nil,                 // 12: diskerase.JobStatus.ArgsEntry
+	(RunOn)(0),                    // 0: diskerase.RunOn
+	(OnLocked)(0),                 // 1: diskerase.OnLocked
+	(Status)(0),                   // 2: diskerase.Status
+	(ParamType)(0),                // 3: diskerase.ParamType
+	(ArgType)(0),                  // 4: diskerase.ArgType
+	(ESStatus)(0),                 // 5: diskerase.ESStatus
+	(TBAction)(0),                 // 6: diskerase.TBAction
+	(*WorkReq)(nil),               // 7: diskerase.WorkReq
+	(*WorkResp)(nil),              // 8: diskerase.WorkResp
+	(*Block)(nil),                 // 9: diskerase.Block
+	(*Job)(nil),                   // 10: diskerase.Job
+	(*RetryPolicy)(nil),           // 11: diskerase.RetryPolicy
+	(*ExecReq)(nil),               // 12: diskerase.ExecReq
+	(*ExecResp)(nil),              // 13: diskerase.ExecResp
+	(*CancelReq)(nil),             // 14: diskerase.CancelReq
+	(*CancelResp)(nil),            // 15: diskerase.CancelResp
+	(*PauseReq)(nil),              // 16: diskerase.PauseReq
+	(*PauseResp)(nil),             // 17: diskerase.PauseResp
+	(*ResumeReq)(nil),             // 18: diskerase.ResumeReq
+	(*ResumeResp)(nil),            // 19: diskerase.ResumeResp
+	(*ApproveReq)(nil),            // 20: diskerase.ApproveReq
+	(*ApproveResp)(nil),           // 21: diskerase.ApproveResp
+	(*Approval)(nil),              // 22: diskerase.Approval
+	(*AuditReq)(nil),              // 23: diskerase.AuditReq
+	(*AuditResp)(nil),             // 24: diskerase.AuditResp
+	(*AuditRecord)(nil),           // 25: diskerase.AuditRecord
+	(*StatusReq)(nil),             // 26: diskerase.StatusReq
+	(*StatusResp)(nil),            // 27: diskerase.StatusResp
+	(*BlockStatus)(nil),           // 28: diskerase.BlockStatus
+	(*JobStatus)(nil),             // 29: diskerase.JobStatus
+	(*ListReq)(nil),               // 30: diskerase.ListReq
+	(*ListResp)(nil),              // 31: diskerase.ListResp
+	(*WorkflowSummary)(nil),       // 32: diskerase.WorkflowSummary
+	(*PlanResp)(nil),              // 33: diskerase.PlanResp
+	(*BlockPlan)(nil),             // 34: diskerase.BlockPlan
+	(*JobPlan)(nil),               // 35: diskerase.JobPlan
+	(*TemplateParam)(nil),         // 36: diskerase.TemplateParam
+	(*TemplateInfo)(nil),          // 37: diskerase.TemplateInfo
+	(*SubmitTemplateReq)(nil),     // 38: diskerase.SubmitTemplateReq
+	(*ListTemplatesReq)(nil),      // 39: diskerase.ListTemplatesReq
+	(*ListTemplatesResp)(nil),     // 40: diskerase.ListTemplatesResp
+	(*ArgSchema)(nil),             // 41: diskerase.ArgSchema
+	(*JobSchema)(nil),             // 42: diskerase.JobSchema
+	(*PolicySchema)(nil),          // 43: diskerase.PolicySchema
+	(*ListJobsReq)(nil),           // 44: diskerase.ListJobsReq
+	(*ListJobsResp)(nil),          // 45: diskerase.ListJobsResp
+	(*ListPoliciesReq)(nil),       // 46: diskerase.ListPoliciesReq
+	(*ListPoliciesResp)(nil),      // 47: diskerase.ListPoliciesResp
+	(*ESInfo)(nil),                // 48: diskerase.ESInfo
+	(*ESSetStatusReq)(nil),        // 49: diskerase.ESSetStatusReq
+	(*ESSetStatusResp)(nil),       // 50: diskerase.ESSetStatusResp
+	(*ESGetStatusReq)(nil),        // 51: diskerase.ESGetStatusReq
+	(*ESGetStatusResp)(nil),       // 52: diskerase.ESGetStatusResp
+	(*ESListReq)(nil),             // 53: diskerase.ESListReq
+	(*ESListResp)(nil),            // 54: diskerase.ESListResp
+	(*TBState)(nil),               // 55: diskerase.TBState
+	(*TBInfo)(nil),                // 56: diskerase.TBInfo
+	(*TBSetReq)(nil),              // 57: diskerase.TBSetReq
+	(*TBSetResp)(nil),             // 58: diskerase.TBSetResp
+	(*TBListReq)(nil),             // 59: diskerase.TBListReq
+	(*TBListResp)(nil),            // 60: diskerase.TBListResp
+	nil,                           // 61: diskerase.Job.ArgsEntry
+	nil,                           // 62: diskerase.JobStatus.ArgsEntry
+	nil,                           // 63: diskerase.SubmitTemplateReq.ParamsEntry
+	(*durationpb.Duration)(nil),   // 64: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 65: google.protobuf.Timestamp
 }
 var file_diskerase_proto_depIdxs = []int32{
-	3,  // 0: diskerase.WorkReq.blocks:type_name -> diskerase.Block
-	4,  // 1: diskerase.Block.jobs:type_name -> diskerase.Job
-	11, // 2: diskerase.Job.args:type_name -> diskerase.Job.ArgsEntry
-	0,  // 3: diskerase.StatusResp.status:type_name -> diskerase.Status
-	9,  // 4: diskerase.StatusResp.blocks:type_name -> diskerase.BlockStatus
-	0,  // 5: diskerase.BlockStatus.status:type_name -> diskerase.Status
-	10, // 6: diskerase.BlockStatus.jobs:type_name -> diskerase.JobStatus
-	12, // 7: diskerase.JobStatus.args:type_name -> diskerase.JobStatus.ArgsEntry
-	0,  // 8: diskerase.JobStatus.status:type_name -> diskerase.Status
-	1,  // 9: diskerase.Workflow.Submit:input_type -> diskerase.WorkReq
-	5,  // 10: diskerase.Workflow.Exec:input_type -> diskerase.ExecReq
-	7,  // 11: diskerase.Workflow.Status:input_type -> diskerase.StatusReq
-	2,  // 12: diskerase.Workflow.Submit:output_type -> diskerase.WorkResp
-	6,  // 13: diskerase.Workflow.Exec:output_type -> diskerase.ExecResp
-	8,  // 14: diskerase.Workflow.Status:output_type -> diskerase.StatusResp
-	12, // [12:15] is the sub-list for method output_type
-	9,  // [9:12] is the sub-list for method input_type
-	9,  // [9:9] is the sub-list for extension type_name
-	9,  // [9:9] is the sub-list for extension extendee
-	0,  // [0:9] is the sub-list for field type_name
+	9,  // 0: diskerase.WorkReq.blocks:type_name -> diskerase.Block
+	22, // 1: diskerase.WorkReq.approvals:type_name -> diskerase.Approval
+	10, // 2: diskerase.Block.jobs:type_name -> diskerase.Job
+	0,  // 3: diskerase.Block.run_on:type_name -> diskerase.RunOn
+	61, // 4: diskerase.Job.args:type_name -> diskerase.Job.ArgsEntry
+	11, // 5: diskerase.Job.retry_policy:type_name -> diskerase.RetryPolicy
+	64, // 6: diskerase.Job.timeout:type_name -> google.protobuf.Duration
+	1,  // 7: diskerase.Job.on_locked:type_name -> diskerase.OnLocked
+	64, // 8: diskerase.RetryPolicy.initial_backoff:type_name -> google.protobuf.Duration
+	64, // 9: diskerase.RetryPolicy.max_backoff:type_name -> google.protobuf.Duration
+	22, // 10: diskerase.ApproveResp.approvals:type_name -> diskerase.Approval
+	65, // 11: diskerase.Approval.time:type_name -> google.protobuf.Timestamp
+	25, // 12: diskerase.AuditResp.records:type_name -> diskerase.AuditRecord
+	65, // 13: diskerase.AuditRecord.time:type_name -> google.protobuf.Timestamp
+	2,  // 14: diskerase.AuditRecord.from:type_name -> diskerase.Status
+	2,  // 15: diskerase.AuditRecord.to:type_name -> diskerase.Status
+	2,  // 16: diskerase.StatusResp.status:type_name -> diskerase.Status
+	28, // 17: diskerase.StatusResp.blocks:type_name -> diskerase.BlockStatus
+	22, // 18: diskerase.StatusResp.approvals:type_name -> diskerase.Approval
+	2,  // 19: diskerase.BlockStatus.status:type_name -> diskerase.Status
+	29, // 20: diskerase.BlockStatus.jobs:type_name -> diskerase.JobStatus
+	62, // 21: diskerase.JobStatus.args:type_name -> diskerase.JobStatus.ArgsEntry
+	2,  // 22: diskerase.JobStatus.status:type_name -> diskerase.Status
+	2,  // 23: diskerase.ListReq.statuses:type_name -> diskerase.Status
+	65, // 24: diskerase.ListReq.submitted_after:type_name -> google.protobuf.Timestamp
+	65, // 25: diskerase.ListReq.submitted_before:type_name -> google.protobuf.Timestamp
+	32, // 26: diskerase.ListResp.workflows:type_name -> diskerase.WorkflowSummary
+	65, // 27: diskerase.WorkflowSummary.submitted:type_name -> google.protobuf.Timestamp
+	2,  // 28: diskerase.WorkflowSummary.status:type_name -> diskerase.Status
+	34, // 29: diskerase.PlanResp.blocks:type_name -> diskerase.BlockPlan
+	0,  // 30: diskerase.BlockPlan.run_on:type_name -> diskerase.RunOn
+	35, // 31: diskerase.BlockPlan.jobs:type_name -> diskerase.JobPlan
+	3,  // 32: diskerase.TemplateParam.type:type_name -> diskerase.ParamType
+	36, // 33: diskerase.TemplateInfo.params:type_name -> diskerase.TemplateParam
+	63, // 34: diskerase.SubmitTemplateReq.params:type_name -> diskerase.SubmitTemplateReq.ParamsEntry
+	37, // 35: diskerase.ListTemplatesResp.templates:type_name -> diskerase.TemplateInfo
+	4,  // 36: diskerase.ArgSchema.type:type_name -> diskerase.ArgType
+	41, // 37: diskerase.JobSchema.args:type_name -> diskerase.ArgSchema
+	41, // 38: diskerase.PolicySchema.settings:type_name -> diskerase.ArgSchema
+	42, // 39: diskerase.ListJobsResp.jobs:type_name -> diskerase.JobSchema
+	43, // 40: diskerase.ListPoliciesResp.policies:type_name -> diskerase.PolicySchema
+	5,  // 41: diskerase.ESInfo.status:type_name -> diskerase.ESStatus
+	5,  // 42: diskerase.ESSetStatusReq.status:type_name -> diskerase.ESStatus
+	48, // 43: diskerase.ESGetStatusResp.info:type_name -> diskerase.ESInfo
+	48, // 44: diskerase.ESListResp.infos:type_name -> diskerase.ESInfo
+	65, // 45: diskerase.TBState.last_refill:type_name -> google.protobuf.Timestamp
+	64, // 46: diskerase.TBInfo.interval:type_name -> google.protobuf.Duration
+	65, // 47: diskerase.TBInfo.last_refill:type_name -> google.protobuf.Timestamp
+	65, // 48: diskerase.TBInfo.next_refill:type_name -> google.protobuf.Timestamp
+	6,  // 49: diskerase.TBSetReq.action:type_name -> diskerase.TBAction
+	56, // 50: diskerase.TBSetResp.info:type_name -> diskerase.TBInfo
+	56, // 51: diskerase.TBListResp.infos:type_name -> diskerase.TBInfo
+	7,  // 52: diskerase.Workflow.Submit:input_type -> diskerase.WorkReq
+	38, // 53: diskerase.Workflow.SubmitTemplate:input_type -> diskerase.SubmitTemplateReq
+	39, // 54: diskerase.Workflow.ListTemplates:input_type -> diskerase.ListTemplatesReq
+	44, // 55: diskerase.Workflow.ListJobs:input_type -> diskerase.ListJobsReq
+	46, // 56: diskerase.Workflow.ListPolicies:input_type -> diskerase.ListPoliciesReq
+	7,  // 57: diskerase.Workflow.Plan:input_type -> diskerase.WorkReq
+	20, // 58: diskerase.Workflow.Approve:input_type -> diskerase.ApproveReq
+	12, // 59: diskerase.Workflow.Exec:input_type -> diskerase.ExecReq
+	26, // 60: diskerase.Workflow.Status:input_type -> diskerase.StatusReq
+	26, // 61: diskerase.Workflow.Watch:input_type -> diskerase.StatusReq
+	14, // 62: diskerase.Workflow.Cancel:input_type -> diskerase.CancelReq
+	16, // 63: diskerase.Workflow.Pause:input_type -> diskerase.PauseReq
+	18, // 64: diskerase.Workflow.Resume:input_type -> diskerase.ResumeReq
+	30, // 65: diskerase.Workflow.List:input_type -> diskerase.ListReq
+	23, // 66: diskerase.Workflow.Audit:input_type -> diskerase.AuditReq
+	49, // 67: diskerase.EmergencyStop.SetStatus:input_type -> diskerase.ESSetStatusReq
+	51, // 68: diskerase.EmergencyStop.GetStatus:input_type -> diskerase.ESGetStatusReq
+	53, // 69: diskerase.EmergencyStop.List:input_type -> diskerase.ESListReq
+	57, // 70: diskerase.TokenBuckets.Set:input_type -> diskerase.TBSetReq
+	59, // 71: diskerase.TokenBuckets.List:input_type -> diskerase.TBListReq
+	8,  // 72: diskerase.Workflow.Submit:output_type -> diskerase.WorkResp
+	8,  // 73: diskerase.Workflow.SubmitTemplate:output_type -> diskerase.WorkResp
+	40, // 74: diskerase.Workflow.ListTemplates:output_type -> diskerase.ListTemplatesResp
+	45, // 75: diskerase.Workflow.ListJobs:output_type -> diskerase.ListJobsResp
+	47, // 76: diskerase.Workflow.ListPolicies:output_type -> diskerase.ListPoliciesResp
+	33, // 77: diskerase.Workflow.Plan:output_type -> diskerase.PlanResp
+	21, // 78: diskerase.Workflow.Approve:output_type -> diskerase.ApproveResp
+	13, // 79: diskerase.Workflow.Exec:output_type -> diskerase.ExecResp
+	27, // 80: diskerase.Workflow.Status:output_type -> diskerase.StatusResp
+	27, // 81: diskerase.Workflow.Watch:output_type -> diskerase.StatusResp
+	15, // 82: diskerase.Workflow.Cancel:output_type -> diskerase.CancelResp
+	17, // 83: diskerase.Workflow.Pause:output_type -> diskerase.PauseResp
+	19, // 84: diskerase.Workflow.Resume:output_type -> diskerase.ResumeResp
+	31, // 85: diskerase.Workflow.List:output_type -> diskerase.ListResp
+	24, // 86: diskerase.Workflow.Audit:output_type -> diskerase.AuditResp
+	50, // 87: diskerase.EmergencyStop.SetStatus:output_type -> diskerase.ESSetStatusResp
+	52, // 88: diskerase.EmergencyStop.GetStatus:output_type -> diskerase.ESGetStatusResp
+	54, // 89: diskerase.EmergencyStop.List:output_type -> diskerase.ESListResp
+	58, // 90: diskerase.TokenBuckets.Set:output_type -> diskerase.TBSetResp
+	60, // 91: diskerase.TokenBuckets.List:output_type -> diskerase.TBListResp
+	72, // [72:92] is the sub-list for method output_type
+	52, // [52:72] is the sub-list for method input_type
+	52, // [52:52] is the sub-list for extension type_name
+	52, // [52:52] is the sub-list for extension extendee
+	0,  // [0:52] is the sub-list for field type_name
 }
 
 func init() { file_diskerase_proto_init() }
@@ -901,8 +4740,260 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*WorkResp); i {
+		file_diskerase_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Block); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Job); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ExecResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CancelResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PauseResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResumeResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApproveReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApproveResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Approval); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -913,8 +5004,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Block); i {
+		file_diskerase_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -925,8 +5016,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Job); i {
+		file_diskerase_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -937,8 +5028,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecReq); i {
+		file_diskerase_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WorkflowSummary); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -949,8 +5040,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExecResp); i {
+		file_diskerase_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlanResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -961,8 +5052,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatusReq); i {
+		file_diskerase_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlockPlan); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -973,8 +5064,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatusResp); i {
+		file_diskerase_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobPlan); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -985,8 +5076,8 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BlockStatus); i {
+		file_diskerase_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateParam); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -997,8 +5088,284 @@ func file_diskerase_proto_init() {
 				return nil
 			}
 		}
-		file_diskerase_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*JobStatus); i {
+		file_diskerase_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TemplateInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubmitTemplateReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTemplatesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListTemplatesResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ArgSchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*JobSchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PolicySchema); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListJobsResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoliciesReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPoliciesResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESSetStatusReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESSetStatusResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESGetStatusReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESGetStatusResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESListReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ESListResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBSetReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBSetResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBListReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_diskerase_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TBListResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1015,10 +5382,10 @@ func file_diskerase_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_diskerase_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   12,
+			NumEnums:      7,
+			NumMessages:   57,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_diskerase_proto_goTypes,
 		DependencyIndexes: file_diskerase_proto_depIdxs,
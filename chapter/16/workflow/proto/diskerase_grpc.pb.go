@@ -1,4 +1,8 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.18.0
+// source: diskerase.proto
 
 package diskerase
 
@@ -22,10 +26,44 @@ type WorkflowClient interface {
 	// Submit the work to the server. This will not execute the work, it will
 	// simply verify it against policy and store it for execution.
 	Submit(ctx context.Context, in *WorkReq, opts ...grpc.CallOption) (*WorkResp, error)
+	// SubmitTemplate builds a WorkReq from a template registered on the server and
+	// submits it like Submit().
+	SubmitTemplate(ctx context.Context, in *SubmitTemplateReq, opts ...grpc.CallOption) (*WorkResp, error)
+	// ListTemplates lists the templates registered on the server and the parameters
+	// they take.
+	ListTemplates(ctx context.Context, in *ListTemplatesReq, opts ...grpc.CallOption) (*ListTemplatesResp, error)
+	// ListJobs lists the Jobs registered on the server and the args they take.
+	ListJobs(ctx context.Context, in *ListJobsReq, opts ...grpc.CallOption) (*ListJobsResp, error)
+	// ListPolicies lists the policies registered on the server and the settings
+	// they take.
+	ListPolicies(ctx context.Context, in *ListPoliciesReq, opts ...grpc.CallOption) (*ListPoliciesResp, error)
+	// Plan reports what would happen if the WorkReq was submitted and executed now,
+	// without storing or executing it. This runs Job validation, policies, emergency
+	// stop checks and any checks Jobs do to plan their actions, such as token
+	// availability.
+	Plan(ctx context.Context, in *WorkReq, opts ...grpc.CallOption) (*PlanResp, error)
+	// Approve a WorkReq submitted earlier. Workflows whose policies require
+	// approvals cannot be executed until they have enough of them.
+	Approve(ctx context.Context, in *ApproveReq, opts ...grpc.CallOption) (*ApproveResp, error)
 	// Tell the service to execute a WorkReq submitted earlier.
 	Exec(ctx context.Context, in *ExecReq, opts ...grpc.CallOption) (*ExecResp, error)
 	// Get the status of a WorkReq.
 	Status(ctx context.Context, in *StatusReq, opts ...grpc.CallOption) (*StatusResp, error)
+	// Watch streams the status of a WorkReq each time it changes. The stream ends
+	// after the final status is sent. If the WorkReq has already finished, the
+	// stored status is sent once.
+	Watch(ctx context.Context, in *StatusReq, opts ...grpc.CallOption) (Workflow_WatchClient, error)
+	// Cancel a running WorkReq. Jobs that are running have their context cancelled.
+	Cancel(ctx context.Context, in *CancelReq, opts ...grpc.CallOption) (*CancelResp, error)
+	// Pause a running WorkReq at the next Block or Job boundary.
+	Pause(ctx context.Context, in *PauseReq, opts ...grpc.CallOption) (*PauseResp, error)
+	// Resume a WorkReq that was paused.
+	Resume(ctx context.Context, in *ResumeReq, opts ...grpc.CallOption) (*ResumeResp, error)
+	// List the WorkReqs that have been submitted to the server.
+	List(ctx context.Context, in *ListReq, opts ...grpc.CallOption) (*ListResp, error)
+	// Audit returns the audit records for a WorkReq, which include every RPC that
+	// referenced it and every change in its status.
+	Audit(ctx context.Context, in *AuditReq, opts ...grpc.CallOption) (*AuditResp, error)
 }
 
 type workflowClient struct {
@@ -45,6 +83,60 @@ func (c *workflowClient) Submit(ctx context.Context, in *WorkReq, opts ...grpc.C
 	return out, nil
 }
 
+func (c *workflowClient) SubmitTemplate(ctx context.Context, in *SubmitTemplateReq, opts ...grpc.CallOption) (*WorkResp, error) {
+	out := new(WorkResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/SubmitTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) ListTemplates(ctx context.Context, in *ListTemplatesReq, opts ...grpc.CallOption) (*ListTemplatesResp, error) {
+	out := new(ListTemplatesResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/ListTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) ListJobs(ctx context.Context, in *ListJobsReq, opts ...grpc.CallOption) (*ListJobsResp, error) {
+	out := new(ListJobsResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) ListPolicies(ctx context.Context, in *ListPoliciesReq, opts ...grpc.CallOption) (*ListPoliciesResp, error) {
+	out := new(ListPoliciesResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/ListPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) Plan(ctx context.Context, in *WorkReq, opts ...grpc.CallOption) (*PlanResp, error) {
+	out := new(PlanResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Plan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) Approve(ctx context.Context, in *ApproveReq, opts ...grpc.CallOption) (*ApproveResp, error) {
+	out := new(ApproveResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Approve", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowClient) Exec(ctx context.Context, in *ExecReq, opts ...grpc.CallOption) (*ExecResp, error) {
 	out := new(ExecResp)
 	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Exec", in, out, opts...)
@@ -63,6 +155,83 @@ func (c *workflowClient) Status(ctx context.Context, in *StatusReq, opts ...grpc
 	return out, nil
 }
 
+func (c *workflowClient) Watch(ctx context.Context, in *StatusReq, opts ...grpc.CallOption) (Workflow_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Workflow_ServiceDesc.Streams[0], "/diskerase.Workflow/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Workflow_WatchClient interface {
+	Recv() (*StatusResp, error)
+	grpc.ClientStream
+}
+
+type workflowWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowWatchClient) Recv() (*StatusResp, error) {
+	m := new(StatusResp)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *workflowClient) Cancel(ctx context.Context, in *CancelReq, opts ...grpc.CallOption) (*CancelResp, error) {
+	out := new(CancelResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Cancel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) Pause(ctx context.Context, in *PauseReq, opts ...grpc.CallOption) (*PauseResp, error) {
+	out := new(PauseResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Pause", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) Resume(ctx context.Context, in *ResumeReq, opts ...grpc.CallOption) (*ResumeResp, error) {
+	out := new(ResumeResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Resume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) List(ctx context.Context, in *ListReq, opts ...grpc.CallOption) (*ListResp, error) {
+	out := new(ListResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowClient) Audit(ctx context.Context, in *AuditReq, opts ...grpc.CallOption) (*AuditResp, error) {
+	out := new(AuditResp)
+	err := c.cc.Invoke(ctx, "/diskerase.Workflow/Audit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WorkflowServer is the server API for Workflow service.
 // All implementations must embed UnimplementedWorkflowServer
 // for forward compatibility
@@ -70,10 +239,44 @@ type WorkflowServer interface {
 	// Submit the work to the server. This will not execute the work, it will
 	// simply verify it against policy and store it for execution.
 	Submit(context.Context, *WorkReq) (*WorkResp, error)
+	// SubmitTemplate builds a WorkReq from a template registered on the server and
+	// submits it like Submit().
+	SubmitTemplate(context.Context, *SubmitTemplateReq) (*WorkResp, error)
+	// ListTemplates lists the templates registered on the server and the parameters
+	// they take.
+	ListTemplates(context.Context, *ListTemplatesReq) (*ListTemplatesResp, error)
+	// ListJobs lists the Jobs registered on the server and the args they take.
+	ListJobs(context.Context, *ListJobsReq) (*ListJobsResp, error)
+	// ListPolicies lists the policies registered on the server and the settings
+	// they take.
+	ListPolicies(context.Context, *ListPoliciesReq) (*ListPoliciesResp, error)
+	// Plan reports what would happen if the WorkReq was submitted and executed now,
+	// without storing or executing it. This runs Job validation, policies, emergency
+	// stop checks and any checks Jobs do to plan their actions, such as token
+	// availability.
+	Plan(context.Context, *WorkReq) (*PlanResp, error)
+	// Approve a WorkReq submitted earlier. Workflows whose policies require
+	// approvals cannot be executed until they have enough of them.
+	Approve(context.Context, *ApproveReq) (*ApproveResp, error)
 	// Tell the service to execute a WorkReq submitted earlier.
 	Exec(context.Context, *ExecReq) (*ExecResp, error)
 	// Get the status of a WorkReq.
 	Status(context.Context, *StatusReq) (*StatusResp, error)
+	// Watch streams the status of a WorkReq each time it changes. The stream ends
+	// after the final status is sent. If the WorkReq has already finished, the
+	// stored status is sent once.
+	Watch(*StatusReq, Workflow_WatchServer) error
+	// Cancel a running WorkReq. Jobs that are running have their context cancelled.
+	Cancel(context.Context, *CancelReq) (*CancelResp, error)
+	// Pause a running WorkReq at the next Block or Job boundary.
+	Pause(context.Context, *PauseReq) (*PauseResp, error)
+	// Resume a WorkReq that was paused.
+	Resume(context.Context, *ResumeReq) (*ResumeResp, error)
+	// List the WorkReqs that have been submitted to the server.
+	List(context.Context, *ListReq) (*ListResp, error)
+	// Audit returns the audit records for a WorkReq, which include every RPC that
+	// referenced it and every change in its status.
+	Audit(context.Context, *AuditReq) (*AuditResp, error)
 	mustEmbedUnimplementedWorkflowServer()
 }
 
@@ -84,12 +287,48 @@ type UnimplementedWorkflowServer struct {
 func (UnimplementedWorkflowServer) Submit(context.Context, *WorkReq) (*WorkResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
 }
+func (UnimplementedWorkflowServer) SubmitTemplate(context.Context, *SubmitTemplateReq) (*WorkResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitTemplate not implemented")
+}
+func (UnimplementedWorkflowServer) ListTemplates(context.Context, *ListTemplatesReq) (*ListTemplatesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedWorkflowServer) ListJobs(context.Context, *ListJobsReq) (*ListJobsResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedWorkflowServer) ListPolicies(context.Context, *ListPoliciesReq) (*ListPoliciesResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPolicies not implemented")
+}
+func (UnimplementedWorkflowServer) Plan(context.Context, *WorkReq) (*PlanResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Plan not implemented")
+}
+func (UnimplementedWorkflowServer) Approve(context.Context, *ApproveReq) (*ApproveResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Approve not implemented")
+}
 func (UnimplementedWorkflowServer) Exec(context.Context, *ExecReq) (*ExecResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Exec not implemented")
 }
 func (UnimplementedWorkflowServer) Status(context.Context, *StatusReq) (*StatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
 }
+func (UnimplementedWorkflowServer) Watch(*StatusReq, Workflow_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedWorkflowServer) Cancel(context.Context, *CancelReq) (*CancelResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
+}
+func (UnimplementedWorkflowServer) Pause(context.Context, *PauseReq) (*PauseResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Pause not implemented")
+}
+func (UnimplementedWorkflowServer) Resume(context.Context, *ResumeReq) (*ResumeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Resume not implemented")
+}
+func (UnimplementedWorkflowServer) List(context.Context, *ListReq) (*ListResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedWorkflowServer) Audit(context.Context, *AuditReq) (*AuditResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Audit not implemented")
+}
 func (UnimplementedWorkflowServer) mustEmbedUnimplementedWorkflowServer() {}
 
 // UnsafeWorkflowServer may be embedded to opt out of forward compatibility for this service.
@@ -121,6 +360,114 @@ func _Workflow_Submit_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Workflow_SubmitTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitTemplateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).SubmitTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/SubmitTemplate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).SubmitTemplate(ctx, req.(*SubmitTemplateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/ListTemplates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).ListTemplates(ctx, req.(*ListTemplatesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).ListJobs(ctx, req.(*ListJobsReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_ListPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoliciesReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).ListPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/ListPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).ListPolicies(ctx, req.(*ListPoliciesReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_Plan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Plan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Plan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Plan(ctx, req.(*WorkReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_Approve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Approve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Approve",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Approve(ctx, req.(*ApproveReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Workflow_Exec_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ExecReq)
 	if err := dec(in); err != nil {
@@ -157,6 +504,117 @@ func _Workflow_Status_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Workflow_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatusReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowServer).Watch(m, &workflowWatchServer{stream})
+}
+
+type Workflow_WatchServer interface {
+	Send(*StatusResp) error
+	grpc.ServerStream
+}
+
+type workflowWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowWatchServer) Send(m *StatusResp) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Workflow_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Cancel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Cancel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Cancel(ctx, req.(*CancelReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_Pause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Pause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Pause",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Pause(ctx, req.(*PauseReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_Resume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Resume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Resume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Resume(ctx, req.(*ResumeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).List(ctx, req.(*ListReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Workflow_Audit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowServer).Audit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.Workflow/Audit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowServer).Audit(ctx, req.(*AuditReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Workflow_ServiceDesc is the grpc.ServiceDesc for Workflow service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -168,6 +626,30 @@ var Workflow_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Submit",
 			Handler:    _Workflow_Submit_Handler,
 		},
+		{
+			MethodName: "SubmitTemplate",
+			Handler:    _Workflow_SubmitTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _Workflow_ListTemplates_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Workflow_ListJobs_Handler,
+		},
+		{
+			MethodName: "ListPolicies",
+			Handler:    _Workflow_ListPolicies_Handler,
+		},
+		{
+			MethodName: "Plan",
+			Handler:    _Workflow_Plan_Handler,
+		},
+		{
+			MethodName: "Approve",
+			Handler:    _Workflow_Approve_Handler,
+		},
 		{
 			MethodName: "Exec",
 			Handler:    _Workflow_Exec_Handler,
@@ -176,6 +658,322 @@ var Workflow_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _Workflow_Status_Handler,
 		},
+		{
+			MethodName: "Cancel",
+			Handler:    _Workflow_Cancel_Handler,
+		},
+		{
+			MethodName: "Pause",
+			Handler:    _Workflow_Pause_Handler,
+		},
+		{
+			MethodName: "Resume",
+			Handler:    _Workflow_Resume_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Workflow_List_Handler,
+		},
+		{
+			MethodName: "Audit",
+			Handler:    _Workflow_Audit_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Workflow_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "diskerase.proto",
+}
+
+// EmergencyStopClient is the client API for EmergencyStop service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EmergencyStopClient interface {
+	// SetStatus sets the emergency stop status of a workflow type.
+	SetStatus(ctx context.Context, in *ESSetStatusReq, opts ...grpc.CallOption) (*ESSetStatusResp, error)
+	// GetStatus gets the emergency stop status of a workflow type.
+	GetStatus(ctx context.Context, in *ESGetStatusReq, opts ...grpc.CallOption) (*ESGetStatusResp, error)
+	// List all emergency stop entries.
+	List(ctx context.Context, in *ESListReq, opts ...grpc.CallOption) (*ESListResp, error)
+}
+
+type emergencyStopClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEmergencyStopClient(cc grpc.ClientConnInterface) EmergencyStopClient {
+	return &emergencyStopClient{cc}
+}
+
+func (c *emergencyStopClient) SetStatus(ctx context.Context, in *ESSetStatusReq, opts ...grpc.CallOption) (*ESSetStatusResp, error) {
+	out := new(ESSetStatusResp)
+	err := c.cc.Invoke(ctx, "/diskerase.EmergencyStop/SetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyStopClient) GetStatus(ctx context.Context, in *ESGetStatusReq, opts ...grpc.CallOption) (*ESGetStatusResp, error) {
+	out := new(ESGetStatusResp)
+	err := c.cc.Invoke(ctx, "/diskerase.EmergencyStop/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *emergencyStopClient) List(ctx context.Context, in *ESListReq, opts ...grpc.CallOption) (*ESListResp, error) {
+	out := new(ESListResp)
+	err := c.cc.Invoke(ctx, "/diskerase.EmergencyStop/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EmergencyStopServer is the server API for EmergencyStop service.
+// All implementations must embed UnimplementedEmergencyStopServer
+// for forward compatibility
+type EmergencyStopServer interface {
+	// SetStatus sets the emergency stop status of a workflow type.
+	SetStatus(context.Context, *ESSetStatusReq) (*ESSetStatusResp, error)
+	// GetStatus gets the emergency stop status of a workflow type.
+	GetStatus(context.Context, *ESGetStatusReq) (*ESGetStatusResp, error)
+	// List all emergency stop entries.
+	List(context.Context, *ESListReq) (*ESListResp, error)
+	mustEmbedUnimplementedEmergencyStopServer()
+}
+
+// UnimplementedEmergencyStopServer must be embedded to have forward compatible implementations.
+type UnimplementedEmergencyStopServer struct {
+}
+
+func (UnimplementedEmergencyStopServer) SetStatus(context.Context, *ESSetStatusReq) (*ESSetStatusResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStatus not implemented")
+}
+func (UnimplementedEmergencyStopServer) GetStatus(context.Context, *ESGetStatusReq) (*ESGetStatusResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedEmergencyStopServer) List(context.Context, *ESListReq) (*ESListResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedEmergencyStopServer) mustEmbedUnimplementedEmergencyStopServer() {}
+
+// UnsafeEmergencyStopServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EmergencyStopServer will
+// result in compilation errors.
+type UnsafeEmergencyStopServer interface {
+	mustEmbedUnimplementedEmergencyStopServer()
+}
+
+func RegisterEmergencyStopServer(s grpc.ServiceRegistrar, srv EmergencyStopServer) {
+	s.RegisterService(&EmergencyStop_ServiceDesc, srv)
+}
+
+func _EmergencyStop_SetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ESSetStatusReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyStopServer).SetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.EmergencyStop/SetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyStopServer).SetStatus(ctx, req.(*ESSetStatusReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyStop_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ESGetStatusReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyStopServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.EmergencyStop/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyStopServer).GetStatus(ctx, req.(*ESGetStatusReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EmergencyStop_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ESListReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EmergencyStopServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.EmergencyStop/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EmergencyStopServer).List(ctx, req.(*ESListReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EmergencyStop_ServiceDesc is the grpc.ServiceDesc for EmergencyStop service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EmergencyStop_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "diskerase.EmergencyStop",
+	HandlerType: (*EmergencyStopServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetStatus",
+			Handler:    _EmergencyStop_SetStatus_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _EmergencyStop_GetStatus_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _EmergencyStop_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "diskerase.proto",
+}
+
+// TokenBucketsClient is the client API for TokenBuckets service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TokenBucketsClient interface {
+	// Set drains or refills a token bucket.
+	Set(ctx context.Context, in *TBSetReq, opts ...grpc.CallOption) (*TBSetResp, error)
+	// List all token buckets.
+	List(ctx context.Context, in *TBListReq, opts ...grpc.CallOption) (*TBListResp, error)
+}
+
+type tokenBucketsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTokenBucketsClient(cc grpc.ClientConnInterface) TokenBucketsClient {
+	return &tokenBucketsClient{cc}
+}
+
+func (c *tokenBucketsClient) Set(ctx context.Context, in *TBSetReq, opts ...grpc.CallOption) (*TBSetResp, error) {
+	out := new(TBSetResp)
+	err := c.cc.Invoke(ctx, "/diskerase.TokenBuckets/Set", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenBucketsClient) List(ctx context.Context, in *TBListReq, opts ...grpc.CallOption) (*TBListResp, error) {
+	out := new(TBListResp)
+	err := c.cc.Invoke(ctx, "/diskerase.TokenBuckets/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TokenBucketsServer is the server API for TokenBuckets service.
+// All implementations must embed UnimplementedTokenBucketsServer
+// for forward compatibility
+type TokenBucketsServer interface {
+	// Set drains or refills a token bucket.
+	Set(context.Context, *TBSetReq) (*TBSetResp, error)
+	// List all token buckets.
+	List(context.Context, *TBListReq) (*TBListResp, error)
+	mustEmbedUnimplementedTokenBucketsServer()
+}
+
+// UnimplementedTokenBucketsServer must be embedded to have forward compatible implementations.
+type UnimplementedTokenBucketsServer struct {
+}
+
+func (UnimplementedTokenBucketsServer) Set(context.Context, *TBSetReq) (*TBSetResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Set not implemented")
+}
+func (UnimplementedTokenBucketsServer) List(context.Context, *TBListReq) (*TBListResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedTokenBucketsServer) mustEmbedUnimplementedTokenBucketsServer() {}
+
+// UnsafeTokenBucketsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TokenBucketsServer will
+// result in compilation errors.
+type UnsafeTokenBucketsServer interface {
+	mustEmbedUnimplementedTokenBucketsServer()
+}
+
+func RegisterTokenBucketsServer(s grpc.ServiceRegistrar, srv TokenBucketsServer) {
+	s.RegisterService(&TokenBuckets_ServiceDesc, srv)
+}
+
+func _TokenBuckets_Set_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TBSetReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenBucketsServer).Set(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.TokenBuckets/Set",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenBucketsServer).Set(ctx, req.(*TBSetReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TokenBuckets_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TBListReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenBucketsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/diskerase.TokenBuckets/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenBucketsServer).List(ctx, req.(*TBListReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TokenBuckets_ServiceDesc is the grpc.ServiceDesc for TokenBuckets service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TokenBuckets_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "diskerase.TokenBuckets",
+	HandlerType: (*TokenBucketsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Set",
+			Handler:    _TokenBuckets_Set_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _TokenBuckets_List_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "diskerase.proto",
@@ -0,0 +1,74 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// approveCmd represents the approve command
+var approveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approves a submitted workflow",
+	Long: `Approves a workflow that was submitted but has not been executed. Workflows
+that are subject to the requireApprovals policy cannot be executed until enough
+people other than the submitter have approved them.
+
+The server must use mTLS, as your identity is the Common Name of your certificate.
+Simply pass the single argument, which is the ID of the workflow.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Printf("must pass a single arg, the ID of the workflow to approve")
+			return
+		}
+		comment, _ := cmd.Flags().GetString("comment")
+
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		approvals, err := c.Approve(ctx, args[0], comment)
+		if err != nil {
+			fmt.Printf("could not approve workflow(%s): %s\n", args[0], err)
+			return
+		}
+		fmt.Printf("workflow(%s) approved, it has been approved by:\n", args[0])
+		for _, a := range approvals {
+			fmt.Printf("\t%s at %s\n", a.Approver, a.Time.AsTime().Format(time.RFC1123))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(approveCmd)
+	approveCmd.Flags().String("comment", "", "a comment recorded with your approval")
+}
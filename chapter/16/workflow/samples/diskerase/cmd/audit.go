@@ -0,0 +1,118 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Shows the audit records for a workflow",
+	Long: `Shows who did what to a workflow and when, oldest first. This includes
+every call to the server that referenced the workflow and every change in
+the status of the workflow, its Blocks and its Jobs.
+
+Simply pass the single argument, which is the ID of the workflow.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Printf("must pass a single arg, the ID of the workflow to audit")
+			return
+		}
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		records, err := c.Audit(ctx, args[0])
+		if err != nil {
+			fmt.Printf("could not audit workflow(%s): %s\n", args[0], err)
+			return
+		}
+
+		headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("Time", "Kind", "What", "Caller", "Result", "Error")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		for _, r := range records {
+			tbl.AddRow(
+				r.Time.AsTime().Local().Format(time.RFC3339Nano),
+				r.Kind,
+				auditWhat(r),
+				r.Caller,
+				auditResult(r),
+				r.Error,
+			)
+		}
+		tbl.Print()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
+
+// auditWhat returns what an audit record is about.
+func auditWhat(r *pb.AuditRecord) string {
+	switch r.Kind {
+	case "rpc":
+		return r.Method
+	case "block":
+		return fmt.Sprintf("Block(%d)", r.Block)
+	case "job":
+		return fmt.Sprintf("Block(%d) Job(%d)", r.Block, r.Job)
+	}
+	return "Workflow"
+}
+
+// auditResult returns the result of the call or the change in status of an audit record.
+func auditResult(r *pb.AuditRecord) string {
+	if r.Kind == "rpc" {
+		return r.Code
+	}
+	s := fmt.Sprintf(
+		"%s -> %s",
+		strings.TrimPrefix(r.From.String(), "Status"),
+		strings.TrimPrefix(r.To.String(), "Status"),
+	)
+	if r.WasEsStopped {
+		s += " (emergency stop)"
+	}
+	return s
+}
@@ -0,0 +1,142 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// bucketCmd represents the bucket command
+var bucketCmd = &cobra.Command{
+	Use:   "bucket",
+	Short: "Reads or changes token buckets",
+	Long: `Reads or changes the token buckets used by the tokenBucket Job on the server.
+
+Buckets are defined in the server's buckets.json file. The tokens in each bucket
+are stored, so restarting the server does not refill them. An operator can
+drain a bucket to hold back Jobs waiting for tokens until the next refill, or
+refill a bucket to let them through now.
+`,
+}
+
+// bucketListCmd represents the bucket list command
+var bucketListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists all token buckets",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		infos, err := c.TBList(ctx)
+		if err != nil {
+			fmt.Printf("could not list token buckets: %s\n", err)
+			return
+		}
+		printBuckets(infos...)
+	},
+}
+
+// bucketDrainCmd represents the bucket drain command
+var bucketDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Removes all tokens from a token bucket",
+	Long: `Removes all tokens from a token bucket. Jobs wait for the next refill.
+
+Simply pass the single argument, which is the name of the bucket.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		bucketSet(args, pb.TBAction_TBDrain)
+	},
+}
+
+// bucketRefillCmd represents the bucket refill command
+var bucketRefillCmd = &cobra.Command{
+	Use:   "refill",
+	Short: "Fills a token bucket",
+	Long: `Fills a token bucket to its size, letting Jobs waiting for tokens through.
+
+Simply pass the single argument, which is the name of the bucket.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		bucketSet(args, pb.TBAction_TBRefill)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketCmd)
+	bucketCmd.AddCommand(bucketListCmd, bucketDrainCmd, bucketRefillCmd)
+}
+
+func bucketSet(args []string, action pb.TBAction) {
+	if len(args) != 1 {
+		fmt.Printf("must pass a single arg, the name of the bucket")
+		return
+	}
+	c, err := newClient()
+	if err != nil {
+		fmt.Printf("could not connect to workflow service: %s\n", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	info, err := c.TBSet(ctx, args[0], action)
+	if err != nil {
+		fmt.Printf("could not change token bucket: %s\n", err)
+		return
+	}
+	printBuckets(info)
+}
+
+func printBuckets(infos ...*pb.TBInfo) {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New("Name", "Available", "Size", "Incr", "Interval", "Next Refill")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	for _, info := range infos {
+		tbl.AddRow(
+			info.Name,
+			info.Available,
+			info.Size,
+			info.Incr,
+			info.Interval.AsDuration(),
+			info.NextRefill.AsTime().Local().Format(time.RFC1123),
+		)
+	}
+	tbl.Print()
+}
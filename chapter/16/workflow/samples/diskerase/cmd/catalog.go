@@ -0,0 +1,130 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// jobsCmd represents the jobs command
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Lists the Jobs on the server and the args they take",
+	Long: `Lists the Jobs registered on the server and the args they take.
+
+Pass the name of a Job to only list that Job.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		schemas, err := c.ListJobs(ctx)
+		if err != nil {
+			fmt.Printf("could not list Jobs: %s\n", err)
+			return
+		}
+		for _, s := range schemas {
+			if len(args) > 0 && args[0] != s.Name {
+				continue
+			}
+			printSchema(s.Name, s.Desc, "Arg", s.Args)
+		}
+	},
+}
+
+// policiesCmd represents the policies command
+var policiesCmd = &cobra.Command{
+	Use:   "policies",
+	Short: "Lists the policies on the server and the settings they take",
+	Long: `Lists the policies registered on the server and the settings they take.
+
+Pass the name of a policy to only list that policy.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		schemas, err := c.ListPolicies(ctx)
+		if err != nil {
+			fmt.Printf("could not list policies: %s\n", err)
+			return
+		}
+		for _, s := range schemas {
+			if len(args) > 0 && args[0] != s.Name {
+				continue
+			}
+			printSchema(s.Name, s.Desc, "Setting", s.Settings)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(jobsCmd, policiesCmd)
+}
+
+// printSchema prints the schema of a Job or policy. "kind" is what its args are called.
+func printSchema(name, desc, kind string, args []*pb.ArgSchema) {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	if desc == "" {
+		desc = "does not describe itself"
+	}
+	fmt.Printf("%s: %s\n", columnFmt(name), desc)
+	if len(args) == 0 {
+		fmt.Println()
+		return
+	}
+
+	tbl := table.New(kind, "Type", "Required", "Allowed", "Default", "Desc")
+	tbl.WithHeaderFormatter(headerFmt)
+	for _, a := range args {
+		n := a.Name
+		if a.Prefix {
+			n += "[name]"
+		}
+		tbl.AddRow(n, strings.TrimPrefix(a.Type.String(), "Arg"), a.Required, strings.Join(a.Allowed, ","), a.Default, a.Desc)
+	}
+	tbl.Print()
+	fmt.Println()
+}
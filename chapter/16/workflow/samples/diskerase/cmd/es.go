@@ -0,0 +1,199 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// esCmd represents the es command
+var esCmd = &cobra.Command{
+	Use:   "es",
+	Short: "Reads or changes emergency stop status",
+	Long: `Reads or changes the emergency stop status of workflow types on the server.
+A workflow type is the name of the workflow, such as "SatelliteDiskErase".
+
+Setting a workflow type to "stop" stops all running workflows of that type
+immediately and prevents new ones from being submitted or executed. The change
+is written to the server's es.json file.
+
+A stop can be scoped to a site, a machine pattern or a type of Job. Then only
+the Jobs that match are failed, and the rest of the workflow continues.
+`,
+}
+
+// esListCmd represents the es list command
+var esListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the emergency stop status of all workflow types",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		infos, err := c.ESList(ctx)
+		if err != nil {
+			fmt.Printf("could not list emergency stop status: %s\n", err)
+			return
+		}
+		printES(infos...)
+	},
+}
+
+// esGetCmd represents the es get command
+var esGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Gets the emergency stop status of a workflow type",
+	Long: `Gets the emergency stop status of a workflow type.
+
+Simply pass the single argument, which is the name of the workflow type.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			fmt.Printf("must pass a single arg, the name of the workflow type")
+			return
+		}
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		info, err := c.ESGetStatus(ctx, args[0])
+		if err != nil {
+			fmt.Printf("could not get emergency stop status: %s\n", err)
+			return
+		}
+		printES(info)
+	},
+}
+
+// esSetCmd represents the es set command
+var esSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Sets the emergency stop status of a workflow type",
+	Long: `Sets the emergency stop status of a workflow type to "go" or "stop".
+
+Pass the name of the workflow type and the status, such as:
+	es set SatelliteDiskErase stop
+
+To only stop some Jobs, scope the entry with --site, --machine or --job. A Job
+is stopped if it matches all that are set:
+	es set SatelliteDiskErase stop --site=aba
+	es set SatelliteDiskErase stop --machine='abb-00*' --job=diskErase
+
+Setting a scoped entry to go allows those Jobs to run again.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			fmt.Printf("must pass two args, the name of the workflow type and go or stop")
+			return
+		}
+
+		var status pb.ESStatus
+		switch strings.ToLower(args[1]) {
+		case "go":
+			status = pb.ESStatus_ESGo
+		case "stop":
+			status = pb.ESStatus_ESStop
+		default:
+			fmt.Printf("status must be go or stop, was %q\n", args[1])
+			return
+		}
+
+		info := &pb.ESInfo{Name: args[0], Status: status}
+		info.Site, _ = cmd.Flags().GetString("site")
+		info.Machine, _ = cmd.Flags().GetString("machine")
+		info.Job, _ = cmd.Flags().GetString("job")
+
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := c.ESSetStatus(ctx, info); err != nil {
+			fmt.Printf("could not set emergency stop status: %s\n", err)
+			return
+		}
+		fmt.Printf("emergency stop status of %s set to %s\n", esName(info), strings.ToLower(args[1]))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(esCmd)
+	esCmd.AddCommand(esListCmd, esGetCmd, esSetCmd)
+
+	esSetCmd.Flags().String("site", "", "only stop Jobs for this site")
+	esSetCmd.Flags().String("machine", "", "only stop Jobs for machines matching this pattern, like 'aba-00*'")
+	esSetCmd.Flags().String("job", "", "only stop Jobs of this type, like diskErase")
+}
+
+// esName returns the name of the workflow type with the scope of the entry, if it has one.
+func esName(info *pb.ESInfo) string {
+	var l []string
+	if info.Site != "" {
+		l = append(l, fmt.Sprintf("site(%s)", info.Site))
+	}
+	if info.Machine != "" {
+		l = append(l, fmt.Sprintf("machine(%s)", info.Machine))
+	}
+	if info.Job != "" {
+		l = append(l, fmt.Sprintf("job(%s)", info.Job))
+	}
+	if len(l) == 0 {
+		return info.Name
+	}
+	return info.Name + " " + strings.Join(l, " ")
+}
+
+func printES(infos ...*pb.ESInfo) {
+	headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+	columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+	tbl := table.New("Name", "Status", "Site", "Machine", "Job")
+	tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+	for _, info := range infos {
+		tbl.AddRow(info.Name, strings.TrimPrefix(info.Status.String(), "ES"), info.Site, info.Machine, info.Job)
+	}
+	tbl.Print()
+}
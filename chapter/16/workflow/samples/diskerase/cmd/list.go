@@ -0,0 +1,144 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists workflows that have been submitted",
+	Long: `Lists the workflows that have been submitted to the server, oldest first.
+
+Workflows can be filtered by name, status and submit time. Statuses are
+the names without the "Status" prefix, such as "Running" or "Failed".
+Times are in RFC3339 format, such as "2021-10-01T15:04:05Z".
+
+By default all pages are retrieved, use --limit to stop after some number
+of workflows.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		req, err := listReq(cmd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+
+		tbl := table.New("ID", "Name", "Submitted", "Submitter", "Status", "Had Errors", "Desc")
+		tbl.WithHeaderFormatter(headerFmt).WithFirstColumnFormatter(columnFmt)
+
+		count := 0
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			resp, err := c.List(ctx, req)
+			cancel()
+			if err != nil {
+				fmt.Printf("could not list workflows: %s\n", err)
+				return
+			}
+
+			for _, wf := range resp.Workflows {
+				if limit > 0 && count == limit {
+					break
+				}
+				tbl.AddRow(
+					wf.Id,
+					wf.Name,
+					wf.Submitted.AsTime().Local().Format(time.RFC3339),
+					wf.Submitter,
+					strings.TrimPrefix(wf.Status.String(), "Status"),
+					wf.HadErrors,
+					wf.Desc,
+				)
+				count++
+			}
+			if resp.NextPageToken == "" || (limit > 0 && count == limit) {
+				break
+			}
+			req.PageToken = resp.NextPageToken
+		}
+		tbl.Print()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	listCmd.Flags().String("name", "", "only list workflows with this name")
+	listCmd.Flags().StringSlice("status", nil, "only list workflows with one of these statuses")
+	listCmd.Flags().String("after", "", "only list workflows submitted at or after this time")
+	listCmd.Flags().String("before", "", "only list workflows submitted before this time")
+	listCmd.Flags().Int("limit", 0, "the maximum number of workflows to list, 0 lists all")
+}
+
+// listReq creates a *pb.ListReq from the flags passed to the list command.
+func listReq(cmd *cobra.Command) (*pb.ListReq, error) {
+	req := &pb.ListReq{}
+	req.Name, _ = cmd.Flags().GetString("name")
+
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	for _, s := range statuses {
+		v, ok := pb.Status_value["Status"+s]
+		if !ok {
+			return nil, fmt.Errorf("status(%s) is not a valid status", s)
+		}
+		req.Statuses = append(req.Statuses, pb.Status(v))
+	}
+
+	for flag, field := range map[string]**timestamppb.Timestamp{
+		"after":  &req.SubmittedAfter,
+		"before": &req.SubmittedBefore,
+	} {
+		v, _ := cmd.Flags().GetString(flag)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("--%s(%s) is not a valid RFC3339 time: %s", flag, v, err)
+		}
+		*field = timestamppb.New(t)
+	}
+	return req, nil
+}
@@ -24,16 +24,12 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/client"
 
-	"github.com/fatih/color"
 	"github.com/inancgumus/screen"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
-
-	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
 // protoStatusCmd represents the protoStatus command
@@ -51,7 +47,7 @@ Simply pass the single argument, which is the ID of the workflow.`,
 			fmt.Printf("must pass a single arg, the ID of the workflow to monitor")
 			return
 		}
-		c, err := client.New(rootCmd.Flag("address").Value.String())
+		c, err := newClient()
 		if err != nil {
 			fmt.Printf("could not connect to workflow service: %s\n", err)
 			return
@@ -67,27 +63,30 @@ func init() {
 	rootCmd.AddCommand(protoStatusCmd)
 }
 
-// monitorProto will contact the server every 10 seconds until the workflow with "id"
-// has left the running state.
+// monitorProto will watch the workflow with "id" until it finishes.
 func monitorProto(ctx context.Context, c *client.Workflow, id string) error {
-	for {
-		resp, err := c.Status(ctx, id)
-		if err != nil {
-			return fmt.Errorf("problem getting status of ID(%s): %w", id, err)
+	ch, err := c.Watch(ctx, id)
+	if err != nil {
+		return fmt.Errorf("problem watching ID(%s): %w", id, err)
+	}
+
+	for update := range ch {
+		if update.Err != nil {
+			return fmt.Errorf("problem getting status of ID(%s): %w", id, update.Err)
 		}
+		resp := update.Status
+
 		screen.Clear()
 		screen.MoveTopLeft()
 
-		color.New(color.FgRed).Println("Updates every 10 seconds")
 		fmt.Println(protojson.Format(resp))
-		if resp.Status != pb.Status_StatusRunning {
+		if resp.Status.Done() {
 			fmt.Println("Workflow completed!")
 			return nil
 		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(10 * time.Second):
-		}
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("status stream for ID(%s) ended before the workflow finished", id)
 }
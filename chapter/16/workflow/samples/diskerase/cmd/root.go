@@ -25,6 +25,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/client"
+
 	"github.com/spf13/cobra"
 
 	"github.com/spf13/viper"
@@ -56,12 +58,32 @@ func init() {
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.diskerase.yaml)")
 	rootCmd.PersistentFlags().String("address", "127.0.0.1:8080", "the address the workflow server is at, host:port")
+	rootCmd.PersistentFlags().String("cert", "", "the client certificate file, set with --key and --ca if the server uses mTLS")
+	rootCmd.PersistentFlags().String("key", "", "the client key file")
+	rootCmd.PersistentFlags().String("ca", "", "the CA certificate file used to verify the server")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// newClient creates a client to the workflow server using our flags.
+func newClient() (*client.Workflow, error) {
+	addr := rootCmd.Flag("address").Value.String()
+	cert := rootCmd.Flag("cert").Value.String()
+	key := rootCmd.Flag("key").Value.String()
+	ca := rootCmd.Flag("ca").Value.String()
+
+	if cert == "" && key == "" && ca == "" {
+		return client.New(addr)
+	}
+	conf, err := client.TLSConfig(cert, key, ca)
+	if err != nil {
+		return nil, err
+	}
+	return client.New(addr, client.WithTLS(conf))
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
@@ -24,15 +24,11 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/client"
 
-	"github.com/fatih/color"
 	"github.com/inancgumus/screen"
 	"github.com/spf13/cobra"
-
-	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 )
 
 // statusCmd represents the status command
@@ -50,7 +46,7 @@ Simply pass the single argument, which is the ID of the workflow.
 			fmt.Printf("must pass a single arg, the ID of the workflow to monitor")
 			return
 		}
-		c, err := client.New(rootCmd.Flag("address").Value.String())
+		c, err := newClient()
 		if err != nil {
 			fmt.Printf("could not connect to workflow service: %s\n", err)
 			return
@@ -66,27 +62,30 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 }
 
-// monitor will contact the server every 10 seconds until the workflow with "id"
-// has left the running state.
+// monitor will watch the workflow with "id" until it finishes.
 func monitor(ctx context.Context, c *client.Workflow, id string) error {
-	for {
-		resp, err := c.Status(ctx, id)
-		if err != nil {
-			return fmt.Errorf("problem getting status of ID(%s): %w", id, err)
+	ch, err := c.Watch(ctx, id)
+	if err != nil {
+		return fmt.Errorf("problem watching ID(%s): %w", id, err)
+	}
+
+	for update := range ch {
+		if update.Err != nil {
+			return fmt.Errorf("problem getting status of ID(%s): %w", id, update.Err)
 		}
+		resp := update.Status
+
 		screen.Clear()
 		screen.MoveTopLeft()
 
-		color.New(color.FgRed).Println("Updates every 10 seconds")
 		fmt.Println(resp.CLISummary(id))
-		if resp.Status != pb.Status_StatusRunning {
+		if resp.Status.Done() {
 			fmt.Println("Workflow completed! To retrieve full details, use 'protoStatus' command.")
 			return nil
 		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(10 * time.Second):
-		}
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return fmt.Errorf("status stream for ID(%s) ended before the workflow finished", id)
 }
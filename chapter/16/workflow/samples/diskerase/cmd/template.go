@@ -0,0 +1,159 @@
+/*
+Copyright © 2021 John Doak
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/rodaine/table"
+	"github.com/spf13/cobra"
+)
+
+// templateCmd represents the template command
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Lists or submits workflow templates",
+	Long: `Lists or submits the workflow templates registered on the server.
+
+A template builds a workflow on the server from a few typed parameters, like the
+site to act on, so clients do not need to build the workflow themselves.
+`,
+}
+
+// templateListCmd represents the template list command
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the templates and the parameters they take",
+	Run: func(cmd *cobra.Command, args []string) {
+		c, err := newClient()
+		if err != nil {
+			fmt.Printf("could not connect to workflow service: %s\n", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		infos, err := c.ListTemplates(ctx)
+		if err != nil {
+			fmt.Printf("could not list templates: %s\n", err)
+			return
+		}
+
+		headerFmt := color.New(color.FgGreen, color.Underline).SprintfFunc()
+		columnFmt := color.New(color.FgYellow).SprintfFunc()
+		for _, info := range infos {
+			fmt.Printf("%s: %s\n", columnFmt(info.Name), info.Desc)
+			tbl := table.New("Param", "Type", "Required", "Default", "Desc")
+			tbl.WithHeaderFormatter(headerFmt)
+			for _, p := range info.Params {
+				tbl.AddRow(p.Name, strings.TrimPrefix(p.Type.String(), "Param"), p.Required, p.Default, p.Desc)
+			}
+			tbl.Print()
+			fmt.Println()
+		}
+	},
+}
+
+// templateSubmitCmd represents the template submit command
+var templateSubmitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Submits and executes a workflow from a template",
+	Long: `Asks the server to build a workflow from a template, submit it and execute it.
+
+Pass the name of the template followed by its parameters as name=value. For example:
+
+	template submit satelliteDiskErase site=aap machinesPerBlock=10
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("must pass the name of the template")
+			return
+		}
+		params := map[string]string{}
+		for _, arg := range args[1:] {
+			sp := strings.SplitN(arg, "=", 2)
+			if len(sp) != 2 {
+				fmt.Printf("param %q must be name=value\n", arg)
+				return
+			}
+			params[sp[0]] = sp[1]
+		}
+		if err := submitTemplate(args[0], params); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd, templateSubmitCmd)
+}
+
+// submitTemplate has the server build a workflow from the template called "name", then submits,
+// executes and monitors it.
+func submitTemplate(name string, params map[string]string) error {
+	c, err := newClient()
+	if err != nil {
+		return fmt.Errorf("could not connect to workflow service: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Open our submit.log file to write our submissions
+	f, err := os.OpenFile(submitLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open our submit.log file: %s", err)
+	}
+	defer f.Close()
+
+	fmt.Printf("submitting workflow from template(%s)...\n", name)
+	id, err := c.SubmitTemplate(ctx, name, params)
+	if err != nil {
+		return fmt.Errorf("submission had an issue: %s", err)
+	}
+
+	if _, err := f.Write([]byte("\n" + id)); err != nil {
+		return fmt.Errorf("could not write to our submit.log file: %s", err)
+	}
+
+	fmt.Printf("workflow(%s) accepted, ask server to execute workflow...\n", id)
+
+	// Now execute our attempt.
+	if err := c.Exec(ctx, id); err != nil {
+		fmt.Printf("executing workflow(%s) on the server had an issue: %s\n", id, err)
+		fmt.Printf("if the workflow requires approvals, execute it once approved with: exec %s\n", id)
+		return nil
+	}
+	fmt.Printf("server is executing workflow(%s)\n", id)
+
+	if err := monitor(context.Background(), c, id); err != nil {
+		return fmt.Errorf("problem monitoring workflow(%s): %s", id, err)
+	}
+	return nil
+}
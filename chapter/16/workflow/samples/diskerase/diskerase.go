@@ -22,11 +22,9 @@ THE SOFTWARE.
 package main
 
 import (
-	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/samples/diskerase/cmd"
 )
 
 func main() {
-	sites.Init("../../data")
 	cmd.Execute()
 }
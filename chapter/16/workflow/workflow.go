@@ -1,17 +1,34 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/data/packages/sites"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/audit"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/auth"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/es"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/metrics"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/notify"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/config"
 	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/remoteexec"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/boltdb"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/storage/dir"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/token/buckets"
+	"github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	pb "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/proto"
 
@@ -19,18 +36,41 @@ import (
 	// the service. This is called a side effects import, because we don't actually use it.
 	// The _ before the package indicates it will not be used directly.
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/diskerase"
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/httprequest"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/sleep"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/tokenbucket"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/jobs/register/validatedecom"
 
 	// These register all our policies, exactly like our Jobs work.
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/maintenancewindow"
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/requireapprovals"
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/restrictidentities"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/restrictjobtypes"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/sameargs"
 	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/policy/register/startorend"
+
+	// These register all our workflow templates, which clients can submit with SubmitTemplate().
+	_ "github.com/PacktPublishing/Go-for-DevOps/chapter/16/workflow/internal/service/templates/register/satellitediskerase"
 )
 
 var (
-	addr = flag.String("addr", "127.0.0.1:8080", "The address to run the server on")
+	addr        = flag.String("addr", "127.0.0.1:8080", "The address to run the server on")
+	store       = flag.String("storage", "dir", "The storage backend to use, either \"dir\" for files in a directory or \"bolt\" for a bbolt database")
+	recovery    = flag.String("recovery", "fail", "What to do with workflows that were running when the server stopped, either \"fail\" or \"resume\"")
+	certFile    = flag.String("cert", "", "The server's TLS certificate file. If set with -key and -ca, clients must use mTLS")
+	keyFile     = flag.String("key", "", "The server's TLS key file")
+	caFile      = flag.String("ca", "", "The CA certificate file used to verify client certificates")
+	esAdmins    = flag.String("es-admins", "", "Comma separated identities that can change emergency stop status with the EmergencyStop service. If not set, anyone can")
+	esPath      = flag.String("es", "configs/es.json", "The emergency stop file")
+	policies    = flag.String("policies", "configs/policies.json", "The policy config file")
+	bucketsPath = flag.String("buckets", "configs/buckets.json", "The token bucket config file")
+	tbAdmins    = flag.String("bucket-admins", "", "Comma separated identities that can drain or refill token buckets with the TokenBuckets service. If not set, anyone can")
+	auditLog    = flag.String("audit", filepath.Join(os.TempDir(), "workflows_audit.log"), "The file to append audit records to, one JSON record per line")
+	metricsAddr = flag.String("metrics-addr", "127.0.0.1:8081", "The address to serve Prometheus metrics on at /metrics. If empty, metrics are not served")
+	otelAddr    = flag.String("otel-addr", "", "The address of an OpenTelemetry collector to send traces to with OTLP over gRPC. If empty, traces are not exported")
+	sshKey      = flag.String("ssh-key", "", "The SSH private key file remoteExec Jobs use to connect to the system agent. If empty, the SSH agent at $SSH_AUTH_SOCK is used")
+	packageDir  = flag.String("remote-packages", "packages", "The directory holding the packages remoteExec Jobs can install")
+	webhooks    = flag.String("webhooks", "", "Comma separated URLs to POST a JSON summary to when a workflow completes, fails, is cancelled or is emergency stopped")
 )
 
 // dirMode is simply the mode we create our directories with.
@@ -39,10 +79,23 @@ const dirMode = os.ModeDir | 0700
 func main() {
 	flag.Parse()
 
-	// Read our policy config.
-	config.Init()
+	// Export our traces.
+	shutdownTracing, err := tracing.Init(context.Background(), *otelAddr)
+	if err != nil {
+		panic(err)
+	}
+	defer shutdownTracing(context.Background())
+
+	// Read our policy config and emergency stop config.
+	config.Init(*policies)
+	es.Init(*esPath)
 	sites.Init("data")
 
+	// Say how remoteExec Jobs connect to the system agent on machines.
+	if err := remoteexec.Init(remoteexec.Config{SSHKeyFile: *sshKey, PackageDir: *packageDir}); err != nil {
+		panic(err)
+	}
+
 	// This makes sure we have a place to store workflows.
 	p := filepath.Join(os.TempDir(), "workflows")
 
@@ -61,15 +114,55 @@ func main() {
 	}
 	log.Println("Workflow Storage is at: ", p)
 
+	data, err := newStorage(*store, p)
+	if err != nil {
+		panic(err)
+	}
+	defer data.Close()
+
+	// Read our token buckets, restoring their tokens from storage.
+	buckets.Init(*bucketsPath, data)
+	defer buckets.Data.Close()
+
+	al, err := audit.New(*auditLog)
+	if err != nil {
+		panic(err)
+	}
+	defer al.Close()
+	log.Println("Workflow audit log is at: ", *auditLog)
+
+	// Tell webhooks when workflows finish.
+	notifier, err := notify.New(splitList(*webhooks))
+	if err != nil {
+		panic(err)
+	}
+	defer notifier.Close()
+
 	// Create our implementation of the gRPC service.
-	serv, err := service.New(p)
+	serv, err := service.New(data, al, service.WithNotifier(notifier))
 	if err != nil {
 		panic(err)
 	}
 
+	// Handle any workflows that were running when the server last stopped.
+	if err := serv.Recover(service.Recovery(*recovery)); err != nil {
+		panic(err)
+	}
+
 	// Create a new gRPC service and register our implementation.
-	g := grpc.NewServer()
+	opts, err := serverOpts(al)
+	if err != nil {
+		panic(err)
+	}
+	g := grpc.NewServer(opts...)
 	pb.RegisterWorkflowServer(g, serv)
+	pb.RegisterEmergencyStopServer(g, service.NewEmergencyStop(splitList(*esAdmins)))
+	pb.RegisterTokenBucketsServer(g, service.NewTokenBuckets(splitList(*tbAdmins)))
+
+	// Serve our Prometheus metrics.
+	if *metricsAddr != "" {
+		go serveMetrics(*metricsAddr)
+	}
 
 	// Grab our address on the network and begin listening.
 	lis, err := net.Listen("tcp", *addr)
@@ -81,3 +174,73 @@ func main() {
 	log.Println("Server started on: ", *addr)
 	g.Serve(lis)
 }
+
+// serverOpts returns the options for our gRPC server. If the TLS flags are set, clients must
+// connect with a certificate signed by our CA and the certificate identifies the caller.
+// Every RPC is recorded in "al".
+func serverOpts(al *audit.Log) ([]grpc.ServerOption, error) {
+	opts := []grpc.ServerOption{
+		// The tracing and metrics interceptors run first so that every RPC is traced and counted,
+		// even ones rejected by other interceptors. The auth interceptors must run before the
+		// audit log so that it has the caller's identity.
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(otelgrpc.WithPropagators(propagation.TraceContext{})),
+			metrics.UnaryInterceptor,
+			auth.UnaryInterceptor,
+			al.UnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			otelgrpc.StreamServerInterceptor(otelgrpc.WithPropagators(propagation.TraceContext{})),
+			metrics.StreamInterceptor,
+			auth.StreamInterceptor,
+			al.StreamInterceptor,
+		),
+	}
+
+	switch {
+	case *certFile == "" && *keyFile == "" && *caFile == "":
+		log.Println("WARNING: -cert, -key and -ca are not set, the server is not using TLS and callers have no identity")
+		return opts, nil
+	case *certFile == "" || *keyFile == "" || *caFile == "":
+		return nil, fmt.Errorf("-cert, -key and -ca must all be set to use TLS")
+	}
+
+	conf, err := auth.ServerConfig(*certFile, *keyFile, *caFile)
+	if err != nil {
+		return nil, err
+	}
+	return append(opts, grpc.Creds(credentials.NewTLS(conf))), nil
+}
+
+// serveMetrics serves our Prometheus metrics at http://addr/metrics. If this fails, the server
+// keeps running without metrics.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	log.Println("Metrics served on: ", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("could not serve metrics on %s: %s", addr, err)
+	}
+}
+
+// splitList splits a comma separated list, ignoring empty entries.
+func splitList(s string) []string {
+	var l []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			l = append(l, v)
+		}
+	}
+	return l
+}
+
+// newStorage creates the storage backend named "kind" that stores its data in directory "p".
+func newStorage(kind string, p string) (storage.Data, error) {
+	switch kind {
+	case "dir":
+		return dir.New(p)
+	case "bolt":
+		return boltdb.New(filepath.Join(p, "workflows.db"))
+	}
+	return nil, fmt.Errorf("unknown storage type(%s)", kind)
+}